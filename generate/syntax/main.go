@@ -53,6 +53,8 @@ var replacements = replace.Replacements{
 	{Replacee: `(\d+)`, Replacer: `<digits>`},
 	{Replacee: `(\S+)`, Replacer: `<non-whitespace-characters>`},
 	{Replacee: `([^"]*)`, Replacer: `<any-characters-except-(")>`},
+	{Replacee: `(.+)`, Replacer: `<any-characters>`},
+	{Replacee: `\.`, Replacer: `.`},
 }
 
 var bracketsReplacements = replace.BracketsReplacements{
@@ -35,6 +35,9 @@ const (
 	DurationSeconds = "seconds"
 )
 
+// ErrFieldNotFound is returned by ExtractField when the path does not exist, e.g. a status that is not reported yet
+var ErrFieldNotFound = errors.New("field not found")
+
 var (
 	DefaultRetry = wait.Backoff{
 		Steps:    6,
@@ -98,6 +101,17 @@ func GetExpBackoff(steps int) wait.Backoff {
 	}
 }
 
+func GetDuration(duration int, durationUnits string) (time.Duration, error) {
+	switch durationUnits {
+	case DurationMinutes:
+		return time.Minute * time.Duration(duration), nil
+	case DurationSeconds:
+		return time.Second * time.Duration(duration), nil
+	default:
+		return 0, errors.Errorf("unsupported duration units: '%s'", durationUnits)
+	}
+}
+
 func RetryOnError(backoff *wait.Backoff, retryExpected func(error) bool, fn FuncToRetryWithReturn) (interface{}, error) {
 	var ex, lastErr error
 	var out interface{}
@@ -150,20 +164,37 @@ func ExtractField(data any, path []string) (any, error) {
 			return nil, err
 		}
 
-		dataAtIdx := data.(map[string]any)[maybeArr[0]]
-		switch dataAtIdx := dataAtIdx.(type) {
+		dataMap, ok := data.(map[string]any)
+		if !ok {
+			return nil, errors.Errorf("field '%s' is not in an object", maybeArr[0])
+		}
+		switch dataAtIdx := dataMap[maybeArr[0]].(type) {
 		case []interface{}:
+			if i < 0 || i >= len(dataAtIdx) {
+				return nil, errors.Wrapf(ErrFieldNotFound, "index %d is out of range for field '%s'", i, maybeArr[0])
+			}
+			return ExtractField(dataAtIdx[i], path[1:])
+		case []map[string]any:
+			if i < 0 || i >= len(dataAtIdx) {
+				return nil, errors.Wrapf(ErrFieldNotFound, "index %d is out of range for field '%s'", i, maybeArr[0])
+			}
 			return ExtractField(dataAtIdx[i], path[1:])
+		case nil:
+			return nil, ErrFieldNotFound
 		default:
-			return ExtractField(dataAtIdx.([]map[string]any)[i], path[1:])
+			return nil, errors.Errorf("field '%s' is not an array", maybeArr[0])
 		}
 	}
 
-	for key, val := range data.(map[string]any) {
+	dataMap, ok := data.(map[string]any)
+	if !ok {
+		return nil, errors.Errorf("field '%s' is not in an object", currKey)
+	}
+	for key, val := range dataMap {
 		if key == currKey {
 			return ExtractField(val, path[1:])
 		}
 	}
 
-	return nil, errors.New("field not found")
+	return nil, ErrFieldNotFound
 }
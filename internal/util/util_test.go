@@ -15,7 +15,9 @@ limitations under the License.
 package util
 
 import (
+	"errors"
 	"testing"
+	"time"
 )
 
 var (
@@ -45,9 +47,10 @@ func TestExtractField(t *testing.T) {
 		expectedValue any
 	}
 	tests := []struct {
-		name    string
-		args    args
-		wantErr bool
+		name         string
+		args         args
+		wantErr      bool
+		wantNotFound bool
 	}{
 		{
 			name: "Positive Test",
@@ -72,14 +75,79 @@ func TestExtractField(t *testing.T) {
 				path:          []string{"spec", "path", "doesnt", "exist"},
 				expectedValue: nil,
 			},
+			wantErr:      true,
+			wantNotFound: true,
+		},
+		{
+			name: "Negative Test - index out of range",
+			args: args{
+				data:          sampleResource,
+				path:          []string{"spec", "template", "containers[1]", "name"},
+				expectedValue: nil,
+			},
+			wantErr:      true,
+			wantNotFound: true,
+		},
+		{
+			name: "Negative Test - field of a scalar",
+			args: args{
+				data:          sampleResource,
+				path:          []string{"spec", "template", "containers[0]", "name", "first"},
+				expectedValue: nil,
+			},
 			wantErr: true,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if extractedValue, err := ExtractField(tt.args.data, tt.args.path); (err != nil) != tt.wantErr || extractedValue != tt.args.expectedValue {
+			extractedValue, err := ExtractField(tt.args.data, tt.args.path)
+			if (err != nil) != tt.wantErr || extractedValue != tt.args.expectedValue {
 				t.Errorf("ExtractField() error = %v, wantErr %v", err, tt.wantErr)
 			}
+			if notFound := errors.Is(err, ErrFieldNotFound); notFound != tt.wantNotFound {
+				t.Errorf("ExtractField() error = %v, wantNotFound %v", err, tt.wantNotFound)
+			}
+		})
+	}
+}
+
+func TestGetDuration(t *testing.T) {
+	type args struct {
+		duration      int
+		durationUnits string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    time.Duration
+		wantErr bool
+	}{
+		{
+			name: "Positive Test: minutes",
+			args: args{duration: 2, durationUnits: DurationMinutes},
+			want: 2 * time.Minute,
+		},
+		{
+			name: "Positive Test: seconds",
+			args: args{duration: 30, durationUnits: DurationSeconds},
+			want: 30 * time.Second,
+		},
+		{
+			name:    "Negative Test: unsupported units",
+			args:    args{duration: 1, durationUnits: "hours"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetDuration(tt.args.duration, tt.args.durationUnits)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetDuration() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("GetDuration() = %v, want %v", got, tt.want)
+			}
 		})
 	}
 }
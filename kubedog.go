@@ -16,6 +16,9 @@ package kubedog
 
 //go:generate go run generate/syntax/main.go
 import (
+	"context"
+	"sync"
+
 	"github.com/cucumber/godog"
 	aws "github.com/keikoproj/kubedog/pkg/aws"
 	"github.com/keikoproj/kubedog/pkg/generic"
@@ -27,13 +30,18 @@ type Test struct {
 	scenario      *godog.ScenarioContext
 	KubeClientSet kube.ClientSet
 	AwsClientSet  aws.ClientSet
+	lock          sync.Mutex
 }
 
 /*
 SetScenario sets the ScenarioContext and contains the steps definition, should be called in the InitializeScenario function required by godog.
+Each scenario keeps its state apart in the KubeClientSet, steps and hooks find it in the context godog gives them.
 Check https://github.com/keikoproj/kubedog/blob/master/docs/syntax.md for steps syntax details.
 */
 func (kdt *Test) SetScenario(scenario *godog.ScenarioContext) {
+	// godog initializes concurrent scenarios concurrently, the steps are registered for one scenario at a time
+	kdt.lock.Lock()
+	defer kdt.lock.Unlock()
 	kdt.scenario = scenario
 	//syntax-generation:begin
 	//syntax-generation:title-0:Generic steps
@@ -44,47 +52,122 @@ func (kdt *Test) SetScenario(scenario *godog.ScenarioContext) {
 	kdt.scenario.Step(`^((?:a )?Kubernetes cluster|(?:there are )?(?:valid )?Kubernetes Credentials)$`, kdt.KubeClientSet.DiscoverClients)
 	kdt.scenario.Step(`^(?:the )?Kubernetes cluster should be (created|deleted|upgraded)$`, kdt.KubeClientSet.KubernetesClusterShouldBe)
 	kdt.scenario.Step(`^(?:I )?store (?:the )?current time as ([^"]*)$`, kdt.KubeClientSet.SetTimestamp)
+	kdt.scenario.Step(`^(?:I )?create (?:an |the )?ephemeral namespace$`, kdt.KubeClientSet.CreateEphemeralNamespace)
+	kdt.scenario.Step(`^(?:I )?use (?:the )?namespace (\S+)$`, kdt.KubeClientSet.UseNamespace)
 	//syntax-generation:title-1:Unstructured Resources
 	kdt.scenario.Step(`^(?:I )?(create|submit|delete|update|upsert) (?:the )?resource (\S+)$`, kdt.KubeClientSet.ResourceOperation)
 	kdt.scenario.Step(`^(?:I )?(create|submit|delete|update|upsert) (?:the )?resource (\S+) in (?:the )?([^"]*) namespace$`, kdt.KubeClientSet.ResourceOperationInNamespace)
 	kdt.scenario.Step(`^(?:I )?(create|submit|delete|update|upsert) (?:the )?resources in (\S+)$`, kdt.KubeClientSet.ResourcesOperation)
 	kdt.scenario.Step(`^(?:I )?(create|submit|delete|update|upsert) (?:the )?resources in (\S+) in (?:the )?([^"]*) namespace$`, kdt.KubeClientSet.ResourcesOperationInNamespace)
+	kdt.scenario.Step(`^(?:I )?(create|submit|delete|update|upsert) (?:the )?resources in (?:the )?directory (\S+)$`, kdt.KubeClientSet.ResourcesInDirectoryOperation)
 	kdt.scenario.Step(`^(?:I )?(create|submit|delete|update|upsert) (?:the )?resource (\S+), the operation should (succeed|fail)$`, kdt.KubeClientSet.ResourceOperationWithResult)
 	kdt.scenario.Step(`^(?:I )?(create|submit|delete|update|upsert) (?:the )?resource (\S+) in (?:the )?([^"]*) namespace, the operation should (succeed|fail)$`, kdt.KubeClientSet.ResourceOperationWithResultInNamespace)
-	kdt.scenario.Step(`^(?:the )?resource ([^"]*) should be (created|deleted)$`, kdt.KubeClientSet.ResourceShouldBe)
+	kdt.scenario.Step(`^(?:the )?resource (\S+) in (?:the )?namespace (\S+) should be (created|deleted)$`, kdt.KubeClientSet.ResourceShouldBeInNamespace)
+	kdt.scenario.Step(`^(?:the )?resource (\S+) should be (created|deleted)$`, kdt.KubeClientSet.ResourceShouldBe)
+	kdt.scenario.Step(`^(?:the )?resource (\S+) in (?:the )?namespace (\S+) (?:should )?converge to selector (\S+)$`, kdt.KubeClientSet.ResourceShouldConvergeToSelectorInNamespace)
 	kdt.scenario.Step(`^(?:the )?resource (\S+) (?:should )?converge to selector (\S+)$`, kdt.KubeClientSet.ResourceShouldConvergeToSelector)
+	kdt.scenario.Step(`^(?:the )?resource (\S+) in (?:the )?namespace (\S+) (?:should )?converge to field (\S+)$`, kdt.KubeClientSet.ResourceShouldConvergeToFieldInNamespace)
 	kdt.scenario.Step(`^(?:the )?resource (\S+) (?:should )?converge to field (\S+)$`, kdt.KubeClientSet.ResourceShouldConvergeToField)
-	kdt.scenario.Step(`^(?:the )?resource ([^"]*) condition ([^"]*) should be ([^"]*)$`, kdt.KubeClientSet.ResourceConditionShouldBe)
-	kdt.scenario.Step(`^(?:I )?update (?:the )?resource ([^"]*) with ([^"]*) set to ([^"]*)$`, kdt.KubeClientSet.UpdateResourceWithField)
+	kdt.scenario.Step(`^(?:the )?resource (\S+) in (?:the )?namespace (\S+) condition ([^"]*) should be ([^"]*)$`, kdt.KubeClientSet.ResourceConditionShouldBeInNamespace)
+	kdt.scenario.Step(`^(?:the )?resource (\S+) condition ([^"]*) should be ([^"]*)$`, kdt.KubeClientSet.ResourceConditionShouldBe)
+	kdt.scenario.Step(`^(?:I )?update (?:the )?resource (\S+) in (?:the )?namespace (\S+) with ([^"]*) set to ([^"]*)$`, kdt.KubeClientSet.UpdateResourceWithFieldInNamespace)
+	kdt.scenario.Step(`^(?:I )?update (?:the )?resource (\S+) with ([^"]*) set to ([^"]*)$`, kdt.KubeClientSet.UpdateResourceWithField)
+	kdt.scenario.Step(`^(all|any) (?:of )?(?:the )?resources in (\S+)(?: in (?:the )?namespace (\S+))? should be (created|deleted)$`, kdt.KubeClientSet.ResourcesShouldBe)
+	kdt.scenario.Step(`^(all|any) (?:of )?(?:the )?resources in (\S+)(?: in (?:the )?namespace (\S+))? (?:should )?converge to selector (\S+)$`, kdt.KubeClientSet.ResourcesShouldConvergeToSelector)
+	kdt.scenario.Step(`^(all|any) (?:of )?(?:the )?resources in (\S+)(?: in (?:the )?namespace (\S+))? (?:should )?converge to field (\S+)$`, kdt.KubeClientSet.ResourcesShouldConvergeToField)
+	kdt.scenario.Step(`^(all|any) (?:of )?(?:the )?resources in (\S+)(?: in (?:the )?namespace (\S+))? condition ([^"]*) should be ([^"]*)$`, kdt.KubeClientSet.ResourcesConditionShouldBe)
+	kdt.scenario.Step(`^(?:the )?(?:CRD|CustomResourceDefinition|custom resource definition) (\S+) should be established$`, kdt.KubeClientSet.CustomResourceDefinitionShouldBeEstablished)
+	kdt.scenario.Step(`^(?:I )?delete (?:all )?(?:the )?(\S+) with (selector|label selector|field selector) (\S+)(?: in (?:the )?namespace (\S+))?$`, kdt.KubeClientSet.DeleteResourcesWithSelector)
+	kdt.scenario.Step(`^(?:the )?count of (\S+) with (selector|label selector|field selector) (\S+)(?: in (?:the )?namespace (\S+))? should be (\d+)$`, kdt.KubeClientSet.ResourcesWithSelectorCountShouldBe)
+	kdt.scenario.Step(`^(at least|at most|exactly) (\d+) (\S+) with (selector|label selector|field selector) (\S+)(?: in (?:the )?namespace (\S+))? should be found$`, kdt.KubeClientSet.ResourcesWithSelectorShouldReachCount)
+	kdt.scenario.Step(`^all (\S+) with (selector|label selector|field selector) (\S+)(?: in (?:the )?namespace (\S+))? (?:should )?converge to field (\S+)$`, kdt.KubeClientSet.ResourcesWithSelectorShouldConvergeToField)
+	kdt.scenario.Step(`^(?:the )?(\S+) named (\S+)(?: in (?:the )?namespace (\S+))? should have (labels|annotations) (.+)$`, kdt.KubeClientSet.ResourceShouldHaveMetadata)
+	kdt.scenario.Step(`^all (?:the )?(\S+) with (selector|label selector|field selector) (\S+)(?: in (?:the )?namespace (\S+))? should have (labels|annotations) (.+)$`, kdt.KubeClientSet.ResourcesWithSelectorShouldHaveMetadata)
 	kdt.scenario.Step(`^(?:I )?verify InstanceGroups (?:are )?in "ready" state$`, kdt.KubeClientSet.VerifyInstanceGroups)
 	//syntax-generation:title-1:Structured Resources
 	//syntax-generation:title-2:Pods
-	kdt.scenario.Step(`^(?:I )?get (?:the )?pods in namespace ([^"]*)$`, kdt.KubeClientSet.ListPods)
-	kdt.scenario.Step(`^(?:I )?get (?:the )?pods in namespace ([^"]*) with selector (\S+)$`, kdt.KubeClientSet.ListPodsWithSelector)
-	kdt.scenario.Step(`^(?:the )?pods in namespace ([^"]*) with selector (\S+) have restart count less than (\d+)$`, kdt.KubeClientSet.PodsWithSelectorHaveRestartCountLessThan)
-	kdt.scenario.Step(`^(some|all) pods in namespace (\S+) with selector (\S+) have "([^"]*)" in logs since ([^"]*) time$`, kdt.KubeClientSet.SomeOrAllPodsInNamespaceWithSelectorHaveStringInLogsSinceTime)
-	kdt.scenario.Step(`^some pods in namespace (\S+) with selector (\S+) don't have "([^"]*)" in logs since ([^"]*) time$`, kdt.KubeClientSet.SomePodsInNamespaceWithSelectorDontHaveStringInLogsSinceTime)
-	kdt.scenario.Step(`^(?:the )?pods in namespace (\S+) with selector (\S+) have no errors in logs since ([^"]*) time$`, kdt.KubeClientSet.PodsInNamespaceWithSelectorHaveNoErrorsInLogsSinceTime)
-	kdt.scenario.Step(`^(?:the )?pods in namespace (\S+) with selector (\S+) have some errors in logs since ([^"]*) time$`, kdt.KubeClientSet.PodsInNamespaceWithSelectorHaveSomeErrorsInLogsSinceTime)
-	kdt.scenario.Step(`^(?:all )?(?:the )?(?:pod|pods) in (?:the )?namespace (\S+) with (?:the )?label selector (\S+) (?:should )?(?:converge to|have) (?:the )?field selector (\S+)$`, kdt.KubeClientSet.PodsInNamespaceWithLabelSelectorConvergeToFieldSelector)
-	kdt.scenario.Step(`^(?:the )?pods in namespace (\S+) with selector (\S+) should have labels (\S+)$`, kdt.KubeClientSet.PodsInNamespaceWithSelectorShouldHaveLabels)
-	kdt.scenario.Step(`^(?:the )?pod (\S+) in namespace (\S+) should have labels (\S+)$`, kdt.KubeClientSet.PodInNamespaceShouldHaveLabels)
+	kdt.scenario.Step(`^(?:I )?get (?:the )?pods(?: in namespace ([^"]*))?$`, kdt.KubeClientSet.ListPods)
+	kdt.scenario.Step(`^(?:I )?get (?:the )?pods(?: in namespace ([^"]*))? with selector (\S+)$`, kdt.KubeClientSet.ListPodsWithSelector)
+	kdt.scenario.Step(`^(?:the )?pods(?: in namespace ([^"]*))? with selector (\S+) have restart count less than (\d+)$`, kdt.KubeClientSet.PodsWithSelectorHaveRestartCountLessThan)
+	kdt.scenario.Step(`^(at least|at most|exactly) (\d+) pod(?:s)?(?: in namespace (\S+))? with selector (\S+) should be (ready|running|succeeded)$`, kdt.KubeClientSet.PodsWithSelectorCountShouldBe)
+	kdt.scenario.Step(`^(some|all) pods(?: in namespace (\S+))? with selector (\S+) have "([^"]*)" in logs since ([^"]*) time$`, kdt.KubeClientSet.SomeOrAllPodsInNamespaceWithSelectorHaveStringInLogsSinceTime)
+	kdt.scenario.Step(`^some pods(?: in namespace (\S+))? with selector (\S+) don't have "([^"]*)" in logs since ([^"]*) time$`, kdt.KubeClientSet.SomePodsInNamespaceWithSelectorDontHaveStringInLogsSinceTime)
+	kdt.scenario.Step(`^(?:the )?pods(?: in namespace (\S+))? with selector (\S+) have no errors in logs since ([^"]*) time$`, kdt.KubeClientSet.PodsInNamespaceWithSelectorHaveNoErrorsInLogsSinceTime)
+	kdt.scenario.Step(`^(?:the )?pods(?: in namespace (\S+))? with selector (\S+) have some errors in logs since ([^"]*) time$`, kdt.KubeClientSet.PodsInNamespaceWithSelectorHaveSomeErrorsInLogsSinceTime)
+	kdt.scenario.Step(`^(?:the )?pods(?: in namespace (\S+))? with selector (\S+) should have (at least|at most|exactly) (\d+) log line(?:s)? matching (substring|regex|json query) (.+) since (\S+) time$`, kdt.KubeClientSet.PodsInNamespaceWithSelectorShouldHaveLogLinesMatching)
+	kdt.scenario.Step(`^(?:the )?(current|previous) (?:instance of )?container (\S+) of pods(?: in namespace (\S+))? with selector (\S+) should have (at least|at most|exactly) (\d+) log line(?:s)? matching (substring|regex|json query) (.+) since (\S+) time$`, kdt.KubeClientSet.ContainerInPodsInNamespaceWithSelectorShouldHaveLogLinesMatching)
+	kdt.scenario.Step(`^(?:the )?pods(?: in namespace (\S+))? with selector (\S+) should log (?:a )?line matching (substring|regex|json query) (.+) since (\S+) time within (\d+) (minutes|seconds)$`, kdt.KubeClientSet.PodsInNamespaceWithSelectorShouldLogLineMatching)
+	kdt.scenario.Step(`^(?:I )?start capturing logs of pods with selector (\S+)(?: in namespace (\S+))?$`, kdt.KubeClientSet.StartCapturingLogs)
+	kdt.scenario.Step(`^(?:the )?pods(?: in namespace (\S+))? with selector (\S+) should be in phase (Pending|Running|Succeeded|Failed|Unknown)$`, kdt.KubeClientSet.PodsInNamespaceWithSelectorShouldBeInPhase)
+	kdt.scenario.Step(`^(?:the )?container (\S+) of pods(?: in namespace (\S+))? with selector (\S+) should have state reason (\S+)$`, kdt.KubeClientSet.ContainerInPodsInNamespaceWithSelectorShouldHaveStateReason)
+	kdt.scenario.Step(`^(?:the )?container (\S+) of pods(?: in namespace (\S+))? with selector (\S+) should be (ready|not ready)$`, kdt.KubeClientSet.ContainerInPodsInNamespaceWithSelectorShouldBeReady)
+	kdt.scenario.Step(`^(?:the )?pods(?: in namespace (\S+))? with selector (\S+) should be scheduled on nodes with selector (\S+)$`, kdt.KubeClientSet.PodsInNamespaceWithSelectorShouldBeScheduledOnNodesWithSelector)
+	kdt.scenario.Step(`^(?:the )?pods(?: in namespace (\S+))? with selector (\S+) should be scheduled on nodes satisfying their node selector and affinity$`, kdt.KubeClientSet.PodsInNamespaceWithSelectorShouldSatisfyNodeAffinity)
+	kdt.scenario.Step(`^(?:the )?pods(?: in namespace (\S+))? with selector (\S+) should have QoS class (Guaranteed|Burstable|BestEffort)$`, kdt.KubeClientSet.PodsInNamespaceWithSelectorShouldHaveQOSClass)
+	kdt.scenario.Step(`^(?:the )?container (\S+) of pods(?: in namespace (\S+))? with selector (\S+) should be running image (\S+)$`, kdt.KubeClientSet.ContainerInPodsInNamespaceWithSelectorShouldRunImage)
+	kdt.scenario.Step(`^(?:all )?(?:the )?(?:pod|pods)(?: in (?:the )?namespace (\S+))? with (?:the )?label selector (\S+) (?:should )?(?:converge to|have) (?:the )?field selector (\S+)$`, kdt.KubeClientSet.PodsInNamespaceWithLabelSelectorConvergeToFieldSelector)
+	kdt.scenario.Step(`^(?:the )?pods(?: in namespace (\S+))? with selector (\S+) should have labels (.+)$`, kdt.KubeClientSet.PodsInNamespaceWithSelectorShouldHaveLabels)
+	kdt.scenario.Step(`^(?:the )?pod (\S+)(?: in namespace (\S+))? should have labels (.+)$`, kdt.KubeClientSet.PodInNamespaceShouldHaveLabels)
+	kdt.scenario.Step(`^(?:I )?evict (?:the )?pod (\S+)(?: in namespace (\S+))?, the eviction should be (allowed|rejected)$`, kdt.KubeClientSet.EvictPodInNamespace)
+	kdt.scenario.Step(`^(?:I )?evict (\d+) pod(?:s)?(?: in namespace (\S+))? with selector (\S+), the eviction should be (allowed|rejected)$`, kdt.KubeClientSet.EvictPodsInNamespaceWithSelector)
+	kdt.scenario.Step(`^(?:I )?(gracefully|forcefully) kill (\d+) (?:random )?(pod|pods|percent of pods)(?: in namespace (\S+))? with selector (\S+)$`, kdt.KubeClientSet.KillRandomPodsInNamespaceWithSelector)
+	kdt.scenario.Step(`^(?:the )?pods(?: in namespace (\S+))? with selector (\S+) should recover within (\d+) (minutes|seconds)$`, kdt.KubeClientSet.PodsInNamespaceWithSelectorShouldRecover)
+	kdt.scenario.Step(`^(?:the )?pods(?: in namespace (\S+))? with selector (\S+) should recover within (\d+) (minutes|seconds) with restart count less than (\d+)$`, kdt.KubeClientSet.PodsInNamespaceWithSelectorShouldRecoverWithRestartCountLessThan)
+	//syntax-generation:title-2:Nodes
+	kdt.scenario.Step(`^(?:I )?(cordon|uncordon) (?:the )?node (\S+)$`, kdt.KubeClientSet.CordonNode)
+	kdt.scenario.Step(`^(?:I )?(cordon|uncordon) (?:the )?nodes with selector (\S+)$`, kdt.KubeClientSet.CordonNodesWithSelector)
+	kdt.scenario.Step(`^(?:I )?drain (?:the )?node (\S+) within (\d+) (minutes|seconds)$`, kdt.KubeClientSet.DrainNode)
+	kdt.scenario.Step(`^(?:I )?drain (?:the )?nodes with selector (\S+) within (\d+) (minutes|seconds)$`, kdt.KubeClientSet.DrainNodesWithSelector)
+	kdt.scenario.Step(`^(?:I )?(add|remove) (?:the )?taint (\S+) (?:to|from) (?:the )?node (\S+)$`, kdt.KubeClientSet.NodeTaintOperation)
+	kdt.scenario.Step(`^(?:I )?(add|remove) (?:the )?label (\S+) (?:to|from) (?:the )?node (\S+)$`, kdt.KubeClientSet.NodeLabelOperation)
+	kdt.scenario.Step(`^(?:the )?node (\S+) condition (\S+) should be (True|False|Unknown)$`, kdt.KubeClientSet.NodeConditionShouldBe)
+	kdt.scenario.Step(`^(?:the )?nodes with selector (\S+) condition (\S+) should be (True|False|Unknown)$`, kdt.KubeClientSet.NodesWithSelectorConditionShouldBe)
+	kdt.scenario.Step(`^(?:the )?nodes with selector (\S+) should have kubelet version (\S+)$`, kdt.KubeClientSet.NodesWithSelectorShouldHaveKubeletVersion)
+	kdt.scenario.Step(`^(?:the )?nodes with selector (\S+) should have (at least|at most|exactly) (\S+) (allocatable|capacity) (cpu|memory|pods|ephemeral-storage)$`, kdt.KubeClientSet.NodesWithSelectorShouldHaveResource)
+	kdt.scenario.Step(`^(?:the )?nodes with selector (\S+) should be spread across (at least|at most|exactly) (\d+) zone(?:s)?$`, kdt.KubeClientSet.NodesWithSelectorShouldBeSpreadAcrossZones)
+	//syntax-generation:title-2:Network
+	kdt.scenario.Step(`^pods with selector (\S+)(?: in namespace (\S+))? should be (allowed|denied) to connect to (service|pods with selector) (\S+)(?: in namespace (\S+))? on port (\d+)$`, kdt.KubeClientSet.ConnectivityShouldBe)
+	kdt.scenario.Step(`^(?:the )?network connectivity should be:$`, kdt.KubeClientSet.ConnectivityMatrixShouldBe)
 	//syntax-generation:title-2:Others
-	kdt.scenario.Step(`^(?:I )?(create|submit|update) (?:the )?secret (\S+) in namespace (\S+) from (?:environment variable )?(\S+)$`, kdt.KubeClientSet.SecretOperationFromEnvironmentVariable)
-	kdt.scenario.Step(`^(?:I )?delete (?:the )?secret (\S+) in namespace (\S+)$`, kdt.KubeClientSet.SecretDelete)
+	kdt.scenario.Step(`^(?:I )?(create|submit|update|upsert) (?:the )?secret (\S+)(?: in namespace (\S+))? from (?:environment variable )?(\S+)$`, kdt.KubeClientSet.SecretOperationFromEnvironmentVariable)
+	kdt.scenario.Step(`^(?:I )?(create|submit|update|upsert) (?:the )?secret (\S+)(?: in namespace (\S+))? with:$`, kdt.KubeClientSet.SecretOperationFromTable)
+	kdt.scenario.Step(`^(?:I )?(create|submit|update|upsert) (?:the )?secret (\S+) of type (Opaque|kubernetes\.io/tls|kubernetes\.io/dockerconfigjson|kubernetes\.io/basic-auth)(?: in namespace (\S+))? with:$`, kdt.KubeClientSet.SecretOfTypeOperationFromTable)
+	kdt.scenario.Step(`^(?:I )?(create|submit|update|upsert) (?:the )?self-signed tls secret (\S+)(?: in namespace (\S+))? for (?:host(?:s)? )?(\S+)$`, kdt.KubeClientSet.SecretOperationWithSelfSignedCertificate)
+	kdt.scenario.Step(`^(?:I )?delete (?:the )?secret (\S+)(?: in namespace (\S+))?$`, kdt.KubeClientSet.SecretDelete)
 	kdt.scenario.Step(`^(\d+) node(?:s)? with selector (\S+) should be (found|ready)$`, kdt.KubeClientSet.NodesWithSelectorShouldBe)
+	kdt.scenario.Step(`^(at least|at most|exactly) (\d+) job(?:s)?(?: in namespace (\S+))? with selector (\S+) should be (completed)$`, kdt.KubeClientSet.JobsWithSelectorCountShouldBe)
+	kdt.scenario.Step(`^(?:I )?create (?:the )?job (\S+) from (?:the )?cronjob (\S+)(?: in namespace (\S+))?$`, kdt.KubeClientSet.CreateJobFromCronJob)
+	kdt.scenario.Step(`^(?:the )?job (\S+)(?: in namespace (\S+))? should (complete|fail)$`, kdt.KubeClientSet.JobShouldFinish)
+	kdt.scenario.Step(`^(?:the )?job (\S+)(?: in namespace (\S+))? should have (at least|at most|exactly) (\d+) (active|succeeded|failed) pod(?:s)?$`, kdt.KubeClientSet.JobShouldHavePodCount)
+	kdt.scenario.Step(`^(?:the )?job (\S+)(?: in namespace (\S+))? should fail after exceeding its backoff limit$`, kdt.KubeClientSet.JobShouldExceedBackoffLimit)
+	kdt.scenario.Step(`^(?:I )?(suspend|resume) (?:the )?cronjob (\S+)(?: in namespace (\S+))?$`, kdt.KubeClientSet.CronJobOperation)
+	kdt.scenario.Step(`^(at least|at most|exactly) (\d+) persistentvolumeclaim(?:s)?(?: in namespace (\S+))? with selector (\S+) should be (bound)$`, kdt.KubeClientSet.PersistentVolumeClaimsWithSelectorCountShouldBe)
+	kdt.scenario.Step(`^(?:the )?(?:pdb|poddisruptionbudget) (\S+)(?: in namespace (\S+))? should have (at least|at most|exactly) (\d+) (disruptionsAllowed|currentHealthy|desiredHealthy|expectedPods)$`, kdt.KubeClientSet.PodDisruptionBudgetStatusShouldBe)
+	kdt.scenario.Step(`^(?:the )?(?:hpa|horizontalpodautoscaler) (\S+)(?: in namespace (\S+))? should have (at least|at most|exactly) (\d+) (currentReplicas|desiredReplicas)$`, kdt.KubeClientSet.HorizontalPodAutoscalerReplicasShouldBe)
+	kdt.scenario.Step(`^(?:the )?(?:hpa|horizontalpodautoscaler) (\S+)(?: in namespace (\S+))? should scale (up|down) within (\d+) (minutes|seconds)$`, kdt.KubeClientSet.HorizontalPodAutoscalerShouldScale)
+	kdt.scenario.Step(`^(?:the )?(?:hpa|horizontalpodautoscaler) (\S+)(?: in namespace (\S+))? condition (AbleToScale|ScalingActive|ScalingLimited) should be (True|False|Unknown)$`, kdt.KubeClientSet.HorizontalPodAutoscalerConditionShouldBe)
+	kdt.scenario.Step(`^(?:the )?service (\S+)(?: in namespace (\S+))? should have (at least|at most|exactly) (\d+) ready endpoint(?:s)?$`, kdt.KubeClientSet.ServiceShouldHaveReadyEndpoints)
+	kdt.scenario.Step(`^(?:the )?service (\S+)(?: in namespace (\S+))? should select (?:the )?pods with selector (\S+)$`, kdt.KubeClientSet.ServiceShouldSelectPodsWithSelector)
+	kdt.scenario.Step(`^(?:the )?service (\S+)(?: in namespace (\S+))? should have (?:a )?load balancer ingress$`, kdt.KubeClientSet.ServiceShouldHaveLoadBalancerIngress)
+	kdt.scenario.Step(`^(?:the )?service (\S+)(?: in namespace (\S+))? should be of type (ClusterIP|NodePort|LoadBalancer|ExternalName)$`, kdt.KubeClientSet.ServiceShouldBeOfType)
+	kdt.scenario.Step(`^(?:the )?service (\S+)(?: in namespace (\S+))? should have (TCP|UDP|SCTP) port (\d+) with target port (\S+)$`, kdt.KubeClientSet.ServiceShouldHavePort)
+	kdt.scenario.Step(`^(?:the )?service (\S+)(?: in namespace (\S+))? should have node port (\d+) for (TCP|UDP|SCTP) port (\d+)$`, kdt.KubeClientSet.ServiceShouldHaveNodePort)
 	kdt.scenario.Step(`^(?:the )?(deployment|hpa|horizontalpodautoscaler|service|pdb|poddisruptionbudget|sa|serviceaccount|configmap) ([^"]*) (is|is not) in namespace ([^"]*)$`, kdt.KubeClientSet.ResourceInNamespace)
 	kdt.scenario.Step(`^(?:I )?scale (?:the )?deployment ([^"]*) in namespace ([^"]*) to (\d+)$`, kdt.KubeClientSet.ScaleDeployment)
 	kdt.scenario.Step(`^(?:I )?validate Prometheus Statefulset ([^"]*) in namespace ([^"]*) has volumeClaimTemplates name ([^"]*)$`, kdt.KubeClientSet.ValidatePrometheusVolumeClaimTemplatesName)
-	kdt.scenario.Step(`^(?:I )?get (?:the )?nodes list$`, kdt.KubeClientSet.ListNodes)
-	kdt.scenario.Step(`^(?:the )?daemonset ([^"]*) is running in namespace ([^"]*)$`, kdt.KubeClientSet.DaemonSetIsRunning)
-	kdt.scenario.Step(`^(?:the )?deployment ([^"]*) is running in namespace ([^"]*)$`, kdt.KubeClientSet.DeploymentIsRunning)
+	kdt.scenario.Step(`^(?:I )?get (?:the )?nodes list$`, kdt.KubeClientSet.ListNodesWithAttachment)
+	kdt.scenario.Step(`^(?:the )?daemonset ([^"]*) is running(?: in namespace ([^"]*))?$`, kdt.KubeClientSet.DaemonSetIsRunning)
+	kdt.scenario.Step(`^(?:the )?deployment ([^"]*) is running(?: in namespace ([^"]*))?$`, kdt.KubeClientSet.DeploymentIsRunning)
 	kdt.scenario.Step(`^(?:the )?data in (?:the )?ConfigMap "([^"]*)" in namespace "([^"]*)" has key "([^"]*)" with value "([^"]*)"$`, kdt.KubeClientSet.ConfigMapDataHasKeyAndValue)
+	kdt.scenario.Step(`^(?:the )?(configmap|secret) (\S+)(?: in namespace (\S+))? key (\S+) should (equal|contain|match regex) environment variable (\S+)$`, kdt.KubeClientSet.DataKeyShouldMatchEnvironmentVariable)
+	kdt.scenario.Step(`^(?:the )?(configmap|secret) (\S+)(?: in namespace (\S+))? key (\S+) should (equal|contain|match regex) "(.*)"$`, kdt.KubeClientSet.DataKeyShouldMatch)
+	kdt.scenario.Step(`^(?:the )?(configmap|secret) (\S+)(?: in namespace (\S+))? key (\S+) should match file (\S+)$`, kdt.KubeClientSet.DataKeyShouldMatchFile)
+	kdt.scenario.Step(`^(?:the )?(configmap|secret) (\S+)(?: in namespace (\S+))? key (\S+) as (yaml|json) at path (\S+) should (equal|contain|match regex) (.+)$`, kdt.KubeClientSet.DataKeyAtPathShouldMatch)
 	kdt.scenario.Step(`^(?:the )?persistentvolume ([^"]*) exists with status (Available|Bound|Released|Failed|Pending)$`, kdt.KubeClientSet.PersistentVolExists)
-	kdt.scenario.Step(`^(?:the )?persistentvolumeclaim ([^"]*) exists with status (Available|Bound|Released|Failed|Pending) in namespace ([^"]*)$`, kdt.KubeClientSet.PersistentVolClaimExists)
+	kdt.scenario.Step(`^(?:the )?persistentvolumeclaim ([^"]*) exists with status (Available|Bound|Released|Failed|Pending)(?: in namespace ([^"]*))?$`, kdt.KubeClientSet.PersistentVolClaimExists)
 	kdt.scenario.Step(`^(?:the )?(clusterrole|clusterrolebinding) with name ([^"]*) should be found$`, kdt.KubeClientSet.ClusterRbacIsFound)
-	kdt.scenario.Step(`^(?:the )?ingress (\S+) in (?:the )?namespace (\S+) (?:is )?(?:available )?on port (\d+) and path ([^"]*)$`, kdt.KubeClientSet.IngressAvailable)
-	kdt.scenario.Step(`^(?:I )?send (\d+) tps to ingress (\S+) in (?:the )?namespace (\S+) (?:available )?on port (\d+) and path ([^"]*) for (\d+) (minutes|seconds) expecting up to (\d+) error(?:s)?$`, kdt.KubeClientSet.SendTrafficToIngress)
+	kdt.scenario.Step(`^(?:the )?ingress (\S+)(?: in (?:the )?namespace (\S+))? (?:is )?(?:available )?on port (\d+) and path ([^"]*)$`, kdt.KubeClientSet.IngressAvailable)
+	kdt.scenario.Step(`^(?:I )?send (\d+) tps to ingress (\S+)(?: in (?:the )?namespace (\S+))? (?:available )?on port (\d+) and path ([^"]*) for (\d+) (minutes|seconds) expecting up to (\d+) error(?:s)?$`, kdt.KubeClientSet.SendTrafficToIngress)
 	//syntax-generation:title-0:AWS steps
 	kdt.scenario.Step(`^(?:there are )?(?:valid )?AWS Credentials$`, kdt.AwsClientSet.DiscoverClients)
 	kdt.scenario.Step(`^an Auto Scaling Group named ([^"]*)$`, kdt.AwsClientSet.AnASGNamed)
@@ -94,6 +177,18 @@ func (kdt *Test) SetScenario(scenario *godog.ScenarioContext) {
 	kdt.scenario.Step(`^(?:I )?(add|remove) (?:the )?(\S+) role as trusted entity to iam role ([^"]*)$`, kdt.AwsClientSet.IamRoleTrust)
 	kdt.scenario.Step(`^(?:I )?(add|remove) cluster shared iam role$`, kdt.AwsClientSet.ClusterSharedIamOperation)
 	//syntax-generation:end
+	kdt.scenario.Before(func(ctx context.Context, sc *godog.Scenario) (context.Context, error) {
+		return kdt.KubeClientSet.SetScenario(ctx, sc.Name, sc.Id), nil
+	})
+	kdt.scenario.After(func(ctx context.Context, sc *godog.Scenario, err error) (context.Context, error) {
+		defer kdt.KubeClientSet.EndScenario(ctx)
+		ctx = kdt.KubeClientSet.AttachKilledPods(ctx)
+		captureErr := kdt.KubeClientSet.StopCapturingLogs(ctx)
+		if err := kdt.KubeClientSet.DeleteEphemeralNamespace(ctx); err != nil {
+			return ctx, err
+		}
+		return ctx, captureErr
+	})
 }
 
 /*
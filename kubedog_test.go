@@ -0,0 +1,319 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubedog
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cucumber/godog"
+	"github.com/cucumber/godog/formatters"
+	messages "github.com/cucumber/messages/go/v21"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	kTesting "k8s.io/client-go/testing"
+)
+
+const stepHandlersFormat = "kubedog-step-handlers"
+
+// stepHandlers records the handler each step text is matched to, and ambiguousSteps the steps matched by several
+var (
+	stepHandlers   = map[string]string{}
+	ambiguousSteps = map[string]string{}
+)
+
+func init() {
+	godog.Format(stepHandlersFormat, "records the handler each step is matched to", func(suite string, out io.Writer) godog.Formatter {
+		return &stepHandlersFormatter{BaseFmt: godog.NewBaseFmt(suite, out)}
+	})
+}
+
+type stepHandlersFormatter struct {
+	*godog.BaseFmt
+}
+
+func (f *stepHandlersFormatter) Defined(pickle *messages.Pickle, step *messages.PickleStep, definition *formatters.StepDefinition) {
+	if definition == nil {
+		stepHandlers[step.Text] = ""
+		return
+	}
+	name := runtime.FuncForPC(reflect.ValueOf(definition.Handler).Pointer()).Name()
+	stepHandlers[step.Text] = strings.TrimSuffix(path.Base(name), "-fm")
+}
+
+func (f *stepHandlersFormatter) Ambiguous(pickle *messages.Pickle, step *messages.PickleStep, definition *formatters.StepDefinition, err error) {
+	ambiguousSteps[step.Text] = err.Error()
+}
+
+func TestStepSyntax(t *testing.T) {
+	tests := []struct {
+		step    string
+		handler string
+	}{
+		// Generic steps
+		{`I wait for 5 seconds`, "generic.WaitFor"},
+		{`the kubectl command is available`, "generic.CommandExists"},
+		{`I run the kubectl command with the get pods args and the command succeeds`, "generic.RunCommand"},
+		// Kubernetes steps
+		{`a Kubernetes cluster`, "kube.(*ClientSet).DiscoverClients"},
+		{`valid Kubernetes Credentials`, "kube.(*ClientSet).DiscoverClients"},
+		{`the Kubernetes cluster should be created`, "kube.(*ClientSet).KubernetesClusterShouldBe"},
+		{`I store the current time as start`, "kube.(*ClientSet).SetTimestamp"},
+		{`I create an ephemeral namespace`, "kube.(*ClientSet).CreateEphemeralNamespace"},
+		{`I use the namespace test-ns`, "kube.(*ClientSet).UseNamespace"},
+		// Unstructured Resources
+		{`I create the resource pod.yaml`, "kube.(*ClientSet).ResourceOperation"},
+		{`I create the resource pod.yaml in the test-ns namespace`, "kube.(*ClientSet).ResourceOperationInNamespace"},
+		{`I create the resources in resources.yaml`, "kube.(*ClientSet).ResourcesOperation"},
+		{`I create the resources in resources.yaml in the test-ns namespace`, "kube.(*ClientSet).ResourcesOperationInNamespace"},
+		{`I create the resources in the directory manifests`, "kube.(*ClientSet).ResourcesInDirectoryOperation"},
+		{`I create the resource pod.yaml, the operation should fail`, "kube.(*ClientSet).ResourceOperationWithResult"},
+		{`I create the resource pod.yaml in the test-ns namespace, the operation should succeed`, "kube.(*ClientSet).ResourceOperationWithResultInNamespace"},
+		{`the resource pod.yaml in the namespace test-ns should be created`, "kube.(*ClientSet).ResourceShouldBeInNamespace"},
+		{`the resource pod.yaml should be created`, "kube.(*ClientSet).ResourceShouldBe"},
+		{`the resource pod.yaml in the namespace test-ns should converge to selector .status.phase=Running`, "kube.(*ClientSet).ResourceShouldConvergeToSelectorInNamespace"},
+		{`the resource pod.yaml should converge to selector .status.phase=Running`, "kube.(*ClientSet).ResourceShouldConvergeToSelector"},
+		{`the resource pod.yaml in the namespace test-ns should converge to field .status.phase=Running`, "kube.(*ClientSet).ResourceShouldConvergeToFieldInNamespace"},
+		{`the resource pod.yaml should converge to field .status.phase=Running`, "kube.(*ClientSet).ResourceShouldConvergeToField"},
+		{`the resource pod.yaml in the namespace test-ns condition Ready should be true`, "kube.(*ClientSet).ResourceConditionShouldBeInNamespace"},
+		{`the resource pod.yaml condition Ready should be true`, "kube.(*ClientSet).ResourceConditionShouldBe"},
+		{`I update the resource pod.yaml in the namespace test-ns with .spec.replicas set to 2`, "kube.(*ClientSet).UpdateResourceWithFieldInNamespace"},
+		{`I update the resource pod.yaml with .spec.replicas set to 2`, "kube.(*ClientSet).UpdateResourceWithField"},
+		{`all of the resources in resources.yaml in the namespace test-ns should be created`, "kube.(*ClientSet).ResourcesShouldBe"},
+		{`any of the resources in resources.yaml should converge to selector .status.phase=Running`, "kube.(*ClientSet).ResourcesShouldConvergeToSelector"},
+		{`all resources in resources.yaml should converge to field .status.phase=Running`, "kube.(*ClientSet).ResourcesShouldConvergeToField"},
+		{`all resources in resources.yaml condition Ready should be true`, "kube.(*ClientSet).ResourcesConditionShouldBe"},
+		{`the CRD widgets.example.com should be established`, "kube.(*ClientSet).CustomResourceDefinitionShouldBeEstablished"},
+		{`I delete all the configmaps with selector app=test in the namespace test-ns`, "kube.(*ClientSet).DeleteResourcesWithSelector"},
+		{`the count of configmaps with selector app=test should be 2`, "kube.(*ClientSet).ResourcesWithSelectorCountShouldBe"},
+		{`at least 2 configmaps with label selector app=test should be found`, "kube.(*ClientSet).ResourcesWithSelectorShouldReachCount"},
+		{`at least 2 pods with selector app=test should be found`, "kube.(*ClientSet).ResourcesWithSelectorShouldReachCount"},
+		{`exactly 1 jobs with selector app=test in namespace test-ns should be found`, "kube.(*ClientSet).ResourcesWithSelectorShouldReachCount"},
+		{`all deployments with selector app=test converge to field .status.readyReplicas=2`, "kube.(*ClientSet).ResourcesWithSelectorShouldConvergeToField"},
+		{`the configmap named test in the namespace test-ns should have labels app=test`, "kube.(*ClientSet).ResourceShouldHaveMetadata"},
+		{`all the configmaps with selector app=test should have annotations owner=team`, "kube.(*ClientSet).ResourcesWithSelectorShouldHaveMetadata"},
+		{`all pods with selector app=test should have labels tier=web`, "kube.(*ClientSet).ResourcesWithSelectorShouldHaveMetadata"},
+		{`I verify InstanceGroups are in "ready" state`, "kube.(*ClientSet).VerifyInstanceGroups"},
+		// Pods
+		{`I get the pods in namespace test-ns`, "kube.(*ClientSet).ListPods"},
+		{`I get the pods with selector app=test`, "kube.(*ClientSet).ListPodsWithSelector"},
+		{`the pods with selector app=test have restart count less than 3`, "kube.(*ClientSet).PodsWithSelectorHaveRestartCountLessThan"},
+		{`at least 2 pods with selector app=test should be ready`, "kube.(*ClientSet).PodsWithSelectorCountShouldBe"},
+		{`all pods with selector app=test have "started" in logs since start time`, "kube.(*ClientSet).SomeOrAllPodsInNamespaceWithSelectorHaveStringInLogsSinceTime"},
+		{`some pods with selector app=test don't have "started" in logs since start time`, "kube.(*ClientSet).SomePodsInNamespaceWithSelectorDontHaveStringInLogsSinceTime"},
+		{`the pods with selector app=test have no errors in logs since start time`, "kube.(*ClientSet).PodsInNamespaceWithSelectorHaveNoErrorsInLogsSinceTime"},
+		{`the pods with selector app=test have some errors in logs since start time`, "kube.(*ClientSet).PodsInNamespaceWithSelectorHaveSomeErrorsInLogsSinceTime"},
+		{`the pods with selector app=test should have at least 1 log line matching substring started since start time`, "kube.(*ClientSet).PodsInNamespaceWithSelectorShouldHaveLogLinesMatching"},
+		{`the previous instance of container main of pods with selector app=test should have exactly 1 log line matching regex ^panic since start time`, "kube.(*ClientSet).ContainerInPodsInNamespaceWithSelectorShouldHaveLogLinesMatching"},
+		{`the pods with selector app=test should log a line matching substring started since start time within 1 minutes`, "kube.(*ClientSet).PodsInNamespaceWithSelectorShouldLogLineMatching"},
+		{`I start capturing logs of pods with selector app=test in namespace test-ns`, "kube.(*ClientSet).StartCapturingLogs"},
+		{`the pods with selector app=test should be in phase Running`, "kube.(*ClientSet).PodsInNamespaceWithSelectorShouldBeInPhase"},
+		{`the container main of pods with selector app=test should have state reason CrashLoopBackOff`, "kube.(*ClientSet).ContainerInPodsInNamespaceWithSelectorShouldHaveStateReason"},
+		{`the container main of pods with selector app=test should be ready`, "kube.(*ClientSet).ContainerInPodsInNamespaceWithSelectorShouldBeReady"},
+		{`the pods with selector app=test should be scheduled on nodes with selector zone=a`, "kube.(*ClientSet).PodsInNamespaceWithSelectorShouldBeScheduledOnNodesWithSelector"},
+		{`the pods with selector app=test should be scheduled on nodes satisfying their node selector and affinity`, "kube.(*ClientSet).PodsInNamespaceWithSelectorShouldSatisfyNodeAffinity"},
+		{`the pods with selector app=test should have QoS class Guaranteed`, "kube.(*ClientSet).PodsInNamespaceWithSelectorShouldHaveQOSClass"},
+		{`the container main of pods with selector app=test should be running image nginx:1.25`, "kube.(*ClientSet).ContainerInPodsInNamespaceWithSelectorShouldRunImage"},
+		{`all pods with label selector app=test should converge to field selector status.phase=Running`, "kube.(*ClientSet).PodsInNamespaceWithLabelSelectorConvergeToFieldSelector"},
+		{`the pods with selector app=test should have labels tier=web`, "kube.(*ClientSet).PodsInNamespaceWithSelectorShouldHaveLabels"},
+		{`pods in namespace test-ns with selector app=test should have labels tier=web`, "kube.(*ClientSet).PodsInNamespaceWithSelectorShouldHaveLabels"},
+		{`the pod test in namespace test-ns should have labels tier=web`, "kube.(*ClientSet).PodInNamespaceShouldHaveLabels"},
+		{`I evict the pod test, the eviction should be allowed`, "kube.(*ClientSet).EvictPodInNamespace"},
+		{`I evict 1 pod with selector app=test, the eviction should be rejected`, "kube.(*ClientSet).EvictPodsInNamespaceWithSelector"},
+		{`I gracefully kill 50 percent of pods with selector app=test`, "kube.(*ClientSet).KillRandomPodsInNamespaceWithSelector"},
+		{`the pods with selector app=test should recover within 2 minutes`, "kube.(*ClientSet).PodsInNamespaceWithSelectorShouldRecover"},
+		{`the pods with selector app=test should recover within 2 minutes with restart count less than 1`, "kube.(*ClientSet).PodsInNamespaceWithSelectorShouldRecoverWithRestartCountLessThan"},
+		// Nodes
+		{`I cordon the node node-1`, "kube.(*ClientSet).CordonNode"},
+		{`I uncordon the nodes with selector zone=a`, "kube.(*ClientSet).CordonNodesWithSelector"},
+		{`I drain the node node-1 within 5 minutes`, "kube.(*ClientSet).DrainNode"},
+		{`I drain the nodes with selector zone=a within 5 minutes`, "kube.(*ClientSet).DrainNodesWithSelector"},
+		{`I add the taint dedicated=test:NoSchedule to the node node-1`, "kube.(*ClientSet).NodeTaintOperation"},
+		{`I remove the label zone=a from the node node-1`, "kube.(*ClientSet).NodeLabelOperation"},
+		{`the node node-1 condition Ready should be True`, "kube.(*ClientSet).NodeConditionShouldBe"},
+		{`the nodes with selector zone=a condition Ready should be True`, "kube.(*ClientSet).NodesWithSelectorConditionShouldBe"},
+		{`the nodes with selector zone=a should have kubelet version v1.29.0`, "kube.(*ClientSet).NodesWithSelectorShouldHaveKubeletVersion"},
+		{`the nodes with selector zone=a should have at least 2 allocatable cpu`, "kube.(*ClientSet).NodesWithSelectorShouldHaveResource"},
+		{`the nodes with selector zone=a should be spread across at least 2 zones`, "kube.(*ClientSet).NodesWithSelectorShouldBeSpreadAcrossZones"},
+		// Network
+		{`pods with selector app=client should be denied to connect to service test in namespace test-ns on port 80`, "kube.(*ClientSet).ConnectivityShouldBe"},
+		{`the network connectivity should be:`, "kube.(*ClientSet).ConnectivityMatrixShouldBe"},
+		// Others
+		{`I create the secret test from environment variable SECRET_DATA`, "kube.(*ClientSet).SecretOperationFromEnvironmentVariable"},
+		{`I create the secret test in namespace test-ns with:`, "kube.(*ClientSet).SecretOperationFromTable"},
+		{`I create the secret test of type kubernetes.io/tls with:`, "kube.(*ClientSet).SecretOfTypeOperationFromTable"},
+		{`I create the self-signed tls secret test for hosts example.com`, "kube.(*ClientSet).SecretOperationWithSelfSignedCertificate"},
+		{`I delete the secret test in namespace test-ns`, "kube.(*ClientSet).SecretDelete"},
+		{`2 nodes with selector zone=a should be ready`, "kube.(*ClientSet).NodesWithSelectorShouldBe"},
+		{`exactly 1 job with selector app=test should be completed`, "kube.(*ClientSet).JobsWithSelectorCountShouldBe"},
+		{`I create the job test-run from the cronjob test`, "kube.(*ClientSet).CreateJobFromCronJob"},
+		{`the job test should complete`, "kube.(*ClientSet).JobShouldFinish"},
+		{`the job test should have at most 1 failed pod`, "kube.(*ClientSet).JobShouldHavePodCount"},
+		{`the job test should fail after exceeding its backoff limit`, "kube.(*ClientSet).JobShouldExceedBackoffLimit"},
+		{`I suspend the cronjob test`, "kube.(*ClientSet).CronJobOperation"},
+		{`at least 1 persistentvolumeclaim with selector app=test should be bound`, "kube.(*ClientSet).PersistentVolumeClaimsWithSelectorCountShouldBe"},
+		{`the pdb test should have at least 1 disruptionsAllowed`, "kube.(*ClientSet).PodDisruptionBudgetStatusShouldBe"},
+		{`the hpa test should have at least 2 currentReplicas`, "kube.(*ClientSet).HorizontalPodAutoscalerReplicasShouldBe"},
+		{`the hpa test should scale up within 5 minutes`, "kube.(*ClientSet).HorizontalPodAutoscalerShouldScale"},
+		{`the hpa test condition ScalingActive should be True`, "kube.(*ClientSet).HorizontalPodAutoscalerConditionShouldBe"},
+		{`the service test should have at least 1 ready endpoint`, "kube.(*ClientSet).ServiceShouldHaveReadyEndpoints"},
+		{`the service test should select the pods with selector app=test`, "kube.(*ClientSet).ServiceShouldSelectPodsWithSelector"},
+		{`the service test should have a load balancer ingress`, "kube.(*ClientSet).ServiceShouldHaveLoadBalancerIngress"},
+		{`the service test should be of type ClusterIP`, "kube.(*ClientSet).ServiceShouldBeOfType"},
+		{`the service test should have TCP port 80 with target port http`, "kube.(*ClientSet).ServiceShouldHavePort"},
+		{`the service test should have node port 30080 for TCP port 80`, "kube.(*ClientSet).ServiceShouldHaveNodePort"},
+		{`the deployment test is in namespace test-ns`, "kube.(*ClientSet).ResourceInNamespace"},
+		{`I scale the deployment test in namespace test-ns to 2`, "kube.(*ClientSet).ScaleDeployment"},
+		{`I validate Prometheus Statefulset prometheus in namespace monitoring has volumeClaimTemplates name data`, "kube.(*ClientSet).ValidatePrometheusVolumeClaimTemplatesName"},
+		{`I get the nodes list`, "kube.(*ClientSet).ListNodesWithAttachment"},
+		{`the daemonset test is running in namespace test-ns`, "kube.(*ClientSet).DaemonSetIsRunning"},
+		{`the deployment test is running`, "kube.(*ClientSet).DeploymentIsRunning"},
+		{`the data in the ConfigMap "test" in namespace "test-ns" has key "mode" with value "debug"`, "kube.(*ClientSet).ConfigMapDataHasKeyAndValue"},
+		{`the secret test key password should equal environment variable TEST_PASSWORD`, "kube.(*ClientSet).DataKeyShouldMatchEnvironmentVariable"},
+		{`the configmap test key mode should equal "debug"`, "kube.(*ClientSet).DataKeyShouldMatch"},
+		{`the secret test key password should equal "environment variable TEST_PASSWORD"`, "kube.(*ClientSet).DataKeyShouldMatch"},
+		{`the secret test key tls.crt should match file tls.crt`, "kube.(*ClientSet).DataKeyShouldMatchFile"},
+		{`the configmap test key config.yaml as yaml at path .log.level should equal debug`, "kube.(*ClientSet).DataKeyAtPathShouldMatch"},
+		{`the persistentvolume test exists with status Bound`, "kube.(*ClientSet).PersistentVolExists"},
+		{`the persistentvolumeclaim test exists with status Bound in namespace test-ns`, "kube.(*ClientSet).PersistentVolClaimExists"},
+		{`the clusterrole with name test should be found`, "kube.(*ClientSet).ClusterRbacIsFound"},
+		{`the ingress test is available on port 80 and path /health`, "kube.(*ClientSet).IngressAvailable"},
+		{`I send 10 tps to ingress test on port 80 and path /health for 1 minutes expecting up to 2 errors`, "kube.(*ClientSet).SendTrafficToIngress"},
+		// AWS steps
+		{`valid AWS Credentials`, "aws.(*ClientSet).DiscoverClients"},
+		{`an Auto Scaling Group named test-asg`, "aws.(*ClientSet).AnASGNamed"},
+		{`I update the current Auto Scaling Group with MinSize set to 2`, "aws.(*ClientSet).UpdateFieldOfCurrentASG"},
+		{`the current Auto Scaling Group is scaled to (min, max) = (2, 4)`, "aws.(*ClientSet).ScaleCurrentASG"},
+		{`the DNS name test.example.com should be created in hostedZoneID Z123`, "aws.(*ClientSet).DnsNameShouldOrNotInHostedZoneID"},
+		{`I add the test role as trusted entity to iam role test-role`, "aws.(*ClientSet).IamRoleTrust"},
+		{`I add cluster shared iam role`, "aws.(*ClientSet).ClusterSharedIamOperation"},
+	}
+
+	var feature strings.Builder
+	feature.WriteString("Feature: step syntax\n")
+	for i, tt := range tests {
+		fmt.Fprintf(&feature, "  Scenario: step %d\n    When %s\n", i, tt.step)
+	}
+	godog.TestSuite{
+		ScenarioInitializer: func(ctx *godog.ScenarioContext) {
+			var k Test
+			k.SetScenario(ctx)
+			// match the steps without running them
+			ctx.StepContext().Before(func(ctx context.Context, st *godog.Step) (context.Context, error) {
+				return ctx, godog.ErrSkip
+			})
+		},
+		Options: &godog.Options{
+			Format:          stepHandlersFormat,
+			Output:          io.Discard,
+			Strict:          true,
+			FeatureContents: []godog.Feature{{Name: "syntax.feature", Contents: []byte(feature.String())}},
+		},
+	}.Run()
+
+	for _, tt := range tests {
+		t.Run(tt.step, func(t *testing.T) {
+			if err, ok := ambiguousSteps[tt.step]; ok {
+				t.Fatalf("step '%s' should match exactly one handler: %s", tt.step, err)
+			}
+			handler, ok := stepHandlers[tt.step]
+			if !ok {
+				t.Fatalf("step '%s' was not run", tt.step)
+			}
+			if handler != tt.handler {
+				t.Errorf("step '%s' matched %s, want %s", tt.step, handler, tt.handler)
+			}
+		})
+	}
+}
+
+func TestConcurrentScenarios(t *testing.T) {
+	const scenarios = 2
+	kubeClientset := fake.NewSimpleClientset()
+	var k Test
+	k.KubeClientSet.KubeInterface = kubeClientset
+	k.KubeClientSet.SetWaiterInterval(time.Millisecond)
+
+	var created sync.WaitGroup
+	created.Add(scenarios)
+	allCreated := make(chan struct{})
+	go func() {
+		created.Wait()
+		close(allCreated)
+	}()
+
+	var feature strings.Builder
+	feature.WriteString("Feature: concurrent scenarios\n")
+	for i := 0; i < scenarios; i++ {
+		fmt.Fprintf(&feature, "  Scenario: scenario %d\n", i)
+		feature.WriteString("    Given I create an ephemeral namespace\n")
+		feature.WriteString("    And I use the namespace $EPHEMERAL_NAMESPACE\n")
+		feature.WriteString("    Then all the scenarios created their ephemeral namespace\n")
+		feature.WriteString("    And I store the current time as done\n")
+	}
+	status := godog.TestSuite{
+		ScenarioInitializer: func(ctx *godog.ScenarioContext) {
+			k.SetScenario(ctx)
+			ctx.Step(`^all the scenarios created their ephemeral namespace$`, func() error {
+				created.Done()
+				select {
+				case <-allCreated:
+					return nil
+				case <-time.After(5 * time.Second):
+					return errors.New("timed out waiting for the other scenarios")
+				}
+			})
+		},
+		Options: &godog.Options{
+			Format:          "progress",
+			Output:          io.Discard,
+			Concurrency:     scenarios,
+			FeatureContents: []godog.Feature{{Name: "concurrent.feature", Contents: []byte(feature.String())}},
+		},
+	}.Run()
+	if status != 0 {
+		t.Fatalf("concurrent scenarios failed with status %d", status)
+	}
+
+	names := map[string]bool{}
+	for _, action := range kubeClientset.Actions() {
+		if createAction, ok := action.(kTesting.CreateAction); ok && action.GetResource().Resource == "namespaces" {
+			names[createAction.GetObject().(*corev1.Namespace).Name] = true
+		}
+	}
+	if len(names) != scenarios {
+		t.Errorf("expected %d ephemeral namespaces to be created, got %v", scenarios, names)
+	}
+	namespaces, err := kubeClientset.CoreV1().Namespaces().List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(namespaces.Items) != 0 {
+		t.Errorf("expected the ephemeral namespaces to be deleted, got %d", len(namespaces.Items))
+	}
+	if _, err := k.KubeClientSet.GetTimestamp("done"); err != nil {
+		t.Errorf("expected the timestamps stored by the scenarios to be kept in the test: %v", err)
+	}
+}
@@ -241,10 +241,21 @@ metadata:
   name: ` + args.Name
 	}
 
+	// the templates are copied so that the generated files are written to a temporary directory
+	testTemplatesPath := t.TempDir()
+	for _, name := range []string{"templated.yaml", "templated-bad-kind.yaml"} {
+		data, err := os.ReadFile(filepath.Join("test", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(testTemplatesPath, name), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
 	var (
-		g                    = gomega.NewWithT(t)
-		testTemplatesPath, _ = filepath.Abs("./test")
-		tests                = []struct {
+		g     = gomega.NewWithT(t)
+		tests = []struct {
 			templatedFilePath string
 			args              templateArgs
 			expectedFilePath  string
@@ -15,9 +15,13 @@ limitations under the License.
 package common
 
 import (
+	"bytes"
+	"html/template"
 	"time"
 
 	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/kubernetes"
 )
 
@@ -28,11 +32,29 @@ const (
 	OperationDelete = "delete"
 	OperationUpsert = "upsert"
 
-	StateCreated  = "created"
-	StateDeleted  = "deleted"
-	StateUpgraded = "upgraded"
-	StateReady    = "ready"
-	StateFound    = "found"
+	OperationCordon   = "cordon"
+	OperationUncordon = "uncordon"
+	OperationAdd      = "add"
+	OperationRemove   = "remove"
+	OperationSuspend  = "suspend"
+	OperationResume   = "resume"
+
+	StateCreated   = "created"
+	StateDeleted   = "deleted"
+	StateUpgraded  = "upgraded"
+	StateReady     = "ready"
+	StateFound     = "found"
+	StateRunning   = "running"
+	StateSucceeded = "succeeded"
+	StateCompleted = "completed"
+	StateBound     = "bound"
+
+	ComparisonAtLeast = "at least"
+	ComparisonAtMost  = "at most"
+	ComparisonExactly = "exactly"
+
+	QuantifierAll = "all"
+	QuantifierAny = "any"
 )
 
 type WaiterConfig struct {
@@ -66,3 +88,89 @@ func ValidateClientset(kubeClientset kubernetes.Interface) error {
 	}
 	return nil
 }
+
+func CompareCount(comparison string, count, expectedCount int) (bool, error) {
+	switch comparison {
+	case ComparisonAtLeast:
+		return count >= expectedCount, nil
+	case ComparisonAtMost:
+		return count <= expectedCount, nil
+	case ComparisonExactly:
+		return count == expectedCount, nil
+	default:
+		return false, errors.Errorf("unsupported comparison: '%s'", comparison)
+	}
+}
+
+// WaitForCount polls the count until it compares to the expected count or the waiter times out, the description names
+// what is counted in logs and errors, e.g. "running pods with selector 'app=x'"
+func WaitForCount(w WaiterConfig, comparison string, expectedCount int, description string, getCount func() (int, error)) error {
+	if _, err := CompareCount(comparison, 0, expectedCount); err != nil {
+		return err
+	}
+
+	for counter := 0; ; counter++ {
+		if counter >= w.GetTries() {
+			return errors.Errorf("waiter timed out waiting for %s %d %s", comparison, expectedCount, description)
+		}
+
+		count, err := getCount()
+		if err != nil {
+			return err
+		}
+		found, err := CompareCount(comparison, count, expectedCount)
+		if err != nil {
+			return err
+		}
+		if found {
+			log.Infof("found %d %s, expected %s %d", count, description, comparison, expectedCount)
+			return nil
+		}
+
+		log.Infof("found %d %s, waiting for %s %d", count, description, comparison, expectedCount)
+		time.Sleep(w.GetInterval())
+	}
+}
+
+// ValidateSelectorMatch checks set satisfies every requirement of the selector, e.g. 'k=v,!k2,k3 in (a,b)'
+func ValidateSelectorMatch(set map[string]string, selector string) error {
+	parsedSelector, err := labels.Parse(selector)
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse selector '%s'", selector)
+	}
+
+	requirements, _ := parsedSelector.Requirements()
+	for _, requirement := range requirements {
+		if !requirement.Matches(labels.Set(set)) {
+			return errors.Errorf("requirement '%s' is not satisfied by %v", requirement.String(), set)
+		}
+	}
+	return nil
+}
+
+// TemplateArguments are given in place of the template arguments to also register functions the templates can call
+type TemplateArguments struct {
+	Arguments interface{}
+	Funcs     template.FuncMap
+}
+
+// RenderTemplate executes the text as an html/template when arguments or functions are given, and returns it as is otherwise
+func RenderTemplate(name, text string, args interface{}) ([]byte, error) {
+	var funcs template.FuncMap
+	if templateArguments, ok := args.(TemplateArguments); ok {
+		args, funcs = templateArguments.Arguments, templateArguments.Funcs
+	}
+	if args == nil && len(funcs) == 0 {
+		return []byte(text), nil
+	}
+
+	var renderBuffer bytes.Buffer
+	t, err := template.New(name).Funcs(funcs).Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	if err := t.Execute(&renderBuffer, &args); err != nil {
+		return nil, err
+	}
+	return renderBuffer.Bytes(), nil
+}
@@ -0,0 +1,92 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"html/template"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestRenderTemplate(t *testing.T) {
+	type templateArgs struct {
+		Name string
+	}
+	funcs := template.FuncMap{
+		"ephemeralNamespace": func() (string, error) { return "kubedog-abc123", nil },
+		"failing":            func() (string, error) { return "", errors.New("not available") },
+	}
+	type args struct {
+		text string
+		args interface{}
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "Positive Test: text without arguments is returned as is",
+			args: args{text: "name: {{.Name}}"},
+			want: "name: {{.Name}}",
+		},
+		{
+			name: "Positive Test: struct arguments",
+			args: args{text: "name: {{.Name}}", args: templateArgs{Name: "test"}},
+			want: "name: test",
+		},
+		{
+			name: "Positive Test: arguments are escaped like html",
+			args: args{text: "name: {{.Name}}", args: templateArgs{Name: "a&b"}},
+			want: "name: a&amp;b",
+		},
+		{
+			name: "Positive Test: struct arguments and functions",
+			args: args{
+				text: "name: {{.Name}}\nnamespace: {{ ephemeralNamespace }}",
+				args: TemplateArguments{Arguments: templateArgs{Name: "test"}, Funcs: funcs},
+			},
+			want: "name: test\nnamespace: kubedog-abc123",
+		},
+		{
+			name: "Positive Test: functions without arguments",
+			args: args{text: "namespace: {{ ephemeralNamespace }}", args: TemplateArguments{Funcs: funcs}},
+			want: "namespace: kubedog-abc123",
+		},
+		{
+			name:    "Negative Test: function fails",
+			args:    args{text: "namespace: {{ failing }}", args: TemplateArguments{Funcs: funcs}},
+			wantErr: true,
+		},
+		{
+			name:    "Negative Test: function not defined",
+			args:    args{text: "namespace: {{ undefined }}", args: TemplateArguments{Arguments: templateArgs{}}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RenderTemplate("test", tt.args.text, tt.args.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("RenderTemplate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && string(got) != tt.want {
+				t.Errorf("RenderTemplate() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
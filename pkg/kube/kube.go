@@ -15,18 +15,28 @@ limitations under the License.
 package kube
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
+	"text/tabwriter"
 	"time"
 
+	"github.com/cucumber/godog"
+	"github.com/keikoproj/kubedog/internal/util"
 	"github.com/keikoproj/kubedog/pkg/kube/common"
+	"github.com/keikoproj/kubedog/pkg/kube/network"
+	"github.com/keikoproj/kubedog/pkg/kube/node"
 	"github.com/keikoproj/kubedog/pkg/kube/pod"
 	"github.com/keikoproj/kubedog/pkg/kube/structured"
 	unstruct "github.com/keikoproj/kubedog/pkg/kube/unstructured"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/rand"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/clientcmd"
@@ -36,6 +46,10 @@ type ClientSet struct {
 	KubeInterface    kubernetes.Interface
 	DynamicInterface dynamic.Interface
 	timestamps       map[string]time.Time
+	scenarios        map[string]*scenarioState
+	lock             sync.Mutex
+	discoveryClient  *unstruct.CachedDiscoveryClient
+	discoveryHost    string
 	config           configuration
 }
 
@@ -55,6 +69,61 @@ func (kc *ClientSet) SetWaiterTries(tries int) {
 	kc.config.waiterTries = tries
 }
 
+// SetLogErrorPatterns sets the regular expressions a log line is considered an error for,
+// literal text must be escaped, e.g. with regexp.QuoteMeta
+func (kc *ClientSet) SetLogErrorPatterns(patterns []string) {
+	kc.config.logErrorPatterns = patterns
+}
+
+func (kc *ClientSet) SetLogsCapturePath(path string) {
+	kc.config.logsCapturePath = path
+}
+
+func (kc *ClientSet) SetConnectivityProbeImage(image string) {
+	kc.config.connectivityProbeImage = image
+}
+
+func (kc *ClientSet) SetEphemeralNamespacePrefix(prefix string) {
+	kc.config.ephemeralNamespacePrefix = prefix
+}
+
+func (kc *ClientSet) SetEphemeralNamespaceLabels(labels map[string]string) {
+	kc.config.ephemeralNamespaceLabels = labels
+}
+
+func (kc *ClientSet) SetDefaultNamespace(namespace string) {
+	kc.config.defaultNamespace = namespace
+}
+
+func (kc *ClientSet) SetDiscoveryCacheDir(path string) {
+	kc.config.discoveryCacheDir = path
+}
+
+func (kc *ClientSet) SetDiscoveryCacheTTL(duration time.Duration) {
+	kc.config.discoveryCacheTTL = duration
+}
+
+// SetScenario starts keeping the state of the scenario apart from the other scenarios, and returns the context its
+// steps and hooks are given so they use that state. The id tells Scenario Outline examples apart.
+func (kc *ClientSet) SetScenario(ctx context.Context, name, id string) context.Context {
+	kc.lock.Lock()
+	defer kc.lock.Unlock()
+	if kc.scenarios == nil {
+		kc.scenarios = map[string]*scenarioState{}
+	}
+	kc.scenarios[id] = &scenarioState{name: name, id: id}
+	return context.WithValue(ctx, scenarioKey{}, id)
+}
+
+// EndScenario drops the state of the scenario, once its hooks are done with it
+func (kc *ClientSet) EndScenario(ctx context.Context) {
+	kc.lock.Lock()
+	defer kc.lock.Unlock()
+	if id, ok := ctx.Value(scenarioKey{}).(string); ok {
+		delete(kc.scenarios, id)
+	}
+}
+
 func (kc *ClientSet) DiscoverClients() error {
 	var (
 		home, _        = os.UserHomeDir()
@@ -87,12 +156,28 @@ func (kc *ClientSet) DiscoverClients() error {
 
 	kc.DynamicInterface = dynClient
 	kc.KubeInterface = client
+	// the cached discovery is kept while the clients are discovered again for the same cluster
+	if kc.discoveryClient != nil && kc.discoveryHost == config.Host {
+		return nil
+	}
+	if kc.config.discoveryCacheDir != "" {
+		diskCachedDiscoveryClient, err := kc.newDiskCachedDiscoveryClient(config)
+		if err != nil {
+			return err
+		}
+		kc.discoveryClient = unstruct.NewCachedDiscoveryClient(diskCachedDiscoveryClient)
+	} else {
+		kc.discoveryClient = unstruct.NewCachedDiscoveryClient(client.Discovery())
+	}
+	kc.discoveryHost = config.Host
 
 	return nil
 }
 
 func (kc *ClientSet) SetTimestamp(timestampName string) error {
 	now := time.Now()
+	kc.lock.Lock()
+	defer kc.lock.Unlock()
 	if kc.timestamps == nil {
 		kc.timestamps = map[string]time.Time{}
 	}
@@ -101,6 +186,37 @@ func (kc *ClientSet) SetTimestamp(timestampName string) error {
 	return nil
 }
 
+// CreateEphemeralNamespace creates a uniquely named namespace that is deleted after the scenario. Steps can refer to it
+// as '$EPHEMERAL_NAMESPACE' in place of a namespace, and templates rendered with template arguments as
+// '{{ ephemeralNamespace }}'.
+func (kc *ClientSet) CreateEphemeralNamespace(ctx context.Context) error {
+	scenario := kc.getScenario(ctx)
+	if scenario.ephemeralNamespace != "" {
+		return errors.Errorf("ephemeral namespace '%s' was already created in this scenario", scenario.ephemeralNamespace)
+	}
+
+	name := kc.getEphemeralNamespacePrefix() + "-" + rand.String(ephemeralNamespaceSuffixLength)
+	labels := map[string]string{ephemeralNamespaceLabel: "true"}
+	for k, v := range kc.config.ephemeralNamespaceLabels {
+		labels[k] = v
+	}
+	if err := structured.CreateNamespace(kc.KubeInterface, name, labels); err != nil {
+		return err
+	}
+	scenario.ephemeralNamespace = name
+	return nil
+}
+
+func (kc *ClientSet) DeleteEphemeralNamespace(ctx context.Context) error {
+	scenario := kc.getScenario(ctx)
+	if scenario.ephemeralNamespace == "" {
+		return nil
+	}
+	name := scenario.ephemeralNamespace
+	scenario.ephemeralNamespace = ""
+	return structured.DeleteNamespace(kc.KubeInterface, kc.getWaiterConfig(), name)
+}
+
 func (kc *ClientSet) KubernetesClusterShouldBe(state string) error {
 	switch state {
 	case common.StateCreated, common.StateUpgraded:
@@ -122,11 +238,11 @@ func (kc *ClientSet) DeleteAllTestResources() error {
 	if err := kc.DiscoverClients(); err != nil {
 		return err
 	}
-	return unstruct.DeleteResourcesAtPath(kc.DynamicInterface, kc.getDiscoveryClient(), kc.config.templateArguments, kc.getWaiterConfig(), kc.getTemplatesPath())
+	return unstruct.DeleteResourcesAtPath(kc.DynamicInterface, kc.getDiscoveryClient(), kc.getTemplateArguments(context.Background()), kc.getWaiterConfig(), kc.getTemplatesPath())
 }
 
-func (kc *ClientSet) ResourceOperation(operation, resourceFileName string) error {
-	resource, err := unstruct.GetResource(kc.getDiscoveryClient(), kc.config.templateArguments, kc.getResourcePath(resourceFileName))
+func (kc *ClientSet) ResourceOperation(ctx context.Context, operation, resourceFileName string) error {
+	resource, err := kc.getResource(ctx, resourceFileName)
 	if err != nil {
 		return err
 	}
@@ -134,104 +250,285 @@ func (kc *ClientSet) ResourceOperation(operation, resourceFileName string) error
 	return unstruct.ResourceOperation(kc.DynamicInterface, resource, operation)
 }
 
-func (kc *ClientSet) ResourceOperationInNamespace(operation, resourceFileName, namespace string) error {
-	resource, err := unstruct.GetResource(kc.getDiscoveryClient(), kc.config.templateArguments, kc.getResourcePath(resourceFileName))
+func (kc *ClientSet) ResourceOperationInNamespace(ctx context.Context, operation, resourceFileName, namespace string) error {
+	namespace, err := kc.resolveNamespace(ctx, namespace)
+	if err != nil {
+		return err
+	}
+	resource, err := kc.getResource(ctx, resourceFileName)
 	if err != nil {
 		return err
 	}
 	return unstruct.ResourceOperationInNamespace(kc.DynamicInterface, resource, operation, namespace)
 }
 
-func (kc *ClientSet) ResourcesOperation(operation, resourcesFileName string) error {
-	resources, err := unstruct.GetResources(kc.getDiscoveryClient(), kc.config.templateArguments, kc.getResourcePath(resourcesFileName))
+func (kc *ClientSet) ResourcesOperation(ctx context.Context, operation, resourcesFileName string) error {
+	resources, err := kc.getResources(ctx, resourcesFileName)
 	if err != nil {
 		return err
 	}
 	return unstruct.ResourcesOperation(kc.DynamicInterface, resources, operation)
 }
 
-func (kc *ClientSet) ResourcesOperationInNamespace(operation, resourcesFileName, namespace string) error {
-	resources, err := unstruct.GetResources(kc.getDiscoveryClient(), kc.config.templateArguments, kc.getResourcePath(resourcesFileName))
+func (kc *ClientSet) ResourcesOperationInNamespace(ctx context.Context, operation, resourcesFileName, namespace string) error {
+	namespace, err := kc.resolveNamespace(ctx, namespace)
+	if err != nil {
+		return err
+	}
+	resources, err := kc.getResources(ctx, resourcesFileName)
 	if err != nil {
 		return err
 	}
 	return unstruct.ResourcesOperationInNamespace(kc.DynamicInterface, resources, operation, namespace)
 }
 
-func (kc *ClientSet) ResourceOperationWithResult(operation, resourceFileName, expectedResult string) error {
-	resource, err := unstruct.GetResource(kc.getDiscoveryClient(), kc.config.templateArguments, kc.getResourcePath(resourceFileName))
+func (kc *ClientSet) ResourcesInDirectoryOperation(ctx context.Context, operation, directory string) error {
+	defaultNamespace, err := kc.resolveNamespace(ctx, "")
+	if err != nil {
+		return err
+	}
+	return unstruct.ResourcesAtPathOperation(kc.DynamicInterface, kc.getDiscoveryClient(), kc.getTemplateArguments(ctx), kc.getWaiterConfig(), kc.getResourcePath(directory), operation, defaultNamespace)
+}
+
+func (kc *ClientSet) ResourceOperationWithResult(ctx context.Context, operation, resourceFileName, expectedResult string) error {
+	resource, err := kc.getResource(ctx, resourceFileName)
 	if err != nil {
 		return err
 	}
 	return unstruct.ResourceOperationWithResult(kc.DynamicInterface, resource, operation, expectedResult)
 }
 
-func (kc *ClientSet) ResourceOperationWithResultInNamespace(operation, resourceFileName, namespace, expectedResult string) error {
-	resource, err := unstruct.GetResource(kc.getDiscoveryClient(), kc.config.templateArguments, kc.getResourcePath(resourceFileName))
+func (kc *ClientSet) ResourceOperationWithResultInNamespace(ctx context.Context, operation, resourceFileName, namespace, expectedResult string) error {
+	namespace, err := kc.resolveNamespace(ctx, namespace)
+	if err != nil {
+		return err
+	}
+	resource, err := kc.getResource(ctx, resourceFileName)
 	if err != nil {
 		return err
 	}
 	return unstruct.ResourceOperationWithResultInNamespace(kc.DynamicInterface, resource, operation, namespace, expectedResult)
 }
 
-func (kc *ClientSet) ResourceShouldBe(resourceFileName, state string) error {
-	resource, err := unstruct.GetResource(kc.getDiscoveryClient(), kc.config.templateArguments, kc.getResourcePath(resourceFileName))
+func (kc *ClientSet) ResourceShouldBe(ctx context.Context, resourceFileName, state string) error {
+	return kc.ResourcesShouldBe(ctx, common.QuantifierAll, resourceFileName, "", state)
+}
+
+func (kc *ClientSet) ResourceShouldBeInNamespace(ctx context.Context, resourceFileName, namespace, state string) error {
+	return kc.ResourcesShouldBe(ctx, common.QuantifierAll, resourceFileName, namespace, state)
+}
+
+func (kc *ClientSet) ResourcesShouldBe(ctx context.Context, quantifier, resourcesFileName, namespace, state string) error {
+	namespace, err := kc.resolveNamespaceOverride(ctx, namespace)
+	if err != nil {
+		return err
+	}
+	resources, err := kc.getResources(ctx, resourcesFileName)
+	if err != nil {
+		return err
+	}
+	return unstruct.ResourcesShouldBeInNamespace(kc.DynamicInterface, resources, kc.getWaiterConfig(), quantifier, namespace, state)
+}
+
+func (kc *ClientSet) ResourceShouldConvergeToSelector(ctx context.Context, resourceFileName, selector string) error {
+	return kc.ResourcesShouldConvergeToSelector(ctx, common.QuantifierAll, resourceFileName, "", selector)
+}
+
+func (kc *ClientSet) ResourceShouldConvergeToSelectorInNamespace(ctx context.Context, resourceFileName, namespace, selector string) error {
+	return kc.ResourcesShouldConvergeToSelector(ctx, common.QuantifierAll, resourceFileName, namespace, selector)
+}
+
+func (kc *ClientSet) ResourcesShouldConvergeToSelector(ctx context.Context, quantifier, resourcesFileName, namespace, selector string) error {
+	namespace, err := kc.resolveNamespaceOverride(ctx, namespace)
+	if err != nil {
+		return err
+	}
+	resources, err := kc.getResources(ctx, resourcesFileName)
+	if err != nil {
+		return err
+	}
+	return unstruct.ResourcesShouldConvergeToSelectorInNamespace(kc.DynamicInterface, resources, kc.getWaiterConfig(), quantifier, namespace, selector)
+}
+
+func (kc *ClientSet) ResourceShouldConvergeToField(ctx context.Context, resourceFileName, selector string) error {
+	return kc.ResourcesShouldConvergeToField(ctx, common.QuantifierAll, resourceFileName, "", selector)
+}
+
+func (kc *ClientSet) ResourceShouldConvergeToFieldInNamespace(ctx context.Context, resourceFileName, namespace, selector string) error {
+	return kc.ResourcesShouldConvergeToField(ctx, common.QuantifierAll, resourceFileName, namespace, selector)
+}
+
+func (kc *ClientSet) ResourcesShouldConvergeToField(ctx context.Context, quantifier, resourcesFileName, namespace, selector string) error {
+	namespace, err := kc.resolveNamespaceOverride(ctx, namespace)
+	if err != nil {
+		return err
+	}
+	resources, err := kc.getResources(ctx, resourcesFileName)
+	if err != nil {
+		return err
+	}
+	return unstruct.ResourcesShouldConvergeToFieldInNamespace(kc.DynamicInterface, resources, kc.getWaiterConfig(), quantifier, namespace, selector)
+}
+
+func (kc *ClientSet) ResourceConditionShouldBe(ctx context.Context, resourceFileName, conditionType, conditionValue string) error {
+	return kc.ResourcesConditionShouldBe(ctx, common.QuantifierAll, resourceFileName, "", conditionType, conditionValue)
+}
+
+func (kc *ClientSet) ResourceConditionShouldBeInNamespace(ctx context.Context, resourceFileName, namespace, conditionType, conditionValue string) error {
+	return kc.ResourcesConditionShouldBe(ctx, common.QuantifierAll, resourceFileName, namespace, conditionType, conditionValue)
+}
+
+func (kc *ClientSet) ResourcesConditionShouldBe(ctx context.Context, quantifier, resourcesFileName, namespace, conditionType, conditionValue string) error {
+	namespace, err := kc.resolveNamespaceOverride(ctx, namespace)
+	if err != nil {
+		return err
+	}
+	resources, err := kc.getResources(ctx, resourcesFileName)
+	if err != nil {
+		return err
+	}
+	return unstruct.ResourcesConditionShouldBeInNamespace(kc.DynamicInterface, resources, kc.getWaiterConfig(), quantifier, namespace, conditionType, conditionValue)
+}
+
+func (kc *ClientSet) CustomResourceDefinitionShouldBeEstablished(name string) error {
+	return unstruct.CustomResourceDefinitionShouldBeEstablished(kc.DynamicInterface, kc.getWaiterConfig(), name)
+}
+
+func (kc *ClientSet) UpdateResourceWithField(ctx context.Context, resourceFileName, key, value string) error {
+	return kc.UpdateResourceWithFieldInNamespace(ctx, resourceFileName, "", key, value)
+}
+
+func (kc *ClientSet) UpdateResourceWithFieldInNamespace(ctx context.Context, resourceFileName, namespace, key, value string) error {
+	namespace, err := kc.resolveNamespaceOverride(ctx, namespace)
+	if err != nil {
+		return err
+	}
+	resources, err := kc.getResources(ctx, resourceFileName)
+	if err != nil {
+		return err
+	}
+	for _, resource := range resources {
+		if err := unstruct.UpdateResourceWithFieldInNamespace(kc.DynamicInterface, resource, namespace, key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (kc *ClientSet) DeleteResourcesWithSelector(ctx context.Context, kind, selectorType, selector, namespace string) error {
+	mapping, err := unstruct.GetResourceMapping(kc.getDiscoveryClient(), kind)
+	if err != nil {
+		return err
+	}
+	namespace, err = kc.resolveMappingNamespace(ctx, mapping, namespace)
+	if err != nil {
+		return err
+	}
+	return unstruct.DeleteResourcesWithSelector(kc.DynamicInterface, mapping, namespace, selectorType, selector)
+}
+
+func (kc *ClientSet) ResourcesWithSelectorCountShouldBe(ctx context.Context, kind, selectorType, selector, namespace string, expectedCount int) error {
+	mapping, err := unstruct.GetResourceMapping(kc.getDiscoveryClient(), kind)
+	if err != nil {
+		return err
+	}
+	namespace, err = kc.resolveMappingNamespace(ctx, mapping, namespace)
 	if err != nil {
 		return err
 	}
-	return unstruct.ResourceShouldBe(kc.DynamicInterface, resource, kc.getWaiterConfig(), state)
+	return unstruct.ResourcesWithSelectorCountShouldBe(kc.DynamicInterface, mapping, namespace, selectorType, selector, expectedCount)
 }
 
-func (kc *ClientSet) ResourceShouldConvergeToSelector(resourceFileName, selector string) error {
-	resource, err := unstruct.GetResource(kc.getDiscoveryClient(), kc.config.templateArguments, kc.getResourcePath(resourceFileName))
+func (kc *ClientSet) ResourceShouldHaveMetadata(ctx context.Context, kind, name, namespace, metadataType, expected string) error {
+	mapping, err := unstruct.GetResourceMapping(kc.getDiscoveryClient(), kind)
+	if err != nil {
+		return err
+	}
+	namespace, err = kc.resolveMappingNamespace(ctx, mapping, namespace)
 	if err != nil {
 		return err
 	}
-	return unstruct.ResourceShouldConvergeToSelector(kc.DynamicInterface, resource, kc.getWaiterConfig(), selector)
+	return unstruct.ResourceShouldHaveMetadata(kc.DynamicInterface, mapping, name, namespace, metadataType, expected)
 }
 
-func (kc *ClientSet) ResourceShouldConvergeToField(resourceFileName, selector string) error {
-	resource, err := unstruct.GetResource(kc.getDiscoveryClient(), kc.config.templateArguments, kc.getResourcePath(resourceFileName))
+func (kc *ClientSet) ResourcesWithSelectorShouldHaveMetadata(ctx context.Context, kind, selectorType, selector, namespace, metadataType, expected string) error {
+	mapping, err := unstruct.GetResourceMapping(kc.getDiscoveryClient(), kind)
+	if err != nil {
+		return err
+	}
+	namespace, err = kc.resolveMappingNamespace(ctx, mapping, namespace)
 	if err != nil {
 		return err
 	}
-	return unstruct.ResourceShouldConvergeToField(kc.DynamicInterface, resource, kc.getWaiterConfig(), selector)
+	return unstruct.ResourcesWithSelectorShouldHaveMetadata(kc.DynamicInterface, mapping, namespace, selectorType, selector, metadataType, expected)
 }
 
-func (kc *ClientSet) ResourceConditionShouldBe(resourceFileName, conditionType, conditionValue string) error {
-	resource, err := unstruct.GetResource(kc.getDiscoveryClient(), kc.config.templateArguments, kc.getResourcePath(resourceFileName))
+func (kc *ClientSet) ResourcesWithSelectorShouldReachCount(ctx context.Context, comparison string, expectedCount int, kind, selectorType, selector, namespace string) error {
+	mapping, err := unstruct.GetResourceMapping(kc.getDiscoveryClient(), kind)
 	if err != nil {
 		return err
 	}
-	return unstruct.ResourceConditionShouldBe(kc.DynamicInterface, resource, kc.getWaiterConfig(), conditionType, conditionValue)
+	namespace, err = kc.resolveMappingNamespace(ctx, mapping, namespace)
+	if err != nil {
+		return err
+	}
+	return unstruct.ResourcesWithSelectorShouldReachCount(kc.DynamicInterface, mapping, kc.getWaiterConfig(), namespace, selectorType, selector, comparison, expectedCount)
 }
 
-func (kc *ClientSet) UpdateResourceWithField(resourceFileName, key, value string) error {
-	resource, err := unstruct.GetResource(kc.getDiscoveryClient(), kc.config.templateArguments, kc.getResourcePath(resourceFileName))
+func (kc *ClientSet) ResourcesWithSelectorShouldConvergeToField(ctx context.Context, kind, selectorType, selector, namespace, fieldSelector string) error {
+	mapping, err := unstruct.GetResourceMapping(kc.getDiscoveryClient(), kind)
+	if err != nil {
+		return err
+	}
+	namespace, err = kc.resolveMappingNamespace(ctx, mapping, namespace)
 	if err != nil {
 		return err
 	}
-	return unstruct.UpdateResourceWithField(kc.DynamicInterface, resource, key, value)
+	return unstruct.ResourcesWithSelectorShouldConvergeToField(kc.DynamicInterface, mapping, kc.getWaiterConfig(), namespace, selectorType, selector, fieldSelector)
 }
 
 func (kc *ClientSet) VerifyInstanceGroups() error {
 	return unstruct.VerifyInstanceGroups(kc.DynamicInterface)
 }
 
-func (kc *ClientSet) ListPods(namespace string) error {
+func (kc *ClientSet) ListPods(ctx context.Context, namespace string) error {
+	namespace, err := kc.resolveNamespace(ctx, namespace)
+	if err != nil {
+		return err
+	}
 	// TODO: use ListPodsWithSelector like ListPods does, ListPods is redundant
 	return pod.ListPods(kc.KubeInterface, namespace)
 }
 
-func (kc *ClientSet) ListPodsWithSelector(namespace, selector string) error {
+func (kc *ClientSet) ListPodsWithSelector(ctx context.Context, namespace, selector string) error {
+	namespace, err := kc.resolveNamespace(ctx, namespace)
+	if err != nil {
+		return err
+	}
 	return pod.ListPodsWithSelector(kc.KubeInterface, namespace, selector)
 }
 
-func (kc *ClientSet) PodsWithSelectorHaveRestartCountLessThan(namespace, selector string, restartCount int) error {
+func (kc *ClientSet) PodsWithSelectorHaveRestartCountLessThan(ctx context.Context, namespace, selector string, restartCount int) error {
+	namespace, err := kc.resolveNamespace(ctx, namespace)
+	if err != nil {
+		return err
+	}
 	return pod.PodsWithSelectorHaveRestartCountLessThan(kc.KubeInterface, namespace, selector, restartCount)
 }
 
-func (kc *ClientSet) SomeOrAllPodsInNamespaceWithSelectorHaveStringInLogsSinceTime(someOrAll, namespace, selector, searchKeyword, sinceTime string) error {
+func (kc *ClientSet) PodsWithSelectorCountShouldBe(ctx context.Context, comparison string, expectedCount int, namespace, selector, state string) error {
+	namespace, err := kc.resolveNamespace(ctx, namespace)
+	if err != nil {
+		return err
+	}
+	return pod.PodsWithSelectorCountShouldBe(kc.KubeInterface, kc.getWaiterConfig(), comparison, expectedCount, namespace, selector, state)
+}
+
+func (kc *ClientSet) SomeOrAllPodsInNamespaceWithSelectorHaveStringInLogsSinceTime(ctx context.Context, someOrAll, namespace, selector, searchKeyword, sinceTime string) error {
+	namespace, err := kc.resolveNamespace(ctx, namespace)
+	if err != nil {
+		return err
+	}
 	timestamp, err := kc.GetTimestamp(sinceTime)
 	if err != nil {
 		return err
@@ -240,7 +537,11 @@ func (kc *ClientSet) SomeOrAllPodsInNamespaceWithSelectorHaveStringInLogsSinceTi
 	return pod.SomeOrAllPodsInNamespaceWithSelectorHaveStringInLogsSinceTime(kc.KubeInterface, kc.getExpBackoff(), someOrAll, namespace, selector, searchKeyword, timestamp)
 }
 
-func (kc *ClientSet) SomePodsInNamespaceWithSelectorDontHaveStringInLogsSinceTime(namespace, selector, searchKeyword, sinceTime string) error {
+func (kc *ClientSet) SomePodsInNamespaceWithSelectorDontHaveStringInLogsSinceTime(ctx context.Context, namespace, selector, searchKeyword, sinceTime string) error {
+	namespace, err := kc.resolveNamespace(ctx, namespace)
+	if err != nil {
+		return err
+	}
 	timestamp, err := kc.GetTimestamp(sinceTime)
 	if err != nil {
 		return err
@@ -248,39 +549,383 @@ func (kc *ClientSet) SomePodsInNamespaceWithSelectorDontHaveStringInLogsSinceTim
 	return pod.SomePodsInNamespaceWithSelectorDontHaveStringInLogsSinceTime(kc.KubeInterface, namespace, selector, searchKeyword, timestamp)
 }
 
-func (kc *ClientSet) PodsInNamespaceWithSelectorHaveNoErrorsInLogsSinceTime(namespace, selector, sinceTime string) error {
+func (kc *ClientSet) PodsInNamespaceWithSelectorHaveNoErrorsInLogsSinceTime(ctx context.Context, namespace, selector, sinceTime string) error {
+	namespace, err := kc.resolveNamespace(ctx, namespace)
+	if err != nil {
+		return err
+	}
+	timestamp, err := kc.GetTimestamp(sinceTime)
+	if err != nil {
+		return err
+	}
+	return pod.PodsInNamespaceWithSelectorHaveNoErrorsInLogsSinceTime(kc.KubeInterface, namespace, selector, timestamp, kc.getLogErrorPatterns())
+}
+
+func (kc *ClientSet) PodsInNamespaceWithSelectorHaveSomeErrorsInLogsSinceTime(ctx context.Context, namespace, selector, sinceTime string) error {
+	namespace, err := kc.resolveNamespace(ctx, namespace)
+	if err != nil {
+		return err
+	}
+	timestamp, err := kc.GetTimestamp(sinceTime)
+	if err != nil {
+		return err
+	}
+	return pod.PodsInNamespaceWithSelectorHaveSomeErrorsInLogsSinceTime(kc.KubeInterface, namespace, selector, timestamp, kc.getLogErrorPatterns())
+}
+
+func (kc *ClientSet) PodsInNamespaceWithSelectorShouldHaveLogLinesMatching(ctx context.Context, namespace, selector, comparison string, expectedCount int, matchType, expression, sinceTime string) error {
+	namespace, err := kc.resolveNamespace(ctx, namespace)
+	if err != nil {
+		return err
+	}
+	timestamp, err := kc.GetTimestamp(sinceTime)
+	if err != nil {
+		return err
+	}
+	return pod.PodsInNamespaceWithSelectorShouldHaveLogLinesMatching(kc.KubeInterface, comparison, expectedCount, namespace, selector, matchType, expression, timestamp)
+}
+
+func (kc *ClientSet) ContainerInPodsInNamespaceWithSelectorShouldHaveLogLinesMatching(ctx context.Context, instance, container, namespace, selector, comparison string, expectedCount int, matchType, expression, sinceTime string) error {
+	namespace, err := kc.resolveNamespace(ctx, namespace)
+	if err != nil {
+		return err
+	}
 	timestamp, err := kc.GetTimestamp(sinceTime)
 	if err != nil {
 		return err
 	}
-	return pod.PodsInNamespaceWithSelectorHaveNoErrorsInLogsSinceTime(kc.KubeInterface, namespace, selector, timestamp)
+	return pod.ContainerInPodsInNamespaceWithSelectorShouldHaveLogLinesMatching(kc.KubeInterface, instance, container, comparison, expectedCount, namespace, selector, matchType, expression, timestamp)
 }
 
-func (kc *ClientSet) PodsInNamespaceWithSelectorHaveSomeErrorsInLogsSinceTime(namespace, selector, sinceTime string) error {
+func (kc *ClientSet) PodsInNamespaceWithSelectorShouldLogLineMatching(ctx context.Context, namespace, selector, matchType, expression, sinceTime string, timeout int, timeoutUnits string) error {
+	namespace, err := kc.resolveNamespace(ctx, namespace)
+	if err != nil {
+		return err
+	}
 	timestamp, err := kc.GetTimestamp(sinceTime)
 	if err != nil {
 		return err
 	}
-	return pod.PodsInNamespaceWithSelectorHaveSomeErrorsInLogsSinceTime(kc.KubeInterface, namespace, selector, timestamp)
+	duration, err := util.GetDuration(timeout, timeoutUnits)
+	if err != nil {
+		return err
+	}
+	return pod.PodsInNamespaceWithSelectorShouldLogLineMatching(kc.KubeInterface, kc.getWaiterConfig(), namespace, selector, matchType, expression, timestamp, duration)
+}
+
+func (kc *ClientSet) StartCapturingLogs(ctx context.Context, selector, namespace string) error {
+	namespace, err := kc.resolveNamespace(ctx, namespace)
+	if err != nil {
+		return err
+	}
+	capture, err := pod.StartCapturingLogs(kc.KubeInterface, kc.getWaiterConfig(), namespace, selector, kc.getLogsCaptureDirectory(ctx))
+	if err != nil {
+		return err
+	}
+	scenario := kc.getScenario(ctx)
+	scenario.logCaptures = append(scenario.logCaptures, capture)
+	return nil
+}
+
+func (kc *ClientSet) PodsInNamespaceWithSelectorShouldBeInPhase(ctx context.Context, namespace, selector, phase string) error {
+	namespace, err := kc.resolveNamespace(ctx, namespace)
+	if err != nil {
+		return err
+	}
+	return pod.PodsInNamespaceWithSelectorShouldBeInPhase(kc.KubeInterface, kc.getWaiterConfig(), namespace, selector, phase)
+}
+
+func (kc *ClientSet) ContainerInPodsInNamespaceWithSelectorShouldHaveStateReason(ctx context.Context, container, namespace, selector, reason string) error {
+	namespace, err := kc.resolveNamespace(ctx, namespace)
+	if err != nil {
+		return err
+	}
+	return pod.ContainerInPodsInNamespaceWithSelectorShouldHaveStateReason(kc.KubeInterface, kc.getWaiterConfig(), container, namespace, selector, reason)
+}
+
+func (kc *ClientSet) ContainerInPodsInNamespaceWithSelectorShouldBeReady(ctx context.Context, container, namespace, selector, readiness string) error {
+	namespace, err := kc.resolveNamespace(ctx, namespace)
+	if err != nil {
+		return err
+	}
+	return pod.ContainerInPodsInNamespaceWithSelectorShouldBeReady(kc.KubeInterface, kc.getWaiterConfig(), container, namespace, selector, readiness)
+}
+
+func (kc *ClientSet) PodsInNamespaceWithSelectorShouldBeScheduledOnNodesWithSelector(ctx context.Context, namespace, selector, nodeSelector string) error {
+	namespace, err := kc.resolveNamespace(ctx, namespace)
+	if err != nil {
+		return err
+	}
+	return pod.PodsInNamespaceWithSelectorShouldBeScheduledOnNodesWithSelector(kc.KubeInterface, kc.getWaiterConfig(), namespace, selector, nodeSelector)
+}
+
+func (kc *ClientSet) PodsInNamespaceWithSelectorShouldSatisfyNodeAffinity(ctx context.Context, namespace, selector string) error {
+	namespace, err := kc.resolveNamespace(ctx, namespace)
+	if err != nil {
+		return err
+	}
+	return pod.PodsInNamespaceWithSelectorShouldSatisfyNodeAffinity(kc.KubeInterface, kc.getWaiterConfig(), namespace, selector)
+}
+
+func (kc *ClientSet) PodsInNamespaceWithSelectorShouldHaveQOSClass(ctx context.Context, namespace, selector, qosClass string) error {
+	namespace, err := kc.resolveNamespace(ctx, namespace)
+	if err != nil {
+		return err
+	}
+	return pod.PodsInNamespaceWithSelectorShouldHaveQOSClass(kc.KubeInterface, kc.getWaiterConfig(), namespace, selector, qosClass)
 }
 
-func (kc *ClientSet) PodsInNamespaceWithLabelSelectorConvergeToFieldSelector(namespace, labelSelector, fieldSelector string) error {
+func (kc *ClientSet) ContainerInPodsInNamespaceWithSelectorShouldRunImage(ctx context.Context, container, namespace, selector, image string) error {
+	namespace, err := kc.resolveNamespace(ctx, namespace)
+	if err != nil {
+		return err
+	}
+	return pod.ContainerInPodsInNamespaceWithSelectorShouldRunImage(kc.KubeInterface, kc.getWaiterConfig(), container, namespace, selector, image)
+}
+
+func (kc *ClientSet) StopCapturingLogs(ctx context.Context) error {
+	scenario := kc.getScenario(ctx)
+	var stopErr error
+	for _, capture := range scenario.logCaptures {
+		if err := capture.Stop(); err != nil && stopErr == nil {
+			stopErr = err
+		}
+	}
+	scenario.logCaptures = nil
+	return stopErr
+}
+
+func (kc *ClientSet) PodsInNamespaceWithLabelSelectorConvergeToFieldSelector(ctx context.Context, namespace, labelSelector, fieldSelector string) error {
+	namespace, err := kc.resolveNamespace(ctx, namespace)
+	if err != nil {
+		return err
+	}
 	return pod.PodsInNamespaceWithLabelSelectorConvergeToFieldSelector(kc.KubeInterface, kc.getExpBackoff(), namespace, labelSelector, fieldSelector)
 }
 
-func (kc *ClientSet) PodsInNamespaceWithSelectorShouldHaveLabels(namespace, selector, labels string) error {
+func (kc *ClientSet) PodsInNamespaceWithSelectorShouldHaveLabels(ctx context.Context, namespace, selector, labels string) error {
+	namespace, err := kc.resolveNamespace(ctx, namespace)
+	if err != nil {
+		return err
+	}
 	return pod.PodsInNamespaceWithSelectorShouldHaveLabels(kc.KubeInterface, namespace, selector, labels)
 }
 
-func (kc *ClientSet) PodInNamespaceShouldHaveLabels(name, namespace, labels string) error {
+func (kc *ClientSet) PodInNamespaceShouldHaveLabels(ctx context.Context, name, namespace, labels string) error {
+	namespace, err := kc.resolveNamespace(ctx, namespace)
+	if err != nil {
+		return err
+	}
 	return pod.PodInNamespaceShouldHaveLabels(kc.KubeInterface, name, namespace, labels)
 }
 
-func (kc *ClientSet) SecretOperationFromEnvironmentVariable(operation, name, namespace, environmentVariable string) error {
+func (kc *ClientSet) CordonNode(operation, name string) error {
+	return node.CordonNode(kc.KubeInterface, operation, name)
+}
+
+func (kc *ClientSet) CordonNodesWithSelector(operation, selector string) error {
+	return node.CordonNodesWithSelector(kc.KubeInterface, operation, selector)
+}
+
+func (kc *ClientSet) DrainNode(name string, timeout int, timeoutUnits string) error {
+	duration, err := util.GetDuration(timeout, timeoutUnits)
+	if err != nil {
+		return err
+	}
+	return node.DrainNode(kc.KubeInterface, kc.getWaiterConfig(), name, duration)
+}
+
+func (kc *ClientSet) DrainNodesWithSelector(selector string, timeout int, timeoutUnits string) error {
+	duration, err := util.GetDuration(timeout, timeoutUnits)
+	if err != nil {
+		return err
+	}
+	return node.DrainNodesWithSelector(kc.KubeInterface, kc.getWaiterConfig(), selector, duration)
+}
+
+func (kc *ClientSet) NodeTaintOperation(operation, taint, name string) error {
+	return node.NodeTaintOperation(kc.KubeInterface, operation, taint, name)
+}
+
+func (kc *ClientSet) NodeLabelOperation(operation, label, name string) error {
+	return node.NodeLabelOperation(kc.KubeInterface, operation, label, name)
+}
+
+func (kc *ClientSet) NodeConditionShouldBe(name, conditionType, conditionStatus string) error {
+	return node.NodeConditionShouldBe(kc.KubeInterface, name, conditionType, conditionStatus)
+}
+
+func (kc *ClientSet) NodesWithSelectorConditionShouldBe(selector, conditionType, conditionStatus string) error {
+	return node.NodesWithSelectorConditionShouldBe(kc.KubeInterface, selector, conditionType, conditionStatus)
+}
+
+func (kc *ClientSet) NodesWithSelectorShouldHaveKubeletVersion(selector, version string) error {
+	return node.NodesWithSelectorShouldHaveKubeletVersion(kc.KubeInterface, selector, version)
+}
+
+func (kc *ClientSet) NodesWithSelectorShouldHaveResource(selector, comparison, quantity, resourceType, resourceName string) error {
+	return node.NodesWithSelectorShouldHaveResource(kc.KubeInterface, selector, comparison, quantity, resourceType, resourceName)
+}
+
+func (kc *ClientSet) NodesWithSelectorShouldBeSpreadAcrossZones(selector, comparison string, expectedZones int) error {
+	return node.NodesWithSelectorShouldBeSpreadAcrossZones(kc.KubeInterface, selector, comparison, expectedZones)
+}
+
+func (kc *ClientSet) EvictPodInNamespace(ctx context.Context, name, namespace, expectedResult string) error {
+	namespace, err := kc.resolveNamespace(ctx, namespace)
+	if err != nil {
+		return err
+	}
+	return pod.EvictPodInNamespace(kc.KubeInterface, name, namespace, expectedResult)
+}
+
+func (kc *ClientSet) EvictPodsInNamespaceWithSelector(ctx context.Context, count int, namespace, selector, expectedResult string) error {
+	namespace, err := kc.resolveNamespace(ctx, namespace)
+	if err != nil {
+		return err
+	}
+	return pod.EvictPodsInNamespaceWithSelector(kc.KubeInterface, count, namespace, selector, expectedResult)
+}
+
+func (kc *ClientSet) KillRandomPodsInNamespaceWithSelector(ctx context.Context, mode string, amount int, unit, namespace, selector string) error {
+	namespace, err := kc.resolveNamespace(ctx, namespace)
+	if err != nil {
+		return err
+	}
+	killed, err := pod.KillRandomPodsInNamespaceWithSelector(kc.KubeInterface, mode, amount, unit, namespace, selector)
+	scenario := kc.getScenario(ctx)
+	scenario.killedPods = append(scenario.killedPods, killed...)
+	return err
+}
+
+func (kc *ClientSet) PodsInNamespaceWithSelectorShouldRecover(ctx context.Context, namespace, selector string, timeout int, timeoutUnits string) error {
+	namespace, err := kc.resolveNamespace(ctx, namespace)
+	if err != nil {
+		return err
+	}
+	duration, err := util.GetDuration(timeout, timeoutUnits)
+	if err != nil {
+		return err
+	}
+	return pod.PodsInNamespaceWithSelectorShouldRecover(kc.KubeInterface, kc.getWaiterConfig(), namespace, selector, kc.getScenario(ctx).killedPods, duration)
+}
+
+func (kc *ClientSet) PodsInNamespaceWithSelectorShouldRecoverWithRestartCountLessThan(ctx context.Context, namespace, selector string, timeout int, timeoutUnits string, restartCount int) error {
+	namespace, err := kc.resolveNamespace(ctx, namespace)
+	if err != nil {
+		return err
+	}
+	if err := kc.PodsInNamespaceWithSelectorShouldRecover(ctx, namespace, selector, timeout, timeoutUnits); err != nil {
+		return err
+	}
+	return kc.PodsWithSelectorHaveRestartCountLessThan(ctx, namespace, selector, restartCount)
+}
+
+// AttachKilledPods attaches the pods killed during the scenario to the godog report
+func (kc *ClientSet) AttachKilledPods(ctx context.Context) context.Context {
+	killedPods := kc.getScenario(ctx).killedPods
+	if len(killedPods) == 0 {
+		return ctx
+	}
+	var table bytes.Buffer
+	w := tabwriter.NewWriter(&table, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "NAMESPACE\tNAME\tUID\tNODE")
+	for _, p := range killedPods {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", p.Namespace, p.Name, p.UID, p.Spec.NodeName)
+	}
+	w.Flush()
+	return godog.Attach(ctx, godog.Attachment{
+		Body:      table.Bytes(),
+		FileName:  "killed-pods.txt",
+		MediaType: "text/plain",
+	})
+}
+
+// UseNamespace sets the namespace that steps and namespaceless manifests fall back to for the rest of the scenario
+func (kc *ClientSet) UseNamespace(ctx context.Context, namespace string) error {
+	namespace, err := kc.resolveNamespace(ctx, namespace)
+	if err != nil {
+		return err
+	}
+	kc.getScenario(ctx).namespace = namespace
+	log.Infof("using namespace '%s'", namespace)
+	return nil
+}
+
+func (kc *ClientSet) ConnectivityShouldBe(ctx context.Context, sourceSelector, sourceNamespace, connectivity, targetKind, target, targetNamespace string, port int) error {
+	sourceNamespace, err := kc.resolveNamespace(ctx, sourceNamespace)
+	if err != nil {
+		return err
+	}
+	targetNamespace, err = kc.resolveNamespace(ctx, targetNamespace)
+	if err != nil {
+		return err
+	}
+	check := network.ConnectivityCheck{
+		SourceNamespace: sourceNamespace,
+		SourceSelector:  sourceSelector,
+		TargetKind:      targetKind,
+		Target:          target,
+		TargetNamespace: targetNamespace,
+		Port:            port,
+		Connectivity:    connectivity,
+	}
+	return network.ConnectivityShouldBe(kc.KubeInterface, kc.getWaiterConfig(), kc.getConnectivityProbeImage(), check)
+}
+
+func (kc *ClientSet) ConnectivityMatrixShouldBe(ctx context.Context, table *godog.Table) error {
+	checks, err := network.NewConnectivityChecks(getTableRows(table))
+	if err != nil {
+		return err
+	}
+	for i := range checks {
+		if checks[i].SourceNamespace, err = kc.resolveNamespace(ctx, checks[i].SourceNamespace); err != nil {
+			return err
+		}
+		if checks[i].TargetNamespace, err = kc.resolveNamespace(ctx, checks[i].TargetNamespace); err != nil {
+			return err
+		}
+	}
+	return network.ConnectivityMatrixShouldBe(kc.KubeInterface, kc.getWaiterConfig(), kc.getConnectivityProbeImage(), checks)
+}
+
+func (kc *ClientSet) SecretOperationFromEnvironmentVariable(ctx context.Context, operation, name, namespace, environmentVariable string) error {
+	namespace, err := kc.resolveNamespace(ctx, namespace)
+	if err != nil {
+		return err
+	}
 	return structured.SecretOperationFromEnvironmentVariable(kc.KubeInterface, operation, name, namespace, environmentVariable)
 }
 
-func (kc *ClientSet) SecretDelete(name, namespace string) error {
+func (kc *ClientSet) SecretOperationFromTable(ctx context.Context, operation, name, namespace string, table *godog.Table) error {
+	namespace, err := kc.resolveNamespace(ctx, namespace)
+	if err != nil {
+		return err
+	}
+	return kc.SecretOfTypeOperationFromTable(ctx, operation, name, string(corev1.SecretTypeOpaque), namespace, table)
+}
+
+func (kc *ClientSet) SecretOfTypeOperationFromTable(ctx context.Context, operation, name, secretType, namespace string, table *godog.Table) error {
+	namespace, err := kc.resolveNamespace(ctx, namespace)
+	if err != nil {
+		return err
+	}
+	return structured.SecretOperationFromTable(kc.KubeInterface, operation, secretType, name, namespace, getTableRows(table), kc.getTemplatesPath(), kc.getTemplateArguments(ctx))
+}
+
+func (kc *ClientSet) SecretOperationWithSelfSignedCertificate(ctx context.Context, operation, name, namespace, hosts string) error {
+	namespace, err := kc.resolveNamespace(ctx, namespace)
+	if err != nil {
+		return err
+	}
+	return structured.SecretOperationWithSelfSignedCertificate(kc.KubeInterface, operation, name, namespace, hosts)
+}
+
+func (kc *ClientSet) SecretDelete(ctx context.Context, name, namespace string) error {
+	namespace, err := kc.resolveNamespace(ctx, namespace)
+	if err != nil {
+		return err
+	}
 	// TODO: use SecretOperationFromEnvironmentVariable directly like SecretDelete does, SecretDelete is redundant
 	return structured.SecretDelete(kc.KubeInterface, name, namespace)
 }
@@ -289,7 +934,151 @@ func (kc *ClientSet) NodesWithSelectorShouldBe(expectedNodes int, selector, stat
 	return structured.NodesWithSelectorShouldBe(kc.KubeInterface, kc.getWaiterConfig(), expectedNodes, selector, state)
 }
 
-func (kc *ClientSet) ResourceInNamespace(resourceType, name, isOrIsNot, namespace string) error {
+func (kc *ClientSet) JobsWithSelectorCountShouldBe(ctx context.Context, comparison string, expectedCount int, namespace, selector, state string) error {
+	namespace, err := kc.resolveNamespace(ctx, namespace)
+	if err != nil {
+		return err
+	}
+	return structured.JobsWithSelectorCountShouldBe(kc.KubeInterface, kc.getWaiterConfig(), comparison, expectedCount, namespace, selector, state)
+}
+
+func (kc *ClientSet) CreateJobFromCronJob(ctx context.Context, jobName, cronJobName, namespace string) error {
+	namespace, err := kc.resolveNamespace(ctx, namespace)
+	if err != nil {
+		return err
+	}
+	return structured.CreateJobFromCronJob(kc.KubeInterface, jobName, cronJobName, namespace)
+}
+
+func (kc *ClientSet) JobShouldFinish(ctx context.Context, name, namespace, outcome string) error {
+	namespace, err := kc.resolveNamespace(ctx, namespace)
+	if err != nil {
+		return err
+	}
+	return structured.JobShouldFinish(kc.KubeInterface, kc.getWaiterConfig(), name, namespace, outcome)
+}
+
+func (kc *ClientSet) JobShouldHavePodCount(ctx context.Context, name, namespace, comparison string, expectedCount int, podStatus string) error {
+	namespace, err := kc.resolveNamespace(ctx, namespace)
+	if err != nil {
+		return err
+	}
+	return structured.JobShouldHavePodCount(kc.KubeInterface, kc.getWaiterConfig(), name, namespace, comparison, expectedCount, podStatus)
+}
+
+func (kc *ClientSet) JobShouldExceedBackoffLimit(ctx context.Context, name, namespace string) error {
+	namespace, err := kc.resolveNamespace(ctx, namespace)
+	if err != nil {
+		return err
+	}
+	return structured.JobShouldExceedBackoffLimit(kc.KubeInterface, kc.getWaiterConfig(), name, namespace)
+}
+
+func (kc *ClientSet) CronJobOperation(ctx context.Context, operation, name, namespace string) error {
+	namespace, err := kc.resolveNamespace(ctx, namespace)
+	if err != nil {
+		return err
+	}
+	return structured.CronJobOperation(kc.KubeInterface, operation, name, namespace)
+}
+
+func (kc *ClientSet) PersistentVolumeClaimsWithSelectorCountShouldBe(ctx context.Context, comparison string, expectedCount int, namespace, selector, state string) error {
+	namespace, err := kc.resolveNamespace(ctx, namespace)
+	if err != nil {
+		return err
+	}
+	return structured.PersistentVolumeClaimsWithSelectorCountShouldBe(kc.KubeInterface, kc.getWaiterConfig(), comparison, expectedCount, namespace, selector, state)
+}
+
+func (kc *ClientSet) PodDisruptionBudgetStatusShouldBe(ctx context.Context, name, namespace, comparison string, expectedValue int, statusField string) error {
+	namespace, err := kc.resolveNamespace(ctx, namespace)
+	if err != nil {
+		return err
+	}
+	return structured.PodDisruptionBudgetStatusShouldBe(kc.KubeInterface, kc.getWaiterConfig(), name, namespace, comparison, expectedValue, statusField)
+}
+
+func (kc *ClientSet) HorizontalPodAutoscalerReplicasShouldBe(ctx context.Context, name, namespace, comparison string, expectedValue int, replicasField string) error {
+	namespace, err := kc.resolveNamespace(ctx, namespace)
+	if err != nil {
+		return err
+	}
+	return structured.HorizontalPodAutoscalerReplicasShouldBe(kc.KubeInterface, kc.getWaiterConfig(), name, namespace, comparison, expectedValue, replicasField)
+}
+
+func (kc *ClientSet) HorizontalPodAutoscalerShouldScale(ctx context.Context, name, namespace, direction string, timeout int, timeoutUnits string) error {
+	namespace, err := kc.resolveNamespace(ctx, namespace)
+	if err != nil {
+		return err
+	}
+	duration, err := util.GetDuration(timeout, timeoutUnits)
+	if err != nil {
+		return err
+	}
+	return structured.HorizontalPodAutoscalerShouldScale(kc.KubeInterface, kc.getWaiterConfig(), name, namespace, direction, duration)
+}
+
+func (kc *ClientSet) HorizontalPodAutoscalerConditionShouldBe(ctx context.Context, name, namespace, conditionType, expectedStatus string) error {
+	namespace, err := kc.resolveNamespace(ctx, namespace)
+	if err != nil {
+		return err
+	}
+	return structured.HorizontalPodAutoscalerConditionShouldBe(kc.KubeInterface, kc.getWaiterConfig(), name, namespace, conditionType, expectedStatus)
+}
+
+func (kc *ClientSet) ServiceShouldHaveReadyEndpoints(ctx context.Context, name, namespace, comparison string, expectedCount int) error {
+	namespace, err := kc.resolveNamespace(ctx, namespace)
+	if err != nil {
+		return err
+	}
+	return structured.ServiceShouldHaveReadyEndpoints(kc.KubeInterface, kc.getWaiterConfig(), name, namespace, comparison, expectedCount)
+}
+
+func (kc *ClientSet) ServiceShouldSelectPodsWithSelector(ctx context.Context, name, namespace, selector string) error {
+	namespace, err := kc.resolveNamespace(ctx, namespace)
+	if err != nil {
+		return err
+	}
+	return structured.ServiceShouldSelectPodsWithSelector(kc.KubeInterface, name, namespace, selector)
+}
+
+func (kc *ClientSet) ServiceShouldHaveLoadBalancerIngress(ctx context.Context, name, namespace string) error {
+	namespace, err := kc.resolveNamespace(ctx, namespace)
+	if err != nil {
+		return err
+	}
+	return structured.ServiceShouldHaveLoadBalancerIngress(kc.KubeInterface, kc.getWaiterConfig(), name, namespace)
+}
+
+func (kc *ClientSet) ServiceShouldBeOfType(ctx context.Context, name, namespace, serviceType string) error {
+	namespace, err := kc.resolveNamespace(ctx, namespace)
+	if err != nil {
+		return err
+	}
+	return structured.ServiceShouldBeOfType(kc.KubeInterface, name, namespace, serviceType)
+}
+
+func (kc *ClientSet) ServiceShouldHavePort(ctx context.Context, name, namespace, protocol string, port int32, targetPort string) error {
+	namespace, err := kc.resolveNamespace(ctx, namespace)
+	if err != nil {
+		return err
+	}
+	return structured.ServiceShouldHavePort(kc.KubeInterface, name, namespace, protocol, port, targetPort)
+}
+
+func (kc *ClientSet) ServiceShouldHaveNodePort(ctx context.Context, name, namespace string, nodePort int32, protocol string, port int32) error {
+	namespace, err := kc.resolveNamespace(ctx, namespace)
+	if err != nil {
+		return err
+	}
+	return structured.ServiceShouldHaveNodePort(kc.KubeInterface, name, namespace, nodePort, protocol, port)
+}
+
+func (kc *ClientSet) ResourceInNamespace(ctx context.Context, resourceType, name, isOrIsNot, namespace string) error {
+	namespace, err := kc.resolveNamespace(ctx, namespace)
+	if err != nil {
+		return err
+	}
 	switch isOrIsNot {
 	case "is":
 		return structured.ResourceInNamespace(kc.KubeInterface, resourceType, name, namespace)
@@ -300,11 +1089,19 @@ func (kc *ClientSet) ResourceInNamespace(resourceType, name, isOrIsNot, namespac
 	}
 }
 
-func (kc *ClientSet) ScaleDeployment(name, namespace string, replicas int32) error {
+func (kc *ClientSet) ScaleDeployment(ctx context.Context, name, namespace string, replicas int32) error {
+	namespace, err := kc.resolveNamespace(ctx, namespace)
+	if err != nil {
+		return err
+	}
 	return structured.ScaleDeployment(kc.KubeInterface, name, namespace, replicas)
 }
 
-func (kc *ClientSet) ValidatePrometheusVolumeClaimTemplatesName(statefulsetName, namespace, volumeClaimTemplatesName string) error {
+func (kc *ClientSet) ValidatePrometheusVolumeClaimTemplatesName(ctx context.Context, statefulsetName, namespace, volumeClaimTemplatesName string) error {
+	namespace, err := kc.resolveNamespace(ctx, namespace)
+	if err != nil {
+		return err
+	}
 	return structured.ValidatePrometheusVolumeClaimTemplatesName(kc.KubeInterface, statefulsetName, namespace, volumeClaimTemplatesName)
 }
 
@@ -312,23 +1109,86 @@ func (kc *ClientSet) ListNodes() error {
 	return structured.ListNodes(kc.KubeInterface)
 }
 
-func (kc *ClientSet) DaemonSetIsRunning(name, namespace string) error {
+// ListNodesWithAttachment lists the nodes like ListNodes, and attaches the table to the godog report
+func (kc *ClientSet) ListNodesWithAttachment(ctx context.Context) (context.Context, error) {
+	table, err := structured.GetNodesTable(kc.KubeInterface)
+	if err != nil {
+		return ctx, err
+	}
+	return godog.Attach(ctx, godog.Attachment{
+		Body:      []byte(table),
+		FileName:  "nodes.txt",
+		MediaType: "text/plain",
+	}), nil
+}
+
+func (kc *ClientSet) DaemonSetIsRunning(ctx context.Context, name, namespace string) error {
+	namespace, err := kc.resolveNamespace(ctx, namespace)
+	if err != nil {
+		return err
+	}
 	return structured.DaemonSetIsRunning(kc.KubeInterface, kc.getExpBackoff(), name, namespace)
 }
 
-func (kc *ClientSet) DeploymentIsRunning(name, namespace string) error {
+func (kc *ClientSet) DeploymentIsRunning(ctx context.Context, name, namespace string) error {
+	namespace, err := kc.resolveNamespace(ctx, namespace)
+	if err != nil {
+		return err
+	}
 	return structured.DeploymentIsRunning(kc.KubeInterface, name, namespace)
 }
 
-func (kc *ClientSet) ConfigMapDataHasKeyAndValue(name, namespace, key, value string) error {
+func (kc *ClientSet) ConfigMapDataHasKeyAndValue(ctx context.Context, name, namespace, key, value string) error {
+	namespace, err := kc.resolveNamespace(ctx, namespace)
+	if err != nil {
+		return err
+	}
 	return structured.ConfigMapDataHasKeyAndValue(kc.KubeInterface, name, namespace, key, value)
 }
 
+// DataKeyShouldMatch redacts secret values in its messages, but the expected value is part of the step text and is
+// reported as is, use DataKeyShouldMatchEnvironmentVariable or DataKeyShouldMatchFile to keep it out of the report
+func (kc *ClientSet) DataKeyShouldMatch(ctx context.Context, kind, name, namespace, key, matchType, expected string) error {
+	namespace, err := kc.resolveNamespace(ctx, namespace)
+	if err != nil {
+		return err
+	}
+	return structured.DataKeyShouldMatch(kc.KubeInterface, kind, name, namespace, key, matchType, expected)
+}
+
+func (kc *ClientSet) DataKeyShouldMatchEnvironmentVariable(ctx context.Context, kind, name, namespace, key, matchType, environmentVariable string) error {
+	namespace, err := kc.resolveNamespace(ctx, namespace)
+	if err != nil {
+		return err
+	}
+	return structured.DataKeyShouldMatchEnvironmentVariable(kc.KubeInterface, kind, name, namespace, key, matchType, environmentVariable)
+}
+
+func (kc *ClientSet) DataKeyShouldMatchFile(ctx context.Context, kind, name, namespace, key, fileName string) error {
+	namespace, err := kc.resolveNamespace(ctx, namespace)
+	if err != nil {
+		return err
+	}
+	return structured.DataKeyShouldMatchFile(kc.KubeInterface, kind, name, namespace, key, kc.getResourcePath(fileName), kc.getTemplateArguments(ctx))
+}
+
+func (kc *ClientSet) DataKeyAtPathShouldMatch(ctx context.Context, kind, name, namespace, key, format, path, matchType, expected string) error {
+	namespace, err := kc.resolveNamespace(ctx, namespace)
+	if err != nil {
+		return err
+	}
+	return structured.DataKeyAtPathShouldMatch(kc.KubeInterface, kind, name, namespace, key, format, path, matchType, expected)
+}
+
 func (kc *ClientSet) PersistentVolExists(name, expectedPhase string) error {
 	return structured.PersistentVolExists(kc.KubeInterface, name, expectedPhase)
 }
 
-func (kc *ClientSet) PersistentVolClaimExists(name, expectedPhase string, namespace string) error {
+func (kc *ClientSet) PersistentVolClaimExists(ctx context.Context, name, expectedPhase string, namespace string) error {
+	namespace, err := kc.resolveNamespace(ctx, namespace)
+	if err != nil {
+		return err
+	}
 	return structured.PersistentVolClaimExists(kc.KubeInterface, name, expectedPhase, namespace)
 }
 
@@ -336,10 +1196,18 @@ func (kc *ClientSet) ClusterRbacIsFound(resourceType, name string) error {
 	return structured.ClusterRbacIsFound(kc.KubeInterface, resourceType, name)
 }
 
-func (kc *ClientSet) IngressAvailable(name, namespace string, port int, path string) error {
+func (kc *ClientSet) IngressAvailable(ctx context.Context, name, namespace string, port int, path string) error {
+	namespace, err := kc.resolveNamespace(ctx, namespace)
+	if err != nil {
+		return err
+	}
 	return structured.IngressAvailable(kc.KubeInterface, kc.getWaiterConfig(), name, namespace, port, path)
 }
 
-func (kc *ClientSet) SendTrafficToIngress(tps int, name, namespace string, port int, path string, duration int, durationUnits string, expectedErrors int) error {
+func (kc *ClientSet) SendTrafficToIngress(ctx context.Context, tps int, name, namespace string, port int, path string, duration int, durationUnits string, expectedErrors int) error {
+	namespace, err := kc.resolveNamespace(ctx, namespace)
+	if err != nil {
+		return err
+	}
 	return structured.SendTrafficToIngress(kc.KubeInterface, kc.getWaiterConfig(), tps, name, namespace, port, path, duration, durationUnits, expectedErrors)
 }
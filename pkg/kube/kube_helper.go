@@ -15,25 +15,65 @@ limitations under the License.
 package kube
 
 import (
+	"context"
 	"fmt"
+	"html/template"
 	"path/filepath"
+	"regexp"
+	"strings"
 	"time"
 
+	"github.com/cucumber/godog"
 	"github.com/keikoproj/kubedog/internal/util"
 	"github.com/keikoproj/kubedog/pkg/kube/common"
+	"github.com/keikoproj/kubedog/pkg/kube/pod"
+	unstruct "github.com/keikoproj/kubedog/pkg/kube/unstructured"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/disk"
+	"k8s.io/client-go/rest"
 )
 
+const (
+	ephemeralNamespaceVariable     = "$EPHEMERAL_NAMESPACE"
+	ephemeralNamespaceLabel        = "kubedog.keikoproj.io/ephemeral"
+	ephemeralNamespaceSuffixLength = 6
+)
+
+type scenarioKey struct{}
+
+// scenarioState is what a scenario keeps apart from the scenarios running along with it
+type scenarioState struct {
+	name               string
+	id                 string
+	namespace          string
+	ephemeralNamespace string
+	killedPods         []corev1.Pod
+	logCaptures        []*pod.LogCapture
+}
+
 type configuration struct {
-	filesPath         string
-	templateArguments interface{}
-	waiterInterval    time.Duration
-	waiterTries       int
+	filesPath                string
+	templateArguments        interface{}
+	waiterInterval           time.Duration
+	waiterTries              int
+	logErrorPatterns         []string
+	logsCapturePath          string
+	connectivityProbeImage   string
+	ephemeralNamespacePrefix string
+	ephemeralNamespaceLabels map[string]string
+	defaultNamespace         string
+	discoveryCacheDir        string
+	discoveryCacheTTL        time.Duration
 }
 
 func (kc *ClientSet) GetTimestamp(timestampName string) (time.Time, error) {
+	kc.lock.Lock()
+	defer kc.lock.Unlock()
 	commonErrorMessage := fmt.Sprintf("failed getting timestamp '%s'", timestampName)
 	if kc.timestamps == nil {
 		return time.Time{}, errors.Errorf("%s: 'ClientSet.Timestamps' is nil", commonErrorMessage)
@@ -74,6 +114,174 @@ func (kc *ClientSet) getWaiterTries() int {
 	return defaultWaiterTries
 }
 
+func (kc *ClientSet) getLogsCapturePath() string {
+	defaultLogsCapturePath := "logs"
+	if kc.config.logsCapturePath != "" {
+		return kc.config.logsCapturePath
+	}
+	return defaultLogsCapturePath
+}
+
+// getLogsCaptureDirectory returns a directory per scenario, named after it and its id so outline examples do not collide
+func (kc *ClientSet) getLogsCaptureDirectory(ctx context.Context) string {
+	scenario := kc.getScenario(ctx)
+	invalidCharacters := regexp.MustCompile(`[^A-Za-z0-9_.-]+`)
+	scenarioDirectory := strings.Trim(invalidCharacters.ReplaceAllString(scenario.name+"_"+scenario.id, "_"), "_")
+	return filepath.Join(kc.getLogsCapturePath(), scenarioDirectory)
+}
+
+func (kc *ClientSet) getLogErrorPatterns() []string {
+	defaultLogErrorPatterns := []string{regexp.QuoteMeta(`"level":"error"`), regexp.QuoteMeta("level=error")}
+	if len(kc.config.logErrorPatterns) > 0 {
+		return kc.config.logErrorPatterns
+	}
+	return defaultLogErrorPatterns
+}
+
+func (kc *ClientSet) getConnectivityProbeImage() string {
+	defaultConnectivityProbeImage := "busybox:1.36"
+	if kc.config.connectivityProbeImage != "" {
+		return kc.config.connectivityProbeImage
+	}
+	return defaultConnectivityProbeImage
+}
+
+func (kc *ClientSet) getEphemeralNamespacePrefix() string {
+	defaultEphemeralNamespacePrefix := "kubedog"
+	if kc.config.ephemeralNamespacePrefix != "" {
+		return kc.config.ephemeralNamespacePrefix
+	}
+	return defaultEphemeralNamespacePrefix
+}
+
+func (kc *ClientSet) getDiscoveryCacheTTL() time.Duration {
+	defaultDiscoveryCacheTTL := time.Hour * 6
+	if kc.config.discoveryCacheTTL > 0 {
+		return kc.config.discoveryCacheTTL
+	}
+	return defaultDiscoveryCacheTTL
+}
+
+// newDiskCachedDiscoveryClient persists discovery between runs in a directory per cluster, the same layout as kubectl's
+// cache directory so it can be shared with it
+func (kc *ClientSet) newDiskCachedDiscoveryClient(config *rest.Config) (discovery.CachedDiscoveryInterface, error) {
+	invalidCharacters := regexp.MustCompile(`[^(\w/.)]`)
+	host := strings.TrimPrefix(strings.TrimPrefix(config.Host, "https://"), "http://")
+	discoveryCacheDir := filepath.Join(kc.config.discoveryCacheDir, "discovery", invalidCharacters.ReplaceAllString(host, "_"))
+	httpCacheDir := filepath.Join(kc.config.discoveryCacheDir, "http")
+	return disk.NewCachedDiscoveryClientForConfig(config, discoveryCacheDir, httpCacheDir, kc.getDiscoveryCacheTTL())
+}
+
+// getScenario returns the state of the scenario running the step, steps run outside of a scenario share a state of
+// their own
+func (kc *ClientSet) getScenario(ctx context.Context) *scenarioState {
+	kc.lock.Lock()
+	defer kc.lock.Unlock()
+	id, _ := ctx.Value(scenarioKey{}).(string)
+	if kc.scenarios == nil {
+		kc.scenarios = map[string]*scenarioState{}
+	}
+	scenario, ok := kc.scenarios[id]
+	if !ok {
+		scenario = &scenarioState{id: id}
+		kc.scenarios[id] = scenario
+	}
+	return scenario
+}
+
+// getDefaultNamespace returns the namespace used by the scenario, if any, otherwise the configured default namespace
+func (kc *ClientSet) getDefaultNamespace(ctx context.Context) string {
+	if namespace := kc.getScenario(ctx).namespace; namespace != "" {
+		return namespace
+	}
+	if kc.config.defaultNamespace != "" {
+		return kc.config.defaultNamespace
+	}
+	return metav1.NamespaceDefault
+}
+
+// resolveNamespace replaces the ephemeral namespace variable with the namespace created for the scenario, and an
+// omitted namespace with the default namespace
+func (kc *ClientSet) resolveNamespace(ctx context.Context, namespace string) (string, error) {
+	if namespace == "" {
+		namespace = kc.getDefaultNamespace(ctx)
+	}
+	if namespace != ephemeralNamespaceVariable {
+		return namespace, nil
+	}
+	ephemeralNamespace := kc.getScenario(ctx).ephemeralNamespace
+	if ephemeralNamespace == "" {
+		return "", errors.New("ephemeral namespace not created in this scenario")
+	}
+	return ephemeralNamespace, nil
+}
+
+// resolveNamespaceOverride resolves a namespace overriding the manifests', when omitted the manifests' namespace is kept
+func (kc *ClientSet) resolveNamespaceOverride(ctx context.Context, namespace string) (string, error) {
+	if namespace == "" {
+		return "", nil
+	}
+	return kc.resolveNamespace(ctx, namespace)
+}
+
+// resolveMappingNamespace resolves the namespace of namespaced resources, cluster scoped resources have none
+func (kc *ClientSet) resolveMappingNamespace(ctx context.Context, mapping *meta.RESTMapping, namespace string) (string, error) {
+	if mapping.Scope != nil && mapping.Scope.Name() == meta.RESTScopeNameRoot {
+		return "", nil
+	}
+	return kc.resolveNamespace(ctx, namespace)
+}
+
+// getTemplateArguments adds the functions templates can call to the template arguments, manifests are only rendered when
+// template arguments are set. '{{ ephemeralNamespace }}' renders the namespace created for the scenario, and outside of a
+// scenario, e.g. when cleaning up after the suite, the one created outside of scenarios if any.
+func (kc *ClientSet) getTemplateArguments(ctx context.Context) interface{} {
+	if kc.config.templateArguments == nil {
+		return nil
+	}
+	return common.TemplateArguments{
+		Arguments: kc.config.templateArguments,
+		Funcs: template.FuncMap{
+			"ephemeralNamespace": func() (string, error) {
+				if _, ok := ctx.Value(scenarioKey{}).(string); !ok {
+					return kc.getScenario(ctx).ephemeralNamespace, nil
+				}
+				return kc.resolveNamespace(ctx, ephemeralNamespaceVariable)
+			},
+		},
+	}
+}
+
+// getResource renders the manifest in the file, namespaced resources without a namespace get the default namespace
+func (kc *ClientSet) getResource(ctx context.Context, resourceFileName string) (unstruct.Resource, error) {
+	defaultNamespace, err := kc.resolveNamespace(ctx, "")
+	if err != nil {
+		return unstruct.Resource{}, err
+	}
+	resource, err := unstruct.GetResource(kc.getDiscoveryClient(), kc.getTemplateArguments(ctx), kc.getResourcePath(resourceFileName))
+	if err != nil {
+		return resource, err
+	}
+	unstruct.SetDefaultNamespace(resource, defaultNamespace)
+	return resource, nil
+}
+
+// getResources renders the manifests in the file, namespaced resources without a namespace get the default namespace
+func (kc *ClientSet) getResources(ctx context.Context, resourcesFileName string) ([]unstruct.Resource, error) {
+	defaultNamespace, err := kc.resolveNamespace(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	resources, err := unstruct.GetResources(kc.getDiscoveryClient(), kc.getTemplateArguments(ctx), kc.getResourcePath(resourcesFileName))
+	if err != nil {
+		return nil, err
+	}
+	for _, resource := range resources {
+		unstruct.SetDefaultNamespace(resource, defaultNamespace)
+	}
+	return resources, nil
+}
+
 func (kc *ClientSet) getWaiterConfig() common.WaiterConfig {
 	return common.NewWaiterConfig(kc.getWaiterTries(), kc.getWaiterInterval())
 }
@@ -82,9 +290,26 @@ func (kc *ClientSet) getExpBackoff() wait.Backoff {
 	return util.GetExpBackoff(kc.getWaiterTries())
 }
 
+// getDiscoveryClient returns a discovery client cached across steps, so REST mappings are not discovered on every call,
+// it is persisted to disk when a cache directory is set
 func (kc *ClientSet) getDiscoveryClient() discovery.DiscoveryInterface {
-	if kc.KubeInterface != nil {
-		return kc.KubeInterface.Discovery()
+	if kc.KubeInterface == nil {
+		return nil
+	}
+	if kc.discoveryClient == nil {
+		kc.discoveryClient = unstruct.NewCachedDiscoveryClient(kc.KubeInterface.Discovery())
+	}
+	return kc.discoveryClient
+}
+
+func getTableRows(table *godog.Table) [][]string {
+	var rows [][]string
+	for _, row := range table.Rows {
+		var cells []string
+		for _, cell := range row.Cells {
+			cells = append(cells, cell.Value)
+		}
+		rows = append(rows, cells)
 	}
-	return nil
+	return rows
 }
@@ -0,0 +1,157 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/keikoproj/kubedog/pkg/kube/common"
+)
+
+func TestDiscoverClientsKeepsDiscoveryCache(t *testing.T) {
+	newServer := func() string {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"major":"1","minor":"29","gitVersion":"v1.29.0"}`)
+		}))
+		t.Cleanup(server.Close)
+		return server.URL
+	}
+	writeKubeconfig := func(t *testing.T, host string) string {
+		kubeconfigPath := filepath.Join(t.TempDir(), "config")
+		kubeconfig := fmt.Sprintf(`apiVersion: v1
+kind: Config
+clusters:
+- name: test
+  cluster:
+    server: %s
+contexts:
+- name: test
+  context:
+    cluster: test
+    user: test
+current-context: test
+users:
+- name: test
+  user: {}
+`, host)
+		if err := os.WriteFile(kubeconfigPath, []byte(kubeconfig), 0600); err != nil {
+			t.Fatal(err)
+		}
+		return kubeconfigPath
+	}
+	firstHost, secondHost := newServer(), newServer()
+
+	tests := []struct {
+		name       string
+		host       string
+		wantCached bool
+	}{
+		{
+			name:       "Positive Test: discovering the same cluster again keeps the cache",
+			host:       firstHost,
+			wantCached: true,
+		},
+		{
+			name:       "Positive Test: discovering another cluster replaces the cache",
+			host:       secondHost,
+			wantCached: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var kc ClientSet
+			t.Setenv("KUBECONFIG", writeKubeconfig(t, firstHost))
+			if err := kc.DiscoverClients(); err != nil {
+				t.Fatal(err)
+			}
+			cached := kc.getDiscoveryClient()
+
+			t.Setenv("KUBECONFIG", writeKubeconfig(t, tt.host))
+			if err := kc.DiscoverClients(); err != nil {
+				t.Fatal(err)
+			}
+			if got := kc.getDiscoveryClient() == cached; got != tt.wantCached {
+				t.Errorf("discovery client kept = %v, want %v", got, tt.wantCached)
+			}
+		})
+	}
+}
+
+func TestGetTemplateArguments(t *testing.T) {
+	type templateArgs struct {
+		Name string
+	}
+	inScenario := func(kc *ClientSet, ephemeralNamespace string) context.Context {
+		ctx := kc.SetScenario(context.Background(), "scenario", "1")
+		kc.getScenario(ctx).ephemeralNamespace = ephemeralNamespace
+		return ctx
+	}
+	tests := []struct {
+		name         string
+		templateArgs interface{}
+		scenario     func(kc *ClientSet) context.Context
+		text         string
+		want         string
+		wantErr      bool
+	}{
+		{
+			name:     "Positive Test: manifests are not rendered without template arguments",
+			scenario: func(kc *ClientSet) context.Context { return inScenario(kc, "kubedog-abc123") },
+			text:     "summary: '{{ $labels.instance }} is down'",
+			want:     "summary: '{{ $labels.instance }} is down'",
+		},
+		{
+			name:         "Positive Test: ephemeral namespace of the scenario",
+			templateArgs: templateArgs{Name: "test"},
+			scenario:     func(kc *ClientSet) context.Context { return inScenario(kc, "kubedog-abc123") },
+			text:         "name: {{ .Name }}\nnamespace: {{ ephemeralNamespace }}",
+			want:         "name: test\nnamespace: kubedog-abc123",
+		},
+		{
+			name:         "Positive Test: ephemeral namespace outside of a scenario",
+			templateArgs: templateArgs{Name: "test"},
+			scenario:     func(kc *ClientSet) context.Context { return context.Background() },
+			text:         "namespace: {{ ephemeralNamespace }}",
+			want:         "namespace: ",
+		},
+		{
+			name:         "Negative Test: ephemeral namespace not created in the scenario",
+			templateArgs: templateArgs{Name: "test"},
+			scenario:     func(kc *ClientSet) context.Context { return inScenario(kc, "") },
+			text:         "namespace: {{ ephemeralNamespace }}",
+			wantErr:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var kc ClientSet
+			kc.SetTemplateArguments(tt.templateArgs)
+			got, err := common.RenderTemplate("test", tt.text, kc.getTemplateArguments(tt.scenario(&kc)))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("RenderTemplate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && string(got) != tt.want {
+				t.Errorf("RenderTemplate() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
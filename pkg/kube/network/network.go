@@ -0,0 +1,104 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package network
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/keikoproj/kubedog/pkg/kube/common"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+/*
+ConnectivityShouldBe runs a probe pod with the labels of a source pod that connects to the target on a port.
+Denied connectivity only means no connection was accepted, so the target service must expose the port and, for
+denied checks, the target pods must declare it as a container port so nothing listening is not taken for denied.
+*/
+func ConnectivityShouldBe(kubeClientset kubernetes.Interface, w common.WaiterConfig, image string, check ConnectivityCheck) (err error) {
+	check.TargetKind = normalizeTargetKind(check.TargetKind)
+	if err := check.validate(); err != nil {
+		return err
+	}
+
+	addresses, err := getTargetAddresses(kubeClientset, check)
+	if err != nil {
+		return err
+	}
+	sourcePod, err := getSourcePod(kubeClientset, check.SourceNamespace, check.SourceSelector)
+	if err != nil {
+		return err
+	}
+
+	probe, err := kubeClientset.CoreV1().Pods(check.SourceNamespace).Create(context.Background(), newProbePod(*sourcePod, image, addresses, check.Port), metav1.CreateOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "failed to create probe pod in namespace %s", check.SourceNamespace)
+	}
+	name, namespace := probe.Name, probe.Namespace
+	defer func() {
+		gracePeriod := int64(0)
+		deleteErr := kubeClientset.CoreV1().Pods(namespace).Delete(context.Background(), name, metav1.DeleteOptions{GracePeriodSeconds: &gracePeriod})
+		if deleteErr != nil && err == nil {
+			err = errors.Wrapf(deleteErr, "failed to delete probe pod %s/%s", namespace, name)
+		}
+	}()
+	log.Infof("created probe pod %s/%s with the labels of pod %s to reach %v on port %d", namespace, name, sourcePod.Name, addresses, check.Port)
+
+	var counter int
+	for {
+		if counter >= w.GetTries() {
+			return errors.Errorf("waiter timed out waiting for probe pod %s/%s to finish", namespace, name)
+		}
+
+		probe, err := kubeClientset.CoreV1().Pods(namespace).Get(context.Background(), name, metav1.GetOptions{})
+		if err != nil {
+			return errors.Wrapf(err, "failed to get probe pod %s/%s", namespace, name)
+		}
+
+		connectivity, finished, err := getProbeResult(*probe)
+		if err != nil {
+			return err
+		}
+		if finished {
+			if connectivity != check.Connectivity {
+				return errors.Errorf("expected %s, but connectivity was %s", check, connectivity)
+			}
+			log.Infof("%s: connectivity was %s", check, connectivity)
+			return nil
+		}
+
+		log.Infof("probe pod %s/%s is %s, waiting for it to finish", namespace, name, probe.Status.Phase)
+		counter++
+		time.Sleep(w.GetInterval())
+	}
+}
+
+// ConnectivityMatrixShouldBe runs every check, and fails with all the checks that did not match the expectation
+func ConnectivityMatrixShouldBe(kubeClientset kubernetes.Interface, w common.WaiterConfig, image string, checks []ConnectivityCheck) error {
+	var failures []string
+	for _, check := range checks {
+		if err := ConnectivityShouldBe(kubeClientset, w, image, check); err != nil {
+			failures = append(failures, err.Error())
+		}
+	}
+	if len(failures) > 0 {
+		return errors.Errorf("%d out of %d connectivity checks failed:\n%s", len(failures), len(checks), strings.Join(failures, "\n"))
+	}
+	return nil
+}
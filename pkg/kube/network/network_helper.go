@@ -0,0 +1,311 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package network
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/keikoproj/kubedog/internal/util"
+	"github.com/keikoproj/kubedog/pkg/kube/common"
+	"github.com/keikoproj/kubedog/pkg/kube/pod"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	connectivityAllowed = "allowed"
+	connectivityDenied  = "denied"
+
+	targetKindService          = "service"
+	targetKindPods             = "pods"
+	targetKindPodsWithSelector = "pods with selector"
+
+	probeNamePrefix            = "kubedog-probe-"
+	probeLabel                 = "kubedog.keikoproj.io/connectivity-probe"
+	probeReadinessGate         = "kubedog.keikoproj.io/connectivity-probe-ready"
+	probeContainerName         = "probe"
+	probeConnectTimeoutSeconds = 5
+
+	probeExitAllReachable  = 0
+	probeExitNoneReachable = 1
+	probeExitSomeReachable = 2
+
+	tableHeaderSourceNamespace = "source namespace"
+	tableHeaderSourceSelector  = "source selector"
+	tableHeaderTargetKind      = "target kind"
+	tableHeaderTarget          = "target"
+	tableHeaderTargetNamespace = "target namespace"
+	tableHeaderPort            = "port"
+	tableHeaderConnectivity    = "connectivity"
+)
+
+type ConnectivityCheck struct {
+	SourceNamespace string
+	SourceSelector  string
+	TargetKind      string
+	Target          string
+	TargetNamespace string
+	Port            int
+	Connectivity    string
+}
+
+func (c ConnectivityCheck) String() string {
+	return fmt.Sprintf("pods with selector '%s' in namespace %s to %s '%s' in namespace %s on port %d should be %s",
+		c.SourceSelector, c.SourceNamespace, c.TargetKind, c.Target, c.TargetNamespace, c.Port, c.Connectivity)
+}
+
+func (c ConnectivityCheck) validate() error {
+	switch c.TargetKind {
+	case targetKindService, targetKindPods:
+	default:
+		return errors.Errorf("unsupported connectivity target kind: '%s'", c.TargetKind)
+	}
+	switch c.Connectivity {
+	case connectivityAllowed, connectivityDenied:
+	default:
+		return errors.Errorf("unsupported connectivity: '%s'", c.Connectivity)
+	}
+	if c.Port <= 0 || c.Port > 65535 {
+		return errors.Errorf("invalid connectivity port: %d", c.Port)
+	}
+	return nil
+}
+
+// NewConnectivityChecks builds the checks from the rows of a data table, the first row being the header with the
+// columns 'source namespace', 'source selector', 'target kind' (service|pods), 'target' (service name or pods selector),
+// 'target namespace', 'port' and 'connectivity' (allowed|denied) in any order
+func NewConnectivityChecks(rows [][]string) ([]ConnectivityCheck, error) {
+	if len(rows) < 2 {
+		return nil, errors.New("connectivity table should have a header and at least one row")
+	}
+
+	columns := map[string]int{}
+	for i, header := range rows[0] {
+		columns[strings.ToLower(strings.TrimSpace(header))] = i
+	}
+	for _, header := range []string{tableHeaderSourceNamespace, tableHeaderSourceSelector, tableHeaderTargetKind, tableHeaderTarget, tableHeaderTargetNamespace, tableHeaderPort, tableHeaderConnectivity} {
+		if _, ok := columns[header]; !ok {
+			return nil, errors.Errorf("connectivity table is missing column '%s'", header)
+		}
+	}
+
+	var checks []ConnectivityCheck
+	for i, row := range rows[1:] {
+		if len(row) != len(rows[0]) {
+			return nil, errors.Errorf("connectivity table row %d has %d cells, expected %d", i+1, len(row), len(rows[0]))
+		}
+		cell := func(header string) string {
+			return strings.TrimSpace(row[columns[header]])
+		}
+
+		port, err := strconv.Atoi(cell(tableHeaderPort))
+		if err != nil {
+			return nil, errors.Wrapf(err, "connectivity table row %d has an invalid port", i+1)
+		}
+		check := ConnectivityCheck{
+			SourceNamespace: cell(tableHeaderSourceNamespace),
+			SourceSelector:  cell(tableHeaderSourceSelector),
+			TargetKind:      normalizeTargetKind(cell(tableHeaderTargetKind)),
+			Target:          cell(tableHeaderTarget),
+			TargetNamespace: cell(tableHeaderTargetNamespace),
+			Port:            port,
+			Connectivity:    cell(tableHeaderConnectivity),
+		}
+		if err := check.validate(); err != nil {
+			return nil, errors.Wrapf(err, "connectivity table row %d", i+1)
+		}
+		checks = append(checks, check)
+	}
+	return checks, nil
+}
+
+func normalizeTargetKind(targetKind string) string {
+	if targetKind == targetKindPodsWithSelector {
+		return targetKindPods
+	}
+	return targetKind
+}
+
+func getTargetAddresses(kubeClientset kubernetes.Interface, check ConnectivityCheck) ([]string, error) {
+	if err := common.ValidateClientset(kubeClientset); err != nil {
+		return nil, err
+	}
+
+	switch check.TargetKind {
+	case targetKindService:
+		service, err := util.RetryOnError(&util.DefaultRetry, util.IsRetriable, func() (interface{}, error) {
+			return kubeClientset.CoreV1().Services(check.TargetNamespace).Get(context.Background(), check.Target, metav1.GetOptions{})
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get service")
+		}
+		clusterIP := service.(*corev1.Service).Spec.ClusterIP
+		if clusterIP == "" || clusterIP == corev1.ClusterIPNone {
+			return nil, errors.Errorf("service %s/%s does not have a cluster ip", check.TargetNamespace, check.Target)
+		}
+		if !serviceHasPort(*service.(*corev1.Service), check.Port) {
+			return nil, errors.Errorf("service %s/%s does not expose port %d", check.TargetNamespace, check.Target, check.Port)
+		}
+		return []string{clusterIP}, nil
+	case targetKindPods:
+		pods, err := pod.GetPodListWithLabelSelector(kubeClientset, check.TargetNamespace, check.Target)
+		if err != nil {
+			return nil, err
+		}
+		var addresses []string
+		for _, p := range pods.Items {
+			if p.Status.Phase != corev1.PodRunning || p.Status.PodIP == "" || p.Labels[probeLabel] != "" {
+				continue
+			}
+			// a pod not listening on the port refuses the connection, which would pass for denied
+			if check.Connectivity == connectivityDenied && !podHasContainerPort(p, check.Port) {
+				return nil, errors.Errorf("pod %s/%s does not declare container port %d, denied connectivity cannot be told apart from nothing listening", p.Namespace, p.Name, check.Port)
+			}
+			addresses = append(addresses, p.Status.PodIP)
+		}
+		if len(addresses) == 0 {
+			return nil, errors.Errorf("no running pods found in namespace %s with selector '%s'", check.TargetNamespace, check.Target)
+		}
+		return addresses, nil
+	default:
+		return nil, errors.Errorf("unsupported connectivity target kind: '%s'", check.TargetKind)
+	}
+}
+
+func serviceHasPort(service corev1.Service, port int) bool {
+	for _, servicePort := range service.Spec.Ports {
+		if int(servicePort.Port) == port {
+			return true
+		}
+	}
+	return false
+}
+
+func podHasContainerPort(pod corev1.Pod, port int) bool {
+	for _, container := range pod.Spec.Containers {
+		for _, containerPort := range container.Ports {
+			if int(containerPort.ContainerPort) == port {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func getSourcePod(kubeClientset kubernetes.Interface, namespace, selector string) (*corev1.Pod, error) {
+	pods, err := pod.GetPodListWithLabelSelector(kubeClientset, namespace, selector)
+	if err != nil {
+		return nil, err
+	}
+	for i, p := range pods.Items {
+		if p.DeletionTimestamp == nil && p.Labels[probeLabel] == "" {
+			return &pods.Items[i], nil
+		}
+	}
+	return nil, errors.Errorf("no pods found in namespace %s with selector '%s'", namespace, selector)
+}
+
+// newProbePod builds a pod that carries the labels of the source pod, so the same network policies apply to it.
+// The source pod is set as its controller so workload controllers with a matching selector do not adopt it,
+// and so it is garbage collected if the cleanup does not happen. Its readiness gate is never set, so it never
+// becomes ready and is not added to the endpoints of the services selecting the source pod.
+func newProbePod(sourcePod corev1.Pod, image string, addresses []string, port int) *corev1.Pod {
+	labels := map[string]string{probeLabel: "true"}
+	for k, v := range sourcePod.Labels {
+		labels[k] = v
+	}
+
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      probeNamePrefix + rand.String(5),
+			Namespace: sourcePod.Namespace,
+			Labels:    labels,
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(&sourcePod, corev1.SchemeGroupVersion.WithKind("Pod")),
+			},
+		},
+		Spec: corev1.PodSpec{
+			RestartPolicy:      corev1.RestartPolicyNever,
+			ServiceAccountName: sourcePod.Spec.ServiceAccountName,
+			ReadinessGates: []corev1.PodReadinessGate{
+				{ConditionType: probeReadinessGate},
+			},
+			Containers: []corev1.Container{
+				{
+					Name:    probeContainerName,
+					Image:   image,
+					Command: []string{"sh", "-c", getProbeScript(addresses, port)},
+				},
+			},
+		},
+	}
+}
+
+// getProbeScript returns a script that exits with probeExitAllReachable, probeExitNoneReachable or
+// probeExitSomeReachable depending on how many of the addresses accepted a connection
+func getProbeScript(addresses []string, port int) string {
+	return fmt.Sprintf(`total=0; ok=0
+for address in %s; do
+  total=$((total+1))
+  if nc -z -w %d "$address" %d; then ok=$((ok+1)); echo "$address:%d reachable"; else echo "$address:%d unreachable"; fi
+done
+[ "$ok" -eq "$total" ] && exit %d
+[ "$ok" -eq 0 ] && exit %d
+exit %d`, strings.Join(addresses, " "), probeConnectTimeoutSeconds, port, port, port, probeExitAllReachable, probeExitNoneReachable, probeExitSomeReachable)
+}
+
+// getProbeResult returns the connectivity observed by a finished probe pod, and whether it has finished.
+// Denied means none of the addresses accepted a connection within the timeout, it does not tell a network
+// policy apart from a target that is down, which is why the target port is checked before probing.
+func getProbeResult(probe corev1.Pod) (string, bool, error) {
+	for _, status := range probe.Status.ContainerStatuses {
+		if status.Name != probeContainerName || status.State.Waiting == nil {
+			continue
+		}
+		switch status.State.Waiting.Reason {
+		case "ErrImagePull", "ImagePullBackOff", "InvalidImageName", "CreateContainerConfigError":
+			return "", true, errors.Errorf("probe pod %s/%s failed to start: %s %s", probe.Namespace, probe.Name, status.State.Waiting.Reason, status.State.Waiting.Message)
+		}
+	}
+
+	switch probe.Status.Phase {
+	case corev1.PodSucceeded:
+		return connectivityAllowed, true, nil
+	case corev1.PodFailed:
+	default:
+		return "", false, nil
+	}
+
+	for _, status := range probe.Status.ContainerStatuses {
+		if status.Name != probeContainerName || status.State.Terminated == nil {
+			continue
+		}
+		switch status.State.Terminated.ExitCode {
+		case probeExitNoneReachable:
+			return connectivityDenied, true, nil
+		case probeExitSomeReachable:
+			return "", true, errors.Errorf("probe pod %s/%s could only reach some of the target addresses", probe.Namespace, probe.Name)
+		default:
+			return "", true, errors.Errorf("probe pod %s/%s terminated with unexpected exit code %d: %s", probe.Namespace, probe.Name, status.State.Terminated.ExitCode, status.State.Terminated.Reason)
+		}
+	}
+	return "", true, errors.Errorf("probe pod %s/%s failed without a terminated %s container", probe.Namespace, probe.Name, probeContainerName)
+}
@@ -0,0 +1,264 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package network
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/keikoproj/kubedog/pkg/kube/common"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	kTesting "k8s.io/client-go/testing"
+)
+
+func TestNewConnectivityChecks(t *testing.T) {
+	header := []string{"source namespace", "source selector", "target kind", "target", "target namespace", "port", "connectivity"}
+	tests := []struct {
+		name       string
+		rows       [][]string
+		wantChecks int
+		wantErr    bool
+	}{
+		{
+			name: "Positive Test",
+			rows: [][]string{
+				header,
+				{"frontend", "app=web", "service", "api", "backend", "8080", "allowed"},
+				{"frontend", "app=web", "pods with selector", "app=db", "backend", "5432", "denied"},
+			},
+			wantChecks: 2,
+		},
+		{
+			name: "Positive Test: columns in any order",
+			rows: [][]string{
+				{"connectivity", "port", "target namespace", "target", "target kind", "source selector", "source namespace"},
+				{"denied", "5432", "backend", "app=db", "pods", "app=web", "frontend"},
+			},
+			wantChecks: 1,
+		},
+		{
+			name:    "Negative Test: missing column",
+			rows:    [][]string{header[1:], {"app=web", "service", "api", "backend", "8080", "allowed"}},
+			wantErr: true,
+		},
+		{
+			name:    "Negative Test: no rows",
+			rows:    [][]string{header},
+			wantErr: true,
+		},
+		{
+			name:    "Negative Test: invalid port",
+			rows:    [][]string{header, {"frontend", "app=web", "service", "api", "backend", "http", "allowed"}},
+			wantErr: true,
+		},
+		{
+			name:    "Negative Test: unsupported connectivity",
+			rows:    [][]string{header, {"frontend", "app=web", "service", "api", "backend", "8080", "blocked"}},
+			wantErr: true,
+		},
+		{
+			name:    "Negative Test: unsupported target kind",
+			rows:    [][]string{header, {"frontend", "app=web", "ingress", "api", "backend", "8080", "allowed"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checks, err := NewConnectivityChecks(tt.rows)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewConnectivityChecks() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(checks) != tt.wantChecks {
+				t.Errorf("NewConnectivityChecks() returned %d checks, want %d", len(checks), tt.wantChecks)
+			}
+		})
+	}
+}
+
+func TestGetProbeResult(t *testing.T) {
+	tests := []struct {
+		name             string
+		probe            corev1.Pod
+		wantConnectivity string
+		wantFinished     bool
+		wantErr          bool
+	}{
+		{
+			name:             "Positive Test: all reachable",
+			probe:            getProbeWithStatus(corev1.PodSucceeded, probeExitAllReachable),
+			wantConnectivity: connectivityAllowed,
+			wantFinished:     true,
+		},
+		{
+			name:             "Positive Test: none reachable",
+			probe:            getProbeWithStatus(corev1.PodFailed, probeExitNoneReachable),
+			wantConnectivity: connectivityDenied,
+			wantFinished:     true,
+		},
+		{
+			name:  "Positive Test: still running",
+			probe: corev1.Pod{Status: corev1.PodStatus{Phase: corev1.PodRunning}},
+		},
+		{
+			name:         "Negative Test: some reachable",
+			probe:        getProbeWithStatus(corev1.PodFailed, probeExitSomeReachable),
+			wantFinished: true,
+			wantErr:      true,
+		},
+		{
+			name:         "Negative Test: unexpected exit code",
+			probe:        getProbeWithStatus(corev1.PodFailed, 127),
+			wantFinished: true,
+			wantErr:      true,
+		},
+		{
+			name: "Negative Test: image pull error",
+			probe: corev1.Pod{Status: corev1.PodStatus{
+				Phase: corev1.PodPending,
+				ContainerStatuses: []corev1.ContainerStatus{
+					{Name: probeContainerName, State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ImagePullBackOff"}}},
+				},
+			}},
+			wantFinished: true,
+			wantErr:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			connectivity, finished, err := getProbeResult(tt.probe)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("getProbeResult() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if connectivity != tt.wantConnectivity || finished != tt.wantFinished {
+				t.Errorf("getProbeResult() = (%s, %t), want (%s, %t)", connectivity, finished, tt.wantConnectivity, tt.wantFinished)
+			}
+		})
+	}
+}
+
+func TestConnectivityShouldBe(t *testing.T) {
+	type args struct {
+		check    ConnectivityCheck
+		exitCode int32
+	}
+	sourcePod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "frontend", Labels: map[string]string{"app": "web"}},
+	}
+	targetPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "db-1", Namespace: "backend", Labels: map[string]string{"app": "db"}},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "db", Ports: []corev1.ContainerPort{{ContainerPort: 5432}}}},
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning, PodIP: "10.0.0.10"},
+	}
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "backend"},
+		Spec:       corev1.ServiceSpec{ClusterIP: "172.20.0.10", Ports: []corev1.ServicePort{{Port: 8080}}},
+	}
+	toService := ConnectivityCheck{SourceNamespace: "frontend", SourceSelector: "app=web", TargetKind: "service", Target: "api", TargetNamespace: "backend", Port: 8080}
+	toPods := ConnectivityCheck{SourceNamespace: "frontend", SourceSelector: "app=web", TargetKind: "pods with selector", Target: "app=db", TargetNamespace: "backend", Port: 5432}
+	withConnectivity := func(check ConnectivityCheck, connectivity string) ConnectivityCheck {
+		check.Connectivity = connectivity
+		return check
+	}
+	withPort := func(check ConnectivityCheck, port int) ConnectivityCheck {
+		check.Port = port
+		return check
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "Positive Test: allowed to service",
+			args: args{check: withConnectivity(toService, connectivityAllowed), exitCode: probeExitAllReachable},
+		},
+		{
+			name: "Positive Test: denied to pods",
+			args: args{check: withConnectivity(toPods, connectivityDenied), exitCode: probeExitNoneReachable},
+		},
+		{
+			name:    "Negative Test: expected denied but allowed",
+			args:    args{check: withConnectivity(toService, connectivityDenied), exitCode: probeExitAllReachable},
+			wantErr: true,
+		},
+		{
+			name: "Positive Test: allowed to pods on an undeclared port",
+			args: args{check: withPort(withConnectivity(toPods, connectivityAllowed), 8080), exitCode: probeExitAllReachable},
+		},
+		{
+			name:    "Negative Test: denied to pods on an undeclared port",
+			args:    args{check: withPort(withConnectivity(toPods, connectivityDenied), 8080), exitCode: probeExitNoneReachable},
+			wantErr: true,
+		},
+		{
+			name:    "Negative Test: service does not expose the port",
+			args:    args{check: withPort(withConnectivity(toService, connectivityDenied), 9090), exitCode: probeExitNoneReachable},
+			wantErr: true,
+		},
+		{
+			name: "Negative Test: no source pods",
+			args: args{check: ConnectivityCheck{SourceNamespace: "frontend", SourceSelector: "app=missing", TargetKind: "service", Target: "api",
+				TargetNamespace: "backend", Port: 8080, Connectivity: connectivityAllowed}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kubeClientset := fake.NewSimpleClientset(sourcePod, targetPod, service)
+			kubeClientset.PrependReactor("create", "pods", func(action kTesting.Action) (bool, runtime.Object, error) {
+				probe := action.(kTesting.CreateAction).GetObject().(*corev1.Pod)
+				if len(probe.Spec.ReadinessGates) == 0 {
+					t.Errorf("probe pod %s has no readiness gate and could become a service endpoint", probe.Name)
+				}
+				phase := corev1.PodSucceeded
+				if tt.args.exitCode != probeExitAllReachable {
+					phase = corev1.PodFailed
+				}
+				probe.Status = getProbeWithStatus(phase, tt.args.exitCode).Status
+				return false, nil, nil
+			})
+			if err := ConnectivityShouldBe(kubeClientset, common.NewWaiterConfig(1, time.Millisecond), "busybox", tt.args.check); (err != nil) != tt.wantErr {
+				t.Errorf("ConnectivityShouldBe() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			pods, err := kubeClientset.CoreV1().Pods("frontend").List(context.Background(), metav1.ListOptions{LabelSelector: probeLabel})
+			if err != nil {
+				t.Fatalf("failed to list probe pods: %v", err)
+			}
+			if len(pods.Items) != 0 {
+				t.Errorf("ConnectivityShouldBe() left %d probe pods behind", len(pods.Items))
+			}
+		})
+	}
+}
+
+func getProbeWithStatus(phase corev1.PodPhase, exitCode int32) corev1.Pod {
+	return corev1.Pod{
+		Status: corev1.PodStatus{
+			Phase: phase,
+			ContainerStatuses: []corev1.ContainerStatus{
+				{
+					Name:  probeContainerName,
+					State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: exitCode}},
+				},
+			},
+		},
+	}
+}
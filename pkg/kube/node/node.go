@@ -0,0 +1,315 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/keikoproj/kubedog/pkg/kube/common"
+	"github.com/keikoproj/kubedog/pkg/kube/pod"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/client-go/kubernetes"
+)
+
+func CordonNode(kubeClientset kubernetes.Interface, operation, name string) error {
+	var unschedulable bool
+	switch operation {
+	case common.OperationCordon:
+		unschedulable = true
+	case common.OperationUncordon:
+		unschedulable = false
+	default:
+		return fmt.Errorf("unsupported operation: '%s'", operation)
+	}
+
+	err := updateNode(kubeClientset, name, func(node *corev1.Node) error {
+		node.Spec.Unschedulable = unschedulable
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	log.Infof("node %s unschedulable set to %v", name, unschedulable)
+	return nil
+}
+
+func CordonNodesWithSelector(kubeClientset kubernetes.Interface, operation, selector string) error {
+	nodes, err := GetNodeListWithLabelSelector(kubeClientset, selector)
+	if err != nil {
+		return err
+	}
+
+	if len(nodes.Items) == 0 {
+		return errors.Errorf("no nodes matched selector '%s'", selector)
+	}
+
+	for _, node := range nodes.Items {
+		if err := CordonNode(kubeClientset, operation, node.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func DrainNode(kubeClientset kubernetes.Interface, w common.WaiterConfig, name string, timeout time.Duration) error {
+	return drainNode(kubeClientset, w, name, time.Now().Add(timeout))
+}
+
+func drainNode(kubeClientset kubernetes.Interface, w common.WaiterConfig, name string, deadline time.Time) error {
+	if err := CordonNode(kubeClientset, common.OperationCordon, name); err != nil {
+		return err
+	}
+
+	pods, err := getDrainablePods(kubeClientset, name)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range pods {
+		for {
+			err := pod.EvictPod(kubeClientset, p)
+			if err == nil || kerrors.IsNotFound(err) {
+				log.Infof("pod %s/%s has been evicted from node %s", p.Namespace, p.Name, name)
+				break
+			}
+			if !kerrors.IsTooManyRequests(err) {
+				return errors.Wrapf(err, "failed to evict pod %s/%s", p.Namespace, p.Name)
+			}
+			if time.Now().After(deadline) {
+				return errors.Errorf("timed out draining node %s: eviction of pod %s/%s is blocked by a disruption budget", name, p.Namespace, p.Name)
+			}
+			log.Infof("eviction of pod %s/%s is blocked by a disruption budget, retrying", p.Namespace, p.Name)
+			time.Sleep(w.GetInterval())
+		}
+	}
+
+	for {
+		remaining, err := getDrainablePods(kubeClientset, name)
+		if err != nil {
+			return err
+		}
+		if len(remaining) == 0 {
+			log.Infof("node %s has been drained", name)
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return errors.Errorf("timed out draining node %s: %d pods are still running", name, len(remaining))
+		}
+		log.Infof("waiting for %d pods to terminate on node %s", len(remaining), name)
+		time.Sleep(w.GetInterval())
+	}
+}
+
+func DrainNodesWithSelector(kubeClientset kubernetes.Interface, w common.WaiterConfig, selector string, timeout time.Duration) error {
+	nodes, err := GetNodeListWithLabelSelector(kubeClientset, selector)
+	if err != nil {
+		return err
+	}
+
+	if len(nodes.Items) == 0 {
+		return errors.Errorf("no nodes matched selector '%s'", selector)
+	}
+
+	// the timeout applies to draining all the nodes, not each of them
+	deadline := time.Now().Add(timeout)
+	for _, node := range nodes.Items {
+		if err := drainNode(kubeClientset, w, node.Name, deadline); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func NodeTaintOperation(kubeClientset kubernetes.Interface, operation, taint, name string) error {
+	expectedTaint, err := parseTaint(taint)
+	if err != nil {
+		return err
+	}
+
+	err = updateNode(kubeClientset, name, func(node *corev1.Node) error {
+		switch operation {
+		case common.OperationAdd:
+			if expectedTaint.Effect == "" {
+				return errors.Errorf("taint '%s' should meet format '<key>[=<value>]:<effect>'", taint)
+			}
+			taints := []corev1.Taint{}
+			for _, t := range node.Spec.Taints {
+				if t.Key == expectedTaint.Key && t.Effect == expectedTaint.Effect {
+					continue
+				}
+				taints = append(taints, t)
+			}
+			node.Spec.Taints = append(taints, expectedTaint)
+		case common.OperationRemove:
+			taints := []corev1.Taint{}
+			for _, t := range node.Spec.Taints {
+				if t.Key == expectedTaint.Key && (expectedTaint.Effect == "" || t.Effect == expectedTaint.Effect) {
+					continue
+				}
+				taints = append(taints, t)
+			}
+			node.Spec.Taints = taints
+		default:
+			return fmt.Errorf("unsupported operation: '%s'", operation)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	log.Infof("%s taint %s on node %s succeeded", operation, taint, name)
+	return nil
+}
+
+func NodeLabelOperation(kubeClientset kubernetes.Interface, operation, label, name string) error {
+	key, value, err := parseLabel(label, operation == common.OperationAdd)
+	if err != nil {
+		return err
+	}
+
+	err = updateNode(kubeClientset, name, func(node *corev1.Node) error {
+		switch operation {
+		case common.OperationAdd:
+			if node.Labels == nil {
+				node.Labels = map[string]string{}
+			}
+			node.Labels[key] = value
+		case common.OperationRemove:
+			delete(node.Labels, key)
+		default:
+			return fmt.Errorf("unsupported operation: '%s'", operation)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	log.Infof("%s label %s on node %s succeeded", operation, label, name)
+	return nil
+}
+
+func NodeConditionShouldBe(kubeClientset kubernetes.Interface, name, conditionType, conditionStatus string) error {
+	node, err := GetNode(kubeClientset, name)
+	if err != nil {
+		return err
+	}
+	return validateNodeCondition(*node, conditionType, conditionStatus)
+}
+
+func NodesWithSelectorConditionShouldBe(kubeClientset kubernetes.Interface, selector, conditionType, conditionStatus string) error {
+	nodes, err := GetNodeListWithLabelSelector(kubeClientset, selector)
+	if err != nil {
+		return err
+	}
+
+	if len(nodes.Items) == 0 {
+		return errors.Errorf("no nodes matched selector '%s'", selector)
+	}
+
+	for _, node := range nodes.Items {
+		if err := validateNodeCondition(node, conditionType, conditionStatus); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func NodesWithSelectorShouldHaveKubeletVersion(kubeClientset kubernetes.Interface, selector, version string) error {
+	nodes, err := GetNodeListWithLabelSelector(kubeClientset, selector)
+	if err != nil {
+		return err
+	}
+
+	if len(nodes.Items) == 0 {
+		return errors.Errorf("no nodes matched selector '%s'", selector)
+	}
+
+	for _, node := range nodes.Items {
+		kubeletVersion := node.Status.NodeInfo.KubeletVersion
+		if !isVersionMatch(kubeletVersion, version) {
+			return errors.Errorf("node %s has kubelet version '%s', expected '%s'", node.Name, kubeletVersion, version)
+		}
+		log.Infof("node %s has kubelet version '%s'", node.Name, kubeletVersion)
+	}
+	return nil
+}
+
+func NodesWithSelectorShouldHaveResource(kubeClientset kubernetes.Interface, selector, comparison, quantity, resourceType, resourceName string) error {
+	expectedQuantity, err := resource.ParseQuantity(quantity)
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse quantity '%s'", quantity)
+	}
+
+	nodes, err := GetNodeListWithLabelSelector(kubeClientset, selector)
+	if err != nil {
+		return err
+	}
+
+	if len(nodes.Items) == 0 {
+		return errors.Errorf("no nodes matched selector '%s'", selector)
+	}
+
+	for _, node := range nodes.Items {
+		resources, err := getNodeResources(node, resourceType)
+		if err != nil {
+			return err
+		}
+		actualQuantity, ok := resources[corev1.ResourceName(resourceName)]
+		if !ok {
+			return errors.Errorf("node %s does not report %s %s", node.Name, resourceType, resourceName)
+		}
+		// Cmp returns -1, 0 or 1, so comparing it against 0 applies the comparison to the quantities
+		matched, err := common.CompareCount(comparison, actualQuantity.Cmp(expectedQuantity), 0)
+		if err != nil {
+			return err
+		}
+		if !matched {
+			return errors.Errorf("node %s has %s %s '%s', expected %s '%s'", node.Name, resourceType, resourceName, actualQuantity.String(), comparison, quantity)
+		}
+		log.Infof("node %s has %s %s '%s'", node.Name, resourceType, resourceName, actualQuantity.String())
+	}
+	return nil
+}
+
+func NodesWithSelectorShouldBeSpreadAcrossZones(kubeClientset kubernetes.Interface, selector, comparison string, expectedZones int) error {
+	nodes, err := GetNodeListWithLabelSelector(kubeClientset, selector)
+	if err != nil {
+		return err
+	}
+
+	zones := map[string]int{}
+	for _, node := range nodes.Items {
+		zone := GetZone(node)
+		if zone == "" {
+			return errors.Errorf("node %s does not have label '%s'", node.Name, corev1.LabelTopologyZone)
+		}
+		zones[zone]++
+	}
+
+	matched, err := common.CompareCount(comparison, len(zones), expectedZones)
+	if err != nil {
+		return err
+	}
+	if !matched {
+		return errors.Errorf("nodes with selector '%s' are spread across %d zones %v, expected %s %d", selector, len(zones), zones, comparison, expectedZones)
+	}
+	log.Infof("nodes with selector '%s' are spread across %d zones %v", selector, len(zones), zones)
+	return nil
+}
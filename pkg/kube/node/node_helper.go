@@ -0,0 +1,194 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"context"
+	"strings"
+
+	"github.com/keikoproj/kubedog/internal/util"
+	"github.com/keikoproj/kubedog/pkg/kube/common"
+	"github.com/keikoproj/kubedog/pkg/kube/pod"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	mirrorPodAnnotation = "kubernetes.io/config.mirror"
+
+	resourceTypeAllocatable = "allocatable"
+	resourceTypeCapacity    = "capacity"
+)
+
+func GetNode(kubeClientset kubernetes.Interface, name string) (*corev1.Node, error) {
+	if err := common.ValidateClientset(kubeClientset); err != nil {
+		return nil, err
+	}
+
+	node, err := util.RetryOnError(&util.DefaultRetry, util.IsRetriable, func() (interface{}, error) {
+		return kubeClientset.CoreV1().Nodes().Get(context.Background(), name, metav1.GetOptions{})
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get node '%s'", name)
+	}
+	return node.(*corev1.Node), nil
+}
+
+func GetNodeListWithLabelSelector(kubeClientset kubernetes.Interface, labelSelector string) (*corev1.NodeList, error) {
+	if err := common.ValidateClientset(kubeClientset); err != nil {
+		return nil, err
+	}
+
+	nodes, err := util.RetryOnError(&util.DefaultRetry, util.IsRetriable, func() (interface{}, error) {
+		return kubeClientset.CoreV1().Nodes().List(context.Background(), metav1.ListOptions{LabelSelector: labelSelector})
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list nodes")
+	}
+	return nodes.(*corev1.NodeList), nil
+}
+
+// GetZone returns the node zone, falling back to the deprecated beta label for older nodes
+func GetZone(node corev1.Node) string {
+	if zone, ok := node.Labels[corev1.LabelTopologyZone]; ok {
+		return zone
+	}
+	return node.Labels[corev1.LabelFailureDomainBetaZone]
+}
+
+func updateNode(kubeClientset kubernetes.Interface, name string, mutateFn func(node *corev1.Node) error) error {
+	if err := common.ValidateClientset(kubeClientset); err != nil {
+		return err
+	}
+
+	_, err := util.RetryOnError(&util.DefaultRetry, util.IsRetriable, func() (interface{}, error) {
+		node, err := kubeClientset.CoreV1().Nodes().Get(context.Background(), name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		if err := mutateFn(node); err != nil {
+			return nil, err
+		}
+		return kubeClientset.CoreV1().Nodes().Update(context.Background(), node, metav1.UpdateOptions{})
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to update node '%s'", name)
+	}
+	return nil
+}
+
+func getDrainablePods(kubeClientset kubernetes.Interface, nodeName string) ([]corev1.Pod, error) {
+	pods, err := pod.GetPodListWithLabelSelectorAndFieldSelector(kubeClientset, metav1.NamespaceAll, "", "spec.nodeName="+nodeName)
+	if err != nil {
+		return nil, err
+	}
+
+	drainable := []corev1.Pod{}
+	for _, p := range pods.Items {
+		if p.Spec.NodeName != nodeName {
+			continue
+		}
+		if _, ok := p.Annotations[mirrorPodAnnotation]; ok {
+			log.Infof("skipping mirror pod %s/%s", p.Namespace, p.Name)
+			continue
+		}
+		if isOwnedByDaemonSet(p) {
+			log.Infof("skipping daemonset pod %s/%s", p.Namespace, p.Name)
+			continue
+		}
+		drainable = append(drainable, p)
+	}
+	return drainable, nil
+}
+
+func isOwnedByDaemonSet(p corev1.Pod) bool {
+	for _, owner := range p.OwnerReferences {
+		if owner.Kind == "DaemonSet" {
+			return true
+		}
+	}
+	return false
+}
+
+// parseTaint accepts taints as '<key>=<value>:<effect>', '<key>:<effect>' or '<key>'
+func parseTaint(taint string) (corev1.Taint, error) {
+	var (
+		result   corev1.Taint
+		keyValue = taint
+	)
+
+	if i := strings.LastIndex(taint, ":"); i >= 0 {
+		keyValue = taint[:i]
+		result.Effect = corev1.TaintEffect(taint[i+1:])
+		switch result.Effect {
+		case corev1.TaintEffectNoSchedule, corev1.TaintEffectPreferNoSchedule, corev1.TaintEffectNoExecute:
+		default:
+			return corev1.Taint{}, errors.Errorf("unsupported taint effect '%s' in taint '%s'", result.Effect, taint)
+		}
+	}
+
+	key, value, _ := strings.Cut(keyValue, "=")
+	if key == "" {
+		return corev1.Taint{}, errors.Errorf("taint '%s' should meet format '<key>[=<value>][:<effect>]'", taint)
+	}
+	result.Key = key
+	result.Value = value
+	return result, nil
+}
+
+func parseLabel(label string, valueRequired bool) (string, string, error) {
+	key, value, found := strings.Cut(label, "=")
+	if key == "" || (valueRequired && !found) {
+		return "", "", errors.Errorf("label '%s' should meet format '<key>=<value>'", label)
+	}
+	return key, value, nil
+}
+
+func validateNodeCondition(node corev1.Node, conditionType, conditionStatus string) error {
+	for _, condition := range node.Status.Conditions {
+		if string(condition.Type) != conditionType {
+			continue
+		}
+		if !strings.EqualFold(string(condition.Status), conditionStatus) {
+			return errors.Errorf("node %s condition %s is '%s', expected '%s'", node.Name, conditionType, condition.Status, conditionStatus)
+		}
+		log.Infof("node %s condition %s is '%s'", node.Name, conditionType, condition.Status)
+		return nil
+	}
+	return errors.Errorf("node %s does not have condition %s", node.Name, conditionType)
+}
+
+func getNodeResources(node corev1.Node, resourceType string) (corev1.ResourceList, error) {
+	switch resourceType {
+	case resourceTypeAllocatable:
+		return node.Status.Allocatable, nil
+	case resourceTypeCapacity:
+		return node.Status.Capacity, nil
+	default:
+		return nil, errors.Errorf("unsupported node resource type: '%s'", resourceType)
+	}
+}
+
+// isVersionMatch matches full versions such as 'v1.28.5' as well as prefixes such as 'v1.28'
+func isVersionMatch(version, expected string) bool {
+	if !strings.HasPrefix(version, expected) {
+		return false
+	}
+	suffix := strings.TrimPrefix(version, expected)
+	return suffix == "" || strings.ContainsAny(suffix[:1], ".-+")
+}
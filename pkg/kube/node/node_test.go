@@ -0,0 +1,652 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"testing"
+	"time"
+
+	"github.com/keikoproj/kubedog/pkg/kube/common"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	kTesting "k8s.io/client-go/testing"
+)
+
+const (
+	nodeName  = "node1"
+	nodeLabel = "some-label-key=some-label-value"
+)
+
+func TestCordonNode(t *testing.T) {
+	type args struct {
+		kubeClientset kubernetes.Interface
+		operation     string
+		name          string
+	}
+	tests := []struct {
+		name              string
+		args              args
+		wantUnschedulable bool
+		wantErr           bool
+	}{
+		{
+			name: "Positive Test: cordon",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(newNode(nodeName, nil)),
+				operation:     common.OperationCordon,
+				name:          nodeName,
+			},
+			wantUnschedulable: true,
+		},
+		{
+			name: "Positive Test: uncordon",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(newNode(nodeName, nil)),
+				operation:     common.OperationUncordon,
+				name:          nodeName,
+			},
+		},
+		{
+			name: "Negative Test: node not found",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(),
+				operation:     common.OperationCordon,
+				name:          nodeName,
+			},
+			wantErr: true,
+		},
+		{
+			name: "Negative Test: invalid operation",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(newNode(nodeName, nil)),
+				operation:     "invalid-operation",
+				name:          nodeName,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CordonNode(tt.args.kubeClientset, tt.args.operation, tt.args.name)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CordonNode() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil {
+				node := getNode(t, tt.args.kubeClientset, tt.args.name)
+				if node.Spec.Unschedulable != tt.wantUnschedulable {
+					t.Errorf("CordonNode() unschedulable = %v, want %v", node.Spec.Unschedulable, tt.wantUnschedulable)
+				}
+			}
+		})
+	}
+}
+
+func TestCordonNodesWithSelector(t *testing.T) {
+	type args struct {
+		kubeClientset kubernetes.Interface
+		operation     string
+		selector      string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "Positive Test",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(newNode(nodeName, map[string]string{"some-label-key": "some-label-value"})),
+				operation:     common.OperationCordon,
+				selector:      nodeLabel,
+			},
+		},
+		{
+			name: "Negative Test: no nodes matched selector",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(newNode(nodeName, nil)),
+				operation:     common.OperationCordon,
+				selector:      nodeLabel,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := CordonNodesWithSelector(tt.args.kubeClientset, tt.args.operation, tt.args.selector); (err != nil) != tt.wantErr {
+				t.Errorf("CordonNodesWithSelector() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDrainNode(t *testing.T) {
+	type args struct {
+		kubeClientset kubernetes.Interface
+		name          string
+		timeout       time.Duration
+	}
+	podOnNode := newPod("pod1", nodeName, nil)
+	daemonSetPod := newPod("pod2", nodeName, []metav1.OwnerReference{{Kind: "DaemonSet", Name: "ds1"}})
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "Positive Test: pods evicted",
+			args: args{
+				kubeClientset: newFakeClientsetWithEvictionReaction(t, nil, newNode(nodeName, nil), podOnNode, daemonSetPod),
+				name:          nodeName,
+				timeout:       time.Second,
+			},
+		},
+		{
+			name: "Negative Test: eviction blocked by disruption budget",
+			args: args{
+				kubeClientset: newFakeClientsetWithEvictionReaction(t,
+					kerrors.NewTooManyRequests("Cannot evict pod as it would violate the pod's disruption budget.", 0),
+					newNode(nodeName, nil), podOnNode),
+				name:    nodeName,
+				timeout: time.Second,
+			},
+			wantErr: true,
+		},
+		{
+			name: "Negative Test: node not found",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(),
+				name:          nodeName,
+				timeout:       time.Second,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := common.NewWaiterConfig(1, time.Second)
+			if err := DrainNode(tt.args.kubeClientset, w, tt.args.name, tt.args.timeout); (err != nil) != tt.wantErr {
+				t.Errorf("DrainNode() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNodeTaintOperation(t *testing.T) {
+	type args struct {
+		kubeClientset kubernetes.Interface
+		operation     string
+		taint         string
+		name          string
+	}
+	taintedNode := newNode(nodeName, nil)
+	taintedNode.Spec.Taints = []corev1.Taint{{Key: "some-taint", Value: "true", Effect: corev1.TaintEffectNoSchedule}}
+	tests := []struct {
+		name       string
+		args       args
+		wantTaints int
+		wantErr    bool
+	}{
+		{
+			name: "Positive Test: add taint",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(newNode(nodeName, nil)),
+				operation:     common.OperationAdd,
+				taint:         "some-taint=true:NoSchedule",
+				name:          nodeName,
+			},
+			wantTaints: 1,
+		},
+		{
+			name: "Positive Test: remove taint without effect",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(taintedNode.DeepCopy()),
+				operation:     common.OperationRemove,
+				taint:         "some-taint",
+				name:          nodeName,
+			},
+			wantTaints: 0,
+		},
+		{
+			name: "Negative Test: add taint without effect",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(newNode(nodeName, nil)),
+				operation:     common.OperationAdd,
+				taint:         "some-taint=true",
+				name:          nodeName,
+			},
+			wantErr: true,
+		},
+		{
+			name: "Negative Test: invalid effect",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(newNode(nodeName, nil)),
+				operation:     common.OperationAdd,
+				taint:         "some-taint=true:Invalid",
+				name:          nodeName,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NodeTaintOperation(tt.args.kubeClientset, tt.args.operation, tt.args.taint, tt.args.name)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NodeTaintOperation() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil {
+				node := getNode(t, tt.args.kubeClientset, tt.args.name)
+				if len(node.Spec.Taints) != tt.wantTaints {
+					t.Errorf("NodeTaintOperation() taints = %v, want %d taints", node.Spec.Taints, tt.wantTaints)
+				}
+			}
+		})
+	}
+}
+
+func TestNodeLabelOperation(t *testing.T) {
+	type args struct {
+		kubeClientset kubernetes.Interface
+		operation     string
+		label         string
+		name          string
+	}
+	tests := []struct {
+		name      string
+		args      args
+		wantLabel bool
+		wantErr   bool
+	}{
+		{
+			name: "Positive Test: add label",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(newNode(nodeName, nil)),
+				operation:     common.OperationAdd,
+				label:         nodeLabel,
+				name:          nodeName,
+			},
+			wantLabel: true,
+		},
+		{
+			name: "Positive Test: remove label",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(newNode(nodeName, map[string]string{"some-label-key": "some-label-value"})),
+				operation:     common.OperationRemove,
+				label:         "some-label-key",
+				name:          nodeName,
+			},
+		},
+		{
+			name: "Negative Test: add label without value",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(newNode(nodeName, nil)),
+				operation:     common.OperationAdd,
+				label:         "some-label-key",
+				name:          nodeName,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NodeLabelOperation(tt.args.kubeClientset, tt.args.operation, tt.args.label, tt.args.name)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NodeLabelOperation() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil {
+				node := getNode(t, tt.args.kubeClientset, tt.args.name)
+				if _, ok := node.Labels["some-label-key"]; ok != tt.wantLabel {
+					t.Errorf("NodeLabelOperation() labels = %v, want label %v", node.Labels, tt.wantLabel)
+				}
+			}
+		})
+	}
+}
+
+func TestNodesWithSelectorConditionShouldBe(t *testing.T) {
+	type args struct {
+		kubeClientset   kubernetes.Interface
+		selector        string
+		conditionType   string
+		conditionStatus string
+	}
+	node := newNode(nodeName, map[string]string{"some-label-key": "some-label-value"})
+	node.Status.Conditions = []corev1.NodeCondition{
+		{Type: corev1.NodeMemoryPressure, Status: corev1.ConditionFalse},
+		{Type: "KernelDeadlock", Status: corev1.ConditionFalse},
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "Positive Test: MemoryPressure is False",
+			args: args{
+				kubeClientset:   fake.NewSimpleClientset(node),
+				selector:        nodeLabel,
+				conditionType:   string(corev1.NodeMemoryPressure),
+				conditionStatus: "False",
+			},
+		},
+		{
+			name: "Positive Test: custom condition is False",
+			args: args{
+				kubeClientset:   fake.NewSimpleClientset(node),
+				selector:        nodeLabel,
+				conditionType:   "KernelDeadlock",
+				conditionStatus: "False",
+			},
+		},
+		{
+			name: "Negative Test: MemoryPressure is not True",
+			args: args{
+				kubeClientset:   fake.NewSimpleClientset(node),
+				selector:        nodeLabel,
+				conditionType:   string(corev1.NodeMemoryPressure),
+				conditionStatus: "True",
+			},
+			wantErr: true,
+		},
+		{
+			name: "Negative Test: condition not found",
+			args: args{
+				kubeClientset:   fake.NewSimpleClientset(node),
+				selector:        nodeLabel,
+				conditionType:   string(corev1.NodeDiskPressure),
+				conditionStatus: "False",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := NodesWithSelectorConditionShouldBe(tt.args.kubeClientset, tt.args.selector, tt.args.conditionType, tt.args.conditionStatus); (err != nil) != tt.wantErr {
+				t.Errorf("NodesWithSelectorConditionShouldBe() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNodesWithSelectorShouldHaveKubeletVersion(t *testing.T) {
+	type args struct {
+		kubeClientset kubernetes.Interface
+		selector      string
+		version       string
+	}
+	node := newNode(nodeName, map[string]string{"some-label-key": "some-label-value"})
+	node.Status.NodeInfo.KubeletVersion = "v1.28.5-eks-5e0fdde"
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "Positive Test: full version",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(node),
+				selector:      nodeLabel,
+				version:       "v1.28.5",
+			},
+		},
+		{
+			name: "Positive Test: minor version",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(node),
+				selector:      nodeLabel,
+				version:       "v1.28",
+			},
+		},
+		{
+			name: "Negative Test: version prefix is not a version",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(node),
+				selector:      nodeLabel,
+				version:       "v1.2",
+			},
+			wantErr: true,
+		},
+		{
+			name: "Negative Test: no nodes matched selector",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(),
+				selector:      nodeLabel,
+				version:       "v1.28",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := NodesWithSelectorShouldHaveKubeletVersion(tt.args.kubeClientset, tt.args.selector, tt.args.version); (err != nil) != tt.wantErr {
+				t.Errorf("NodesWithSelectorShouldHaveKubeletVersion() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNodesWithSelectorShouldHaveResource(t *testing.T) {
+	type args struct {
+		kubeClientset kubernetes.Interface
+		selector      string
+		comparison    string
+		quantity      string
+		resourceType  string
+		resourceName  string
+	}
+	node := newNode(nodeName, map[string]string{"some-label-key": "some-label-value"})
+	node.Status.Allocatable = corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("3920m"),
+		corev1.ResourceMemory: resource.MustParse("15Gi"),
+	}
+	node.Status.Capacity = corev1.ResourceList{
+		corev1.ResourcePods: resource.MustParse("110"),
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "Positive Test: at least allocatable cpu",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(node),
+				selector:      nodeLabel,
+				comparison:    common.ComparisonAtLeast,
+				quantity:      "2",
+				resourceType:  resourceTypeAllocatable,
+				resourceName:  "cpu",
+			},
+		},
+		{
+			name: "Positive Test: at most allocatable memory",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(node),
+				selector:      nodeLabel,
+				comparison:    common.ComparisonAtMost,
+				quantity:      "16Gi",
+				resourceType:  resourceTypeAllocatable,
+				resourceName:  "memory",
+			},
+		},
+		{
+			name: "Positive Test: exactly capacity pods",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(node),
+				selector:      nodeLabel,
+				comparison:    common.ComparisonExactly,
+				quantity:      "110",
+				resourceType:  resourceTypeCapacity,
+				resourceName:  "pods",
+			},
+		},
+		{
+			name: "Negative Test: not enough allocatable cpu",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(node),
+				selector:      nodeLabel,
+				comparison:    common.ComparisonAtLeast,
+				quantity:      "4",
+				resourceType:  resourceTypeAllocatable,
+				resourceName:  "cpu",
+			},
+			wantErr: true,
+		},
+		{
+			name: "Negative Test: resource not reported",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(node),
+				selector:      nodeLabel,
+				comparison:    common.ComparisonAtLeast,
+				quantity:      "1",
+				resourceType:  resourceTypeAllocatable,
+				resourceName:  "pods",
+			},
+			wantErr: true,
+		},
+		{
+			name: "Negative Test: invalid quantity",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(node),
+				selector:      nodeLabel,
+				comparison:    common.ComparisonAtLeast,
+				quantity:      "two",
+				resourceType:  resourceTypeAllocatable,
+				resourceName:  "cpu",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := NodesWithSelectorShouldHaveResource(tt.args.kubeClientset, tt.args.selector, tt.args.comparison, tt.args.quantity, tt.args.resourceType, tt.args.resourceName); (err != nil) != tt.wantErr {
+				t.Errorf("NodesWithSelectorShouldHaveResource() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNodesWithSelectorShouldBeSpreadAcrossZones(t *testing.T) {
+	type args struct {
+		kubeClientset kubernetes.Interface
+		selector      string
+		comparison    string
+		expectedZones int
+	}
+	labels := map[string]string{"some-label-key": "some-label-value"}
+	nodeZoneA := newNode("node-a", map[string]string{"some-label-key": "some-label-value", corev1.LabelTopologyZone: "us-west-2a"})
+	nodeZoneB := newNode("node-b", map[string]string{"some-label-key": "some-label-value", corev1.LabelFailureDomainBetaZone: "us-west-2b"})
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "Positive Test: exactly 2 zones",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(nodeZoneA, nodeZoneB),
+				selector:      nodeLabel,
+				comparison:    common.ComparisonExactly,
+				expectedZones: 2,
+			},
+		},
+		{
+			name: "Negative Test: at least 3 zones",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(nodeZoneA, nodeZoneB),
+				selector:      nodeLabel,
+				comparison:    common.ComparisonAtLeast,
+				expectedZones: 3,
+			},
+			wantErr: true,
+		},
+		{
+			name: "Negative Test: node without zone",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(nodeZoneA, newNode(nodeName, labels)),
+				selector:      nodeLabel,
+				comparison:    common.ComparisonAtLeast,
+				expectedZones: 1,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := NodesWithSelectorShouldBeSpreadAcrossZones(tt.args.kubeClientset, tt.args.selector, tt.args.comparison, tt.args.expectedZones); (err != nil) != tt.wantErr {
+				t.Errorf("NodesWithSelectorShouldBeSpreadAcrossZones() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func newNode(name string, labels map[string]string) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: labels,
+		},
+	}
+}
+
+func newPod(name, nodeName string, ownerReferences []metav1.OwnerReference) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       "namespace1",
+			OwnerReferences: ownerReferences,
+		},
+		Spec: corev1.PodSpec{
+			NodeName: nodeName,
+		},
+	}
+}
+
+func getNode(t *testing.T, kubeClientset kubernetes.Interface, name string) *corev1.Node {
+	node, err := GetNode(kubeClientset, name)
+	if err != nil {
+		t.Error(err)
+	}
+	return node
+}
+
+// newFakeClientsetWithEvictionReaction deletes evicted pods, or fails evictions with evictionErr if set
+func newFakeClientsetWithEvictionReaction(t *testing.T, evictionErr error, objects ...runtime.Object) *fake.Clientset {
+	client := fake.NewSimpleClientset(objects...)
+	client.PrependReactor("create", "pods", func(action kTesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "eviction" {
+			return false, nil, nil
+		}
+		if evictionErr != nil {
+			return true, nil, evictionErr
+		}
+		eviction, ok := action.(kTesting.CreateAction).GetObject().(*policyv1.Eviction)
+		if !ok {
+			t.Errorf("unexpected eviction object: %v", action)
+		}
+		gvr := schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+		return true, nil, client.Tracker().Delete(gvr, eviction.Namespace, eviction.Name)
+	})
+	return client
+}
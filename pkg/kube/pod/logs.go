@@ -0,0 +1,485 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pod
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	matchTypeSubstring = "substring"
+	matchTypeRegex     = "regex"
+	matchTypeJSONQuery = "json query"
+
+	queryOperatorEqual     = "="
+	queryOperatorNotEqual  = "!="
+	queryOperatorMatch     = "=~"
+	queryOperatorNotMatch  = "!~"
+	queryOperatorIn        = "in"
+	queryOperatorNotIn     = "notin"
+	queryClauseSeparator   = " and "
+	queryValuesSeparator   = ","
+	queryFieldPathSplitter = "."
+
+	logInstanceCurrent  = "current"
+	logInstancePrevious = "previous"
+)
+
+var (
+	queryComparisonRegExp = regexp.MustCompile(`^([\w.\-]+)\s*(=~|!~|!=|==|=)\s*(.+)$`)
+	querySetRegExp        = regexp.MustCompile(`^([\w.\-]+)\s+(in|notin)\s+\((.*)\)$`)
+)
+
+type logMatcher struct {
+	description string
+	match       func(line string) bool
+}
+
+/*
+newLogMatcher returns a matcher for log lines of the given type:
+  - substring: the line contains the expression
+  - regex: the line matches the regular expression
+  - json query: the line is a JSON object satisfying every clause of the query,
+    clauses are joined by 'and' and have the form 'field op value' with op one of
+    '=', '!=', '=~', '!~' or 'field in (a,b)', 'field notin (a,b)'. Nested fields
+    use dots, e.g. 'level in (error,fatal) and msg !~ "retrying"'
+*/
+func newLogMatcher(matchType, expression string) (logMatcher, error) {
+	switch matchType {
+	case matchTypeSubstring:
+		return logMatcher{
+			description: fmt.Sprintf("substring '%s'", expression),
+			match: func(line string) bool {
+				return strings.Contains(line, expression)
+			},
+		}, nil
+	case matchTypeRegex:
+		re, err := regexp.Compile(expression)
+		if err != nil {
+			return logMatcher{}, errors.Wrapf(err, "failed to compile regex '%s'", expression)
+		}
+		return logMatcher{
+			description: fmt.Sprintf("regex '%s'", expression),
+			match:       re.MatchString,
+		}, nil
+	case matchTypeJSONQuery:
+		clauses, err := parseJSONQuery(expression)
+		if err != nil {
+			return logMatcher{}, err
+		}
+		return logMatcher{
+			description: fmt.Sprintf("json query '%s'", expression),
+			match: func(line string) bool {
+				return matchJSONQuery(line, clauses)
+			},
+		}, nil
+	default:
+		return logMatcher{}, errors.Errorf("unsupported log match type: '%s'", matchType)
+	}
+}
+
+// newErrorLogMatcher returns a matcher for lines matching any of the error patterns, which are regular expressions
+func newErrorLogMatcher(errorPatterns []string) (logMatcher, error) {
+	if len(errorPatterns) == 0 {
+		return logMatcher{}, errors.New("no log error patterns configured")
+	}
+	alternatives := make([]string, len(errorPatterns))
+	for i, pattern := range errorPatterns {
+		alternatives[i] = fmt.Sprintf("(?:%s)", pattern)
+	}
+	return newLogMatcher(matchTypeRegex, strings.Join(alternatives, "|"))
+}
+
+// logTarget selects which containers of a pod, and which instance of them, logs are read from
+type logTarget struct {
+	container string
+	previous  bool
+}
+
+func newLogTarget(instance, container string) (logTarget, error) {
+	switch instance {
+	case logInstanceCurrent:
+		return logTarget{container: container}, nil
+	case logInstancePrevious:
+		return logTarget{container: container, previous: true}, nil
+	default:
+		return logTarget{}, errors.Errorf("unsupported log instance: '%s'", instance)
+	}
+}
+
+/*
+containers returns the names of the init, regular and ephemeral containers of the pod
+the target reads logs from. When reading previous logs of all containers, only restarted
+containers are returned since the others have no previous instance.
+*/
+func (lt logTarget) containers(pod corev1.Pod) ([]string, error) {
+	var names []string
+	for _, container := range pod.Spec.InitContainers {
+		names = append(names, container.Name)
+	}
+	for _, container := range pod.Spec.Containers {
+		names = append(names, container.Name)
+	}
+	for _, container := range pod.Spec.EphemeralContainers {
+		names = append(names, container.Name)
+	}
+
+	if lt.container != "" {
+		if !slices.Contains(names, lt.container) {
+			return nil, errors.Errorf("pod '%s' has no container '%s'", pod.Name, lt.container)
+		}
+		if lt.previous && getRestartCount(pod, lt.container) == 0 {
+			return nil, errors.Errorf("container '%s' of pod '%s' has no previous instance", lt.container, pod.Name)
+		}
+		return []string{lt.container}, nil
+	}
+
+	if !lt.previous {
+		return names, nil
+	}
+	var restarted []string
+	for _, name := range names {
+		if getRestartCount(pod, name) > 0 {
+			restarted = append(restarted, name)
+		}
+	}
+	return restarted, nil
+}
+
+func (lt logTarget) describe(container string) string {
+	if container == "" {
+		container = lt.container
+	}
+	description := "all containers"
+	if container != "" {
+		description = fmt.Sprintf("container '%s'", container)
+	}
+	if lt.previous {
+		description = "previous instance of " + description
+	}
+	return description
+}
+
+func getRestartCount(pod corev1.Pod, container string) int32 {
+	statuses := append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...)
+	statuses = append(statuses, pod.Status.ContainerStatuses...)
+	statuses = append(statuses, pod.Status.EphemeralContainerStatuses...)
+	for _, status := range statuses {
+		if status.Name == container {
+			return status.RestartCount
+		}
+	}
+	return 0
+}
+
+type queryClause struct {
+	path     []string
+	operator string
+	values   []string
+	regExp   *regexp.Regexp
+}
+
+func parseJSONQuery(query string) ([]queryClause, error) {
+	var clauses []queryClause
+	for _, rawClause := range strings.Split(query, queryClauseSeparator) {
+		clause, err := parseQueryClause(strings.TrimSpace(rawClause))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse json query '%s'", query)
+		}
+		clauses = append(clauses, clause)
+	}
+	return clauses, nil
+}
+
+func parseQueryClause(rawClause string) (queryClause, error) {
+	if match := querySetRegExp.FindStringSubmatch(rawClause); match != nil {
+		clause := queryClause{
+			path:     strings.Split(match[1], queryFieldPathSplitter),
+			operator: match[2],
+		}
+		for _, value := range strings.Split(match[3], queryValuesSeparator) {
+			clause.values = append(clause.values, unquoteQueryValue(value))
+		}
+		return clause, nil
+	}
+
+	match := queryComparisonRegExp.FindStringSubmatch(rawClause)
+	if match == nil {
+		return queryClause{}, errors.Errorf("invalid clause '%s'", rawClause)
+	}
+	clause := queryClause{
+		path:     strings.Split(match[1], queryFieldPathSplitter),
+		operator: match[2],
+		values:   []string{unquoteQueryValue(match[3])},
+	}
+	if clause.operator == "==" {
+		clause.operator = queryOperatorEqual
+	}
+	if clause.operator == queryOperatorMatch || clause.operator == queryOperatorNotMatch {
+		re, err := regexp.Compile(clause.values[0])
+		if err != nil {
+			return queryClause{}, errors.Wrapf(err, "invalid regex in clause '%s'", rawClause)
+		}
+		clause.regExp = re
+	}
+	return clause, nil
+}
+
+func unquoteQueryValue(value string) string {
+	value = strings.TrimSpace(value)
+	if unquoted, err := strconv.Unquote(value); err == nil {
+		return unquoted
+	}
+	return value
+}
+
+func matchJSONQuery(line string, clauses []queryClause) bool {
+	var entry map[string]any
+	if err := json.Unmarshal([]byte(line), &entry); err != nil {
+		return false
+	}
+	for _, clause := range clauses {
+		if !clause.matches(entry) {
+			return false
+		}
+	}
+	return true
+}
+
+// matches treats a missing field as not equal to, and not matching, any value
+func (qc queryClause) matches(entry map[string]any) bool {
+	field, found, err := unstructured.NestedFieldNoCopy(entry, qc.path...)
+	found = found && err == nil && field != nil
+	value := fmt.Sprint(field)
+
+	switch qc.operator {
+	case queryOperatorEqual:
+		return found && value == qc.values[0]
+	case queryOperatorNotEqual:
+		return !found || value != qc.values[0]
+	case queryOperatorMatch:
+		return found && qc.regExp.MatchString(value)
+	case queryOperatorNotMatch:
+		return !found || !qc.regExp.MatchString(value)
+	case queryOperatorIn:
+		return found && slices.Contains(qc.values, value)
+	case queryOperatorNotIn:
+		return !found || !slices.Contains(qc.values, value)
+	default:
+		return false
+	}
+}
+
+/*
+logFollower tails the logs of all containers of the pods matching a selector, following pods
+as they are created, and hands each stream to handle until stop is called or the context ends
+*/
+type logFollower struct {
+	kubeClientset kubernetes.Interface
+	since         metav1.Time
+	retryInterval time.Duration
+	handle        func(pod corev1.Pod, container string, podLogs io.Reader)
+	started       chan error
+	done          chan struct{}
+	doneOnce      sync.Once
+	followed      map[containerInstance]bool
+	wg            sync.WaitGroup
+}
+
+// containerInstance identifies a run of a container, a restarted container is followed again
+type containerInstance struct {
+	uid          types.UID
+	container    string
+	restartCount int32
+}
+
+func newLogFollower(kubeClientset kubernetes.Interface, since time.Time, retryInterval time.Duration, handle func(pod corev1.Pod, container string, podLogs io.Reader)) *logFollower {
+	return &logFollower{
+		kubeClientset: kubeClientset,
+		since:         metav1.NewTime(since),
+		retryInterval: retryInterval,
+		handle:        handle,
+		started:       make(chan error, 1),
+		done:          make(chan struct{}),
+		followed:      map[containerInstance]bool{},
+	}
+}
+
+func (lf *logFollower) stop() {
+	lf.doneOnce.Do(func() {
+		close(lf.done)
+	})
+}
+
+// run blocks until stop is called, returning nil, or until the context ends, returning its error, whether the pods
+// could be watched at all is sent to started
+func (lf *logFollower) run(ctx context.Context, namespace, selector string) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer func() {
+		cancel()
+		lf.wg.Wait()
+	}()
+
+	watcher, err := lf.watchPods(ctx, namespace, selector)
+	lf.started <- err
+	for {
+		if err != nil {
+			return err
+		}
+
+		if err := lf.consume(ctx, watcher); err != errWatchClosed {
+			return err
+		}
+		log.Infof("watch of pods in namespace %s with selector '%s' closed, restarting", namespace, selector)
+		watcher, err = lf.watchPods(ctx, namespace, selector)
+	}
+}
+
+var errWatchClosed = errors.New("watch closed")
+
+// watchPods follows the pods that currently exist and returns a watch for the ones to come
+func (lf *logFollower) watchPods(ctx context.Context, namespace, selector string) (watch.Interface, error) {
+	pods, err := lf.kubeClientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list pods in namespace %s with selector '%s'", namespace, selector)
+	}
+	for _, pod := range pods.Items {
+		lf.follow(ctx, pod)
+	}
+
+	watcher, err := lf.kubeClientset.CoreV1().Pods(namespace).Watch(ctx, metav1.ListOptions{
+		LabelSelector:   selector,
+		ResourceVersion: pods.ResourceVersion,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to watch pods in namespace %s with selector '%s'", namespace, selector)
+	}
+	return watcher, nil
+}
+
+func (lf *logFollower) consume(ctx context.Context, watcher watch.Interface) error {
+	defer watcher.Stop()
+	for {
+		select {
+		case <-lf.done:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		case event, ok := <-watcher.ResultChan():
+			if !ok {
+				return errWatchClosed
+			}
+			pod, isPod := event.Object.(*corev1.Pod)
+			if isPod && (event.Type == watch.Added || event.Type == watch.Modified) {
+				lf.follow(ctx, *pod)
+			}
+		}
+	}
+}
+
+func (lf *logFollower) follow(ctx context.Context, pod corev1.Pod) {
+	containers, _ := logTarget{}.containers(pod)
+	for _, container := range containers {
+		instance := containerInstance{
+			uid:          pod.UID,
+			container:    container,
+			restartCount: getRestartCount(pod, container),
+		}
+		if lf.followed[instance] {
+			continue
+		}
+		lf.followed[instance] = true
+
+		lf.wg.Add(1)
+		go func(container string) {
+			defer lf.wg.Done()
+			lf.followContainer(ctx, pod, container)
+		}(container)
+	}
+}
+
+// followContainer retries opening the stream until the container has started or the pod is gone
+func (lf *logFollower) followContainer(ctx context.Context, pod corev1.Pod, container string) {
+	for {
+		podLogOpts := corev1.PodLogOptions{
+			Container: container,
+			Follow:    true,
+			SinceTime: &lf.since,
+		}
+		podLogs, err := lf.kubeClientset.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &podLogOpts).Stream(ctx)
+		if err == nil {
+			lf.handle(pod, container, podLogs)
+			podLogs.Close()
+			return
+		}
+		if kerrors.IsNotFound(err) {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(lf.retryInterval):
+		}
+	}
+}
+
+// LogCapture streams the logs of the pods matching a selector into per-container files until stopped
+type LogCapture struct {
+	follower *logFollower
+	result   chan error
+}
+
+// Stop ends the capture and waits for the log files to be closed
+func (lc *LogCapture) Stop() error {
+	lc.follower.stop()
+	return <-lc.result
+}
+
+func writeLogsToFile(directory string, pod corev1.Pod, container string, podLogs io.Reader) {
+	filePath := filepath.Join(directory, fmt.Sprintf("%s_%s_%s.log", pod.Namespace, pod.Name, container))
+	f, err := os.OpenFile(filePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		log.Errorf("failed to open log capture file '%s': %v", filePath, err)
+		return
+	}
+	defer f.Close()
+
+	log.Infof("capturing logs of container '%s' of pod '%s' to '%s'", container, pod.Name, filePath)
+	if _, err := io.Copy(f, podLogs); err != nil && !errors.Is(err, context.Canceled) {
+		log.Errorf("failed to capture logs of container '%s' of pod '%s': %v", container, pod.Name, err)
+	}
+}
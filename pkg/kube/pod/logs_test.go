@@ -0,0 +1,292 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pod
+
+import (
+	"context"
+	"io"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes/fake"
+	kTesting "k8s.io/client-go/testing"
+)
+
+func TestNewLogMatcher(t *testing.T) {
+	type args struct {
+		matchType  string
+		expression string
+		line       string
+	}
+	tests := []struct {
+		name      string
+		args      args
+		wantMatch bool
+		wantErr   bool
+	}{
+		{
+			name: "Positive Test: substring",
+			args: args{
+				matchType:  matchTypeSubstring,
+				expression: "level=error",
+				line:       `time="2024-01-01T00:00:00Z" level=error msg="failed"`,
+			},
+			wantMatch: true,
+		},
+		{
+			name: "Positive Test: regex",
+			args: args{
+				matchType:  matchTypeRegex,
+				expression: `took \d+ms`,
+				line:       "request took 250ms",
+			},
+			wantMatch: true,
+		},
+		{
+			name: "Positive Test: json query with in and not match",
+			args: args{
+				matchType:  matchTypeJSONQuery,
+				expression: `level in (error,fatal) and msg !~ "retrying"`,
+				line:       `{"level":"fatal","msg":"connection refused"}`,
+			},
+			wantMatch: true,
+		},
+		{
+			name: "Positive Test: json query with nested field and missing field",
+			args: args{
+				matchType:  matchTypeJSONQuery,
+				expression: `http.status = 500 and user != admin`,
+				line:       `{"http":{"status":500}}`,
+			},
+			wantMatch: true,
+		},
+		{
+			name: "Negative Test: json query excluded by not match",
+			args: args{
+				matchType:  matchTypeJSONQuery,
+				expression: `level in (error,fatal) and msg !~ "retrying"`,
+				line:       `{"level":"error","msg":"retrying request"}`,
+			},
+		},
+		{
+			name: "Negative Test: json query on non json line",
+			args: args{
+				matchType:  matchTypeJSONQuery,
+				expression: `level = error`,
+				line:       `level=error`,
+			},
+		},
+		{
+			name: "Negative Test: invalid regex",
+			args: args{
+				matchType:  matchTypeRegex,
+				expression: `(unclosed`,
+			},
+			wantErr: true,
+		},
+		{
+			name: "Negative Test: invalid json query clause",
+			args: args{
+				matchType:  matchTypeJSONQuery,
+				expression: `level and msg`,
+			},
+			wantErr: true,
+		},
+		{
+			name: "Negative Test: unsupported match type",
+			args: args{
+				matchType:  "glob",
+				expression: `*`,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matcher, err := newLogMatcher(tt.args.matchType, tt.args.expression)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("newLogMatcher() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			if got := matcher.match(tt.args.line); got != tt.wantMatch {
+				t.Errorf("newLogMatcher().match() = %v, want %v", got, tt.wantMatch)
+			}
+		})
+	}
+}
+
+func TestLogTargetContainers(t *testing.T) {
+	pod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "pod-1"},
+		Spec: corev1.PodSpec{
+			InitContainers: []corev1.Container{{Name: "init"}},
+			Containers:     []corev1.Container{{Name: "main"}, {Name: "sidecar"}},
+			EphemeralContainers: []corev1.EphemeralContainer{
+				{EphemeralContainerCommon: corev1.EphemeralContainerCommon{Name: "debugger"}},
+			},
+		},
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{
+				{Name: "main", RestartCount: 3},
+				{Name: "sidecar"},
+			},
+		},
+	}
+	tests := []struct {
+		name    string
+		target  logTarget
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:   "Positive Test: all containers",
+			target: logTarget{},
+			want:   []string{"init", "main", "sidecar", "debugger"},
+		},
+		{
+			name:   "Positive Test: init container",
+			target: logTarget{container: "init"},
+			want:   []string{"init"},
+		},
+		{
+			name:   "Positive Test: previous instance of all containers",
+			target: logTarget{previous: true},
+			want:   []string{"main"},
+		},
+		{
+			name:   "Positive Test: previous instance of restarted container",
+			target: logTarget{container: "main", previous: true},
+			want:   []string{"main"},
+		},
+		{
+			name:    "Negative Test: previous instance of container never restarted",
+			target:  logTarget{container: "sidecar", previous: true},
+			wantErr: true,
+		},
+		{
+			name:    "Negative Test: unknown container",
+			target:  logTarget{container: "unknown"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.target.containers(pod)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("logTarget.containers() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("logTarget.containers() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLogFollowerFollowsRestartedContainers(t *testing.T) {
+	namespaceName := "test-ns"
+	tests := []struct {
+		name          string
+		restartCounts []int32
+		want          []int32
+	}{
+		{
+			name:          "Positive Test: restarted container is followed again",
+			restartCounts: []int32{1, 2},
+			want:          []int32{0, 1, 2},
+		},
+		{
+			name:          "Positive Test: status update without restart is not followed again",
+			restartCounts: []int32{0},
+			want:          []int32{0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "pod-1",
+					Namespace: namespaceName,
+					UID:       "pod-1-uid",
+					Labels:    map[string]string{"app": "test-service"},
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "main"}},
+				},
+				Status: corev1.PodStatus{
+					ContainerStatuses: []corev1.ContainerStatus{{Name: "main"}},
+				},
+			}
+			kubeClientset := fake.NewSimpleClientset(pod)
+			watching := make(chan struct{})
+			var watchingOnce sync.Once
+			kubeClientset.PrependWatchReactor("pods", func(action kTesting.Action) (bool, watch.Interface, error) {
+				watchingOnce.Do(func() { close(watching) })
+				return false, nil, nil
+			})
+
+			followed := make(chan int32, 10)
+			follower := newLogFollower(kubeClientset, time.Now(), 10*time.Millisecond, func(pod corev1.Pod, container string, podLogs io.Reader) {
+				followed <- getRestartCount(pod, container)
+			})
+			result := make(chan error, 1)
+			go func() {
+				result <- follower.run(context.Background(), namespaceName, "app=test-service")
+			}()
+
+			var got []int32
+			waitFollowed := func() {
+				select {
+				case restartCount := <-followed:
+					got = append(got, restartCount)
+				case <-time.After(time.Second):
+				}
+			}
+			waitFollowed()
+			<-watching
+			for _, restartCount := range tt.restartCounts {
+				pod.Status.ContainerStatuses[0].RestartCount = restartCount
+				if _, err := kubeClientset.CoreV1().Pods(namespaceName).UpdateStatus(context.Background(), pod, metav1.UpdateOptions{}); err != nil {
+					t.Fatal(err)
+				}
+				if len(got) < len(tt.want) {
+					waitFollowed()
+				}
+			}
+			// give an unexpected follow the chance to show up
+			select {
+			case restartCount := <-followed:
+				got = append(got, restartCount)
+			case <-time.After(50 * time.Millisecond):
+			}
+
+			follower.stop()
+			if err := <-result; err != nil {
+				t.Errorf("logFollower.run() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("followed restart counts = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -15,10 +15,14 @@ limitations under the License.
 package pod
 
 import (
+	"bufio"
 	"context"
 	"fmt"
+	"io"
+	"math/rand"
+	"os"
 	"reflect"
-	"strings"
+	"slices"
 	"time"
 
 	"github.com/keikoproj/kubedog/internal/util"
@@ -26,7 +30,10 @@ import (
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
 )
@@ -189,7 +196,12 @@ func SomePodsInNamespaceWithSelectorDontHaveStringInLogsSinceTime(kubeClientset
 	return fmt.Errorf("pod has '%s' message in the logs", searchkeyword)
 }
 
-func PodsInNamespaceWithSelectorHaveNoErrorsInLogsSinceTime(kubeClientset kubernetes.Interface, namespace string, selector string, since time.Time) error {
+func PodsInNamespaceWithSelectorHaveNoErrorsInLogsSinceTime(kubeClientset kubernetes.Interface, namespace string, selector string, since time.Time, errorPatterns []string) error {
+	matcher, err := newErrorLogMatcher(errorPatterns)
+	if err != nil {
+		return err
+	}
+
 	pods, err := GetPodListWithLabelSelector(kubeClientset, namespace, selector)
 	if err != nil {
 		return err
@@ -199,8 +211,7 @@ func PodsInNamespaceWithSelectorHaveNoErrorsInLogsSinceTime(kubeClientset kubern
 	}
 
 	for _, pod := range pods.Items {
-		errorStrings := []string{`"level":"error"`, "level=error"}
-		count, err := countStringInPodLogs(kubeClientset, pod, since, errorStrings...)
+		count, err := countMatchesInPodLogs(kubeClientset, pod, since, logTarget{}, matcher)
 		if err != nil {
 			return err
 		}
@@ -212,14 +223,26 @@ func PodsInNamespaceWithSelectorHaveNoErrorsInLogsSinceTime(kubeClientset kubern
 	return nil
 }
 
-func PodsInNamespaceWithSelectorHaveSomeErrorsInLogsSinceTime(kubeClientset kubernetes.Interface, namespace string, selector string, since time.Time) error {
-	err := PodsInNamespaceWithSelectorHaveNoErrorsInLogsSinceTime(kubeClientset, namespace, selector, since)
+func PodsInNamespaceWithSelectorHaveSomeErrorsInLogsSinceTime(kubeClientset kubernetes.Interface, namespace string, selector string, since time.Time, errorPatterns []string) error {
+	err := PodsInNamespaceWithSelectorHaveNoErrorsInLogsSinceTime(kubeClientset, namespace, selector, since, errorPatterns)
 	if err == nil {
 		return fmt.Errorf("logs found from selector %q in namespace %q have errors", selector, namespace)
 	}
 	return nil
 }
 
+func PodsInNamespaceWithSelectorShouldHaveLogLinesMatching(kubeClientset kubernetes.Interface, comparison string, expectedCount int, namespace, selector, matchType, expression string, since time.Time) error {
+	return podsLogLinesMatchingShouldBe(kubeClientset, logTarget{}, comparison, expectedCount, namespace, selector, matchType, expression, since)
+}
+
+func ContainerInPodsInNamespaceWithSelectorShouldHaveLogLinesMatching(kubeClientset kubernetes.Interface, instance, container, comparison string, expectedCount int, namespace, selector, matchType, expression string, since time.Time) error {
+	target, err := newLogTarget(instance, container)
+	if err != nil {
+		return err
+	}
+	return podsLogLinesMatchingShouldBe(kubeClientset, target, comparison, expectedCount, namespace, selector, matchType, expression, since)
+}
+
 func PodInNamespaceShouldHaveLabels(kubeClientset kubernetes.Interface, name, namespace, labels string) error {
 	if err := common.ValidateClientset(kubeClientset); err != nil {
 		return err
@@ -230,25 +253,8 @@ func PodInNamespaceShouldHaveLabels(kubeClientset kubernetes.Interface, name, na
 		return errors.New("Error fetching pod: " + err.Error())
 	}
 
-	inputLabels := make(map[string]string)
-	slc := strings.Split(labels, ",")
-	for _, item := range slc {
-		vals := strings.Split(item, "=")
-		if len(vals) != 2 {
-			continue
-		}
-
-		inputLabels[vals[0]] = vals[1]
-	}
-
-	for k, v := range inputLabels {
-		pV, ok := pod.Labels[k]
-		if !ok {
-			return errors.New(fmt.Sprintf("Label %s missing in pod/namespace %s", k, name+"/"+namespace))
-		}
-		if v != pV {
-			return errors.New(fmt.Sprintf("Label value %s doesn't match expected %s for key %s in pod/namespace %s", pV, v, k, name+"/"+namespace))
-		}
+	if err := common.ValidateSelectorMatch(pod.Labels, labels); err != nil {
+		return errors.Wrapf(err, "labels of pod/namespace %s do not match", name+"/"+namespace)
 	}
 
 	return nil
@@ -265,27 +271,323 @@ func PodsInNamespaceWithSelectorShouldHaveLabels(kubeClientset kubernetes.Interf
 	}
 
 	for _, pod := range podList.Items {
-		inputLabels := make(map[string]string)
-		slc := strings.Split(labels, ",")
-		for _, item := range slc {
-			vals := strings.Split(item, "=")
-			if len(vals) != 2 {
-				continue
+		if err := common.ValidateSelectorMatch(pod.Labels, labels); err != nil {
+			return errors.Wrapf(err, "labels of pod/namespace %s do not match", pod.Name+"/"+namespace)
+		}
+	}
+
+	return nil
+}
+
+func PodsWithSelectorCountShouldBe(kubeClientset kubernetes.Interface, w common.WaiterConfig, comparison string, expectedCount int, namespace, selector, state string) error {
+	description := fmt.Sprintf("%s pods with selector '%s'", state, selector)
+	return common.WaitForCount(w, comparison, expectedCount, description, func() (int, error) {
+		pods, err := GetPodListWithLabelSelector(kubeClientset, namespace, selector)
+		if err != nil {
+			return 0, err
+		}
+
+		var podsCount int
+		for _, pod := range pods.Items {
+			inState, err := isPodInState(pod, state)
+			if err != nil {
+				return 0, err
+			}
+			if inState {
+				podsCount++
 			}
+		}
+		return podsCount, nil
+	})
+}
 
-			inputLabels[vals[0]] = vals[1]
+func EvictPodInNamespace(kubeClientset kubernetes.Interface, name, namespace, expectedResult string) error {
+	if err := common.ValidateClientset(kubeClientset); err != nil {
+		return err
+	}
+
+	pod, err := kubeClientset.CoreV1().Pods(namespace).Get(context.Background(), name, metav1.GetOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "failed to get pod %s/%s", namespace, name)
+	}
+
+	rejected, err := evictPodWithResult(kubeClientset, *pod)
+	if err != nil {
+		return err
+	}
+	return validateEvictionResult(expectedResult, rejected, fmt.Sprintf("pod %s/%s", namespace, name))
+}
+
+func EvictPodsInNamespaceWithSelector(kubeClientset kubernetes.Interface, count int, namespace, selector, expectedResult string) error {
+	if count < 1 {
+		return errors.Errorf("pod count to evict must be at least 1, got %d", count)
+	}
+
+	pods, err := GetPodListWithLabelSelector(kubeClientset, namespace, selector)
+	if err != nil {
+		return err
+	}
+
+	if len(pods.Items) < count {
+		return errors.Errorf("expected at least %d pods matching selector '%s', found %d", count, selector, len(pods.Items))
+	}
+
+	var rejected bool
+	for _, pod := range pods.Items[:count] {
+		rejected, err = evictPodWithResult(kubeClientset, pod)
+		if err != nil {
+			return err
+		}
+		// stop at the first rejection, the disruption budget is protecting the remaining pods
+		if rejected {
+			break
 		}
+	}
+	return validateEvictionResult(expectedResult, rejected, fmt.Sprintf("%d pods in namespace %s with selector '%s'", count, namespace, selector))
+}
+
+func KillRandomPodsInNamespaceWithSelector(kubeClientset kubernetes.Interface, mode string, amount int, unit, namespace, selector string) ([]corev1.Pod, error) {
+	var gracePeriodSeconds *int64
+	switch mode {
+	case killGracefully:
+	case killForcefully:
+		gracePeriodSeconds = new(int64)
+	default:
+		return nil, errors.Errorf("unsupported kill mode: '%s'", mode)
+	}
 
-		for k, v := range inputLabels {
-			pV, ok := pod.Labels[k]
-			if !ok {
-				return fmt.Errorf("label %s missing in pod/namespace %s", k, pod.Name+"/"+namespace)
+	pods, err := GetPodListWithLabelSelector(kubeClientset, namespace, selector)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(pods.Items) == 0 {
+		return nil, errors.Errorf("No pods matched selector '%s'", selector)
+	}
+
+	count, err := getKillCount(len(pods.Items), amount, unit)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := pods.Items
+	rand.Shuffle(len(candidates), func(i, j int) {
+		candidates[i], candidates[j] = candidates[j], candidates[i]
+	})
+
+	killed := []corev1.Pod{}
+	for _, pod := range candidates[:count] {
+		err := kubeClientset.CoreV1().Pods(pod.Namespace).Delete(context.Background(), pod.Name, metav1.DeleteOptions{GracePeriodSeconds: gracePeriodSeconds})
+		if err != nil && !kerrors.IsNotFound(err) {
+			return killed, errors.Wrapf(err, "failed to kill pod %s/%s", pod.Namespace, pod.Name)
+		}
+		log.Infof("chaos: killed pod %s/%s on node %s", pod.Namespace, pod.Name, pod.Spec.NodeName)
+		killed = append(killed, pod)
+	}
+	log.Infof("chaos: killed %d/%d pods in namespace %s with selector '%s'", len(killed), len(pods.Items), namespace, selector)
+	return killed, nil
+}
+
+func PodsInNamespaceWithSelectorShouldRecover(kubeClientset kubernetes.Interface, w common.WaiterConfig, namespace, selector string, killedPods []corev1.Pod, timeout time.Duration) error {
+	killedPods, err := filterPodsWithSelector(killedPods, namespace, selector)
+	if err != nil {
+		return err
+	}
+
+	if len(killedPods) == 0 {
+		return errors.Errorf("no pods were killed in namespace %s with selector '%s' during this scenario", namespace, selector)
+	}
+
+	desiredCount, err := getDesiredPodCount(kubeClientset, killedPods[0])
+	if err != nil {
+		return err
+	}
+
+	killedUIDs := map[types.UID]string{}
+	for _, pod := range killedPods {
+		killedUIDs[pod.UID] = pod.Name
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		pods, err := GetPodListWithLabelSelector(kubeClientset, namespace, selector)
+		if err != nil {
+			return err
+		}
+
+		var (
+			readyCount     int
+			remainingNames []string
+		)
+		for _, pod := range pods.Items {
+			if name, ok := killedUIDs[pod.UID]; ok {
+				remainingNames = append(remainingNames, name)
+				continue
 			}
-			if v != pV {
-				return fmt.Errorf("label value %s doesn't match expected %s for key %s in pod/namespace %s", pV, v, k, pod.Name+"/"+namespace)
+			if ready, _ := isPodInState(pod, common.StateReady); ready {
+				readyCount++
 			}
 		}
+
+		if len(remainingNames) == 0 && readyCount >= desiredCount {
+			log.Infof("pods in namespace %s with selector '%s' recovered to %d/%d ready pods", namespace, selector, readyCount, desiredCount)
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return errors.Errorf("pods in namespace %s with selector '%s' did not recover within %v: %d/%d ready pods, killed pods still present: %v", namespace, selector, timeout, readyCount, desiredCount, remainingNames)
+		}
+		log.Infof("waiting for pods in namespace %s with selector '%s' to recover: %d/%d ready pods", namespace, selector, readyCount, desiredCount)
+		time.Sleep(w.GetInterval())
+	}
+}
+
+func PodsInNamespaceWithSelectorShouldLogLineMatching(kubeClientset kubernetes.Interface, w common.WaiterConfig, namespace, selector, matchType, expression string, since time.Time, timeout time.Duration) error {
+	if err := common.ValidateClientset(kubeClientset); err != nil {
+		return err
+	}
+
+	matcher, err := newLogMatcher(matchType, expression)
+	if err != nil {
+		return err
 	}
 
+	var follower *logFollower
+	follower = newLogFollower(kubeClientset, since, w.GetInterval(), func(pod corev1.Pod, container string, podLogs io.Reader) {
+		scanner := bufio.NewScanner(podLogs)
+		for scanner.Scan() {
+			if line := scanner.Text(); matcher.match(line) {
+				log.Infof("Found %s in line '%s' in container '%s' of pod '%s'", matcher.description, line, container, pod.Name)
+				follower.stop()
+				return
+			}
+		}
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := follower.run(ctx, namespace, selector); err != nil {
+		return errors.Wrapf(err, "pods in namespace %s with selector '%s' did not log a line matching %s within %v", namespace, selector, matcher.description, timeout)
+	}
 	return nil
 }
+
+func StartCapturingLogs(kubeClientset kubernetes.Interface, w common.WaiterConfig, namespace, selector, directory string) (*LogCapture, error) {
+	if err := common.ValidateClientset(kubeClientset); err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(directory, 0755); err != nil {
+		return nil, errors.Wrapf(err, "failed to create log capture directory '%s'", directory)
+	}
+
+	capture := &LogCapture{
+		follower: newLogFollower(kubeClientset, time.Now(), w.GetInterval(), func(pod corev1.Pod, container string, podLogs io.Reader) {
+			writeLogsToFile(directory, pod, container, podLogs)
+		}),
+		result: make(chan error, 1),
+	}
+	go func() {
+		capture.result <- capture.follower.run(context.Background(), namespace, selector)
+	}()
+	if err := <-capture.follower.started; err != nil {
+		return nil, err
+	}
+	log.Infof("started capturing logs of pods in namespace %s with selector '%s' to '%s'", namespace, selector, directory)
+	return capture, nil
+}
+
+func PodsInNamespaceWithSelectorShouldBeInPhase(kubeClientset kubernetes.Interface, w common.WaiterConfig, namespace, selector, phase string) error {
+	return podsWithSelectorShouldSatisfy(kubeClientset, w, namespace, selector, fmt.Sprintf("be in phase %s", phase), func(pod corev1.Pod) error {
+		if pod.Status.Phase != corev1.PodPhase(phase) {
+			return errors.Errorf("pod '%s' is in phase %s", pod.Name, pod.Status.Phase)
+		}
+		return nil
+	})
+}
+
+func ContainerInPodsInNamespaceWithSelectorShouldHaveStateReason(kubeClientset kubernetes.Interface, w common.WaiterConfig, container, namespace, selector, reason string) error {
+	return podsWithSelectorShouldSatisfy(kubeClientset, w, namespace, selector, fmt.Sprintf("have container '%s' with state reason %s", container, reason), func(pod corev1.Pod) error {
+		status, err := getContainerStatus(pod, container)
+		if err != nil {
+			return err
+		}
+		reasons := getContainerStateReasons(status)
+		if !slices.Contains(reasons, reason) {
+			return errors.Errorf("container '%s' of pod '%s' has state reasons %v", container, pod.Name, reasons)
+		}
+		return nil
+	})
+}
+
+func ContainerInPodsInNamespaceWithSelectorShouldBeReady(kubeClientset kubernetes.Interface, w common.WaiterConfig, container, namespace, selector, readiness string) error {
+	var expectedReady bool
+	switch readiness {
+	case containerReady:
+		expectedReady = true
+	case containerNotReady:
+		expectedReady = false
+	default:
+		return errors.Errorf("unsupported container readiness: '%s'", readiness)
+	}
+
+	return podsWithSelectorShouldSatisfy(kubeClientset, w, namespace, selector, fmt.Sprintf("have container '%s' %s", container, readiness), func(pod corev1.Pod) error {
+		status, err := getContainerStatus(pod, container)
+		if err != nil {
+			return err
+		}
+		if status.Ready != expectedReady {
+			return errors.Errorf("container '%s' of pod '%s' has ready set to %t", container, pod.Name, status.Ready)
+		}
+		return nil
+	})
+}
+
+func PodsInNamespaceWithSelectorShouldBeScheduledOnNodesWithSelector(kubeClientset kubernetes.Interface, w common.WaiterConfig, namespace, selector, nodeSelector string) error {
+	labelSelector, err := labels.Parse(nodeSelector)
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse node selector '%s'", nodeSelector)
+	}
+
+	return podsWithSelectorShouldSatisfy(kubeClientset, w, namespace, selector, fmt.Sprintf("be scheduled on nodes with selector '%s'", nodeSelector), func(pod corev1.Pod) error {
+		node, err := getPodNode(kubeClientset, pod)
+		if err != nil {
+			return err
+		}
+		if !labelSelector.Matches(labels.Set(node.Labels)) {
+			return errors.Errorf("pod '%s' is scheduled on node '%s' which does not match selector '%s'", pod.Name, node.Name, nodeSelector)
+		}
+		return nil
+	})
+}
+
+func PodsInNamespaceWithSelectorShouldSatisfyNodeAffinity(kubeClientset kubernetes.Interface, w common.WaiterConfig, namespace, selector string) error {
+	return podsWithSelectorShouldSatisfy(kubeClientset, w, namespace, selector, "be scheduled on nodes satisfying their node selector and affinity", func(pod corev1.Pod) error {
+		node, err := getPodNode(kubeClientset, pod)
+		if err != nil {
+			return err
+		}
+		return validateNodeAffinity(pod, *node)
+	})
+}
+
+func PodsInNamespaceWithSelectorShouldHaveQOSClass(kubeClientset kubernetes.Interface, w common.WaiterConfig, namespace, selector, qosClass string) error {
+	return podsWithSelectorShouldSatisfy(kubeClientset, w, namespace, selector, fmt.Sprintf("have QoS class %s", qosClass), func(pod corev1.Pod) error {
+		if pod.Status.QOSClass != corev1.PodQOSClass(qosClass) {
+			return errors.Errorf("pod '%s' has QoS class '%s'", pod.Name, pod.Status.QOSClass)
+		}
+		return nil
+	})
+}
+
+func ContainerInPodsInNamespaceWithSelectorShouldRunImage(kubeClientset kubernetes.Interface, w common.WaiterConfig, container, namespace, selector, image string) error {
+	return podsWithSelectorShouldSatisfy(kubeClientset, w, namespace, selector, fmt.Sprintf("have container '%s' running image %s", container, image), func(pod corev1.Pod) error {
+		status, err := getContainerStatus(pod, container)
+		if err != nil {
+			return err
+		}
+		if !isImageMatch(status, image) {
+			return errors.Errorf("container '%s' of pod '%s' is running image '%s' with id '%s'", container, pod.Name, status.Image, status.ImageID)
+		}
+		return nil
+	})
+}
@@ -17,6 +17,7 @@ package pod
 import (
 	"bufio"
 	"context"
+	"math"
 	"strings"
 	"time"
 
@@ -25,10 +26,28 @@ import (
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
 	"k8s.io/client-go/kubernetes"
 )
 
+const (
+	evictionAllowed  = "allowed"
+	evictionRejected = "rejected"
+
+	killGracefully  = "gracefully"
+	killForcefully  = "forcefully"
+	killUnitPod     = "pod"
+	killUnitPods    = "pods"
+	killUnitPercent = "percent of pods"
+
+	containerReady    = "ready"
+	containerNotReady = "not ready"
+)
+
 func GetPodListWithLabelSelector(kubeClientset kubernetes.Interface, namespace, labelSelector string) (*corev1.PodList, error) {
 	return GetPodListWithLabelSelectorAndFieldSelector(kubeClientset, namespace, labelSelector, "")
 }
@@ -48,31 +67,62 @@ func GetPodListWithLabelSelectorAndFieldSelector(kubeClientset kubernetes.Interf
 	return pods.(*corev1.PodList), nil
 }
 
+func EvictPod(kubeClientset kubernetes.Interface, pod corev1.Pod) error {
+	if err := common.ValidateClientset(kubeClientset); err != nil {
+		return err
+	}
+
+	eviction := &policyv1.Eviction{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      pod.Name,
+			Namespace: pod.Namespace,
+		},
+	}
+	return kubeClientset.PolicyV1().Evictions(pod.Namespace).Evict(context.Background(), eviction)
+}
+
 func countStringInPodLogs(kubeClientset kubernetes.Interface, pod corev1.Pod, since time.Time, stringsToFind ...string) (int, error) {
+	matchers := make([]logMatcher, len(stringsToFind))
+	for i, stringToFind := range stringsToFind {
+		matcher, err := newLogMatcher(matchTypeSubstring, stringToFind)
+		if err != nil {
+			return 0, err
+		}
+		matchers[i] = matcher
+	}
+	return countMatchesInPodLogs(kubeClientset, pod, since, logTarget{}, matchers...)
+}
+
+func countMatchesInPodLogs(kubeClientset kubernetes.Interface, pod corev1.Pod, since time.Time, target logTarget, matchers ...logMatcher) (int, error) {
 	foundCount := 0
 	if err := common.ValidateClientset(kubeClientset); err != nil {
 		return foundCount, err
 	}
+	containers, err := target.containers(pod)
+	if err != nil {
+		return foundCount, err
+	}
 	var sinceTime metav1.Time = metav1.NewTime(since)
-	for _, container := range pod.Spec.Containers {
+	for _, container := range containers {
 		podLogOpts := corev1.PodLogOptions{
 			SinceTime: &sinceTime,
-			Container: container.Name,
+			Container: container,
+			Previous:  target.previous,
 		}
 
 		req := kubeClientset.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &podLogOpts)
 		podLogs, err := req.Stream(context.Background())
 		if err != nil {
-			return 0, errors.Errorf("Error in opening stream for pod '%s', container '%s' : '%s'", pod.Name, container.Name, string(err.Error()))
+			return 0, errors.Errorf("Error in opening stream for pod '%s', container '%s' : '%s'", pod.Name, container, string(err.Error()))
 		}
 
 		scanner := bufio.NewScanner(podLogs)
 		for scanner.Scan() {
 			line := scanner.Text()
-			for _, stringToFind := range stringsToFind {
-				if strings.Contains(line, stringToFind) {
+			for _, matcher := range matchers {
+				if matcher.match(line) {
 					foundCount += 1
-					log.Infof("Found string '%s' in line '%s' in container '%s' of pod '%s'", stringToFind, line, container.Name, pod.Name)
+					log.Infof("Found %s in line '%s' in %s of pod '%s'", matcher.description, line, target.describe(container), pod.Name)
 				}
 			}
 		}
@@ -80,3 +130,311 @@ func countStringInPodLogs(kubeClientset kubernetes.Interface, pod corev1.Pod, si
 	}
 	return foundCount, nil
 }
+
+func isPodInState(pod corev1.Pod, state string) (bool, error) {
+	switch state {
+	case common.StateFound:
+		return true, nil
+	case common.StateReady:
+		for _, condition := range pod.Status.Conditions {
+			if condition.Type == corev1.PodReady {
+				return condition.Status == corev1.ConditionTrue, nil
+			}
+		}
+		return false, nil
+	case common.StateRunning:
+		return pod.Status.Phase == corev1.PodRunning, nil
+	case common.StateSucceeded:
+		return pod.Status.Phase == corev1.PodSucceeded, nil
+	default:
+		return false, errors.Errorf("unsupported pod state: '%s'", state)
+	}
+}
+
+func evictPodWithResult(kubeClientset kubernetes.Interface, pod corev1.Pod) (bool, error) {
+	err := EvictPod(kubeClientset, pod)
+	switch {
+	case err == nil:
+		log.Infof("pod %s/%s has been evicted", pod.Namespace, pod.Name)
+		return false, nil
+	case kerrors.IsTooManyRequests(err):
+		log.Infof("eviction of pod %s/%s was rejected: %v", pod.Namespace, pod.Name, err)
+		return true, nil
+	default:
+		return false, errors.Wrapf(err, "failed to evict pod %s/%s", pod.Namespace, pod.Name)
+	}
+}
+
+func validateEvictionResult(expectedResult string, rejected bool, target string) error {
+	switch expectedResult {
+	case evictionAllowed:
+		if rejected {
+			return errors.Errorf("expected eviction of %s to be allowed, but it was rejected", target)
+		}
+	case evictionRejected:
+		if !rejected {
+			return errors.Errorf("expected eviction of %s to be rejected, but it was allowed", target)
+		}
+	default:
+		return errors.Errorf("unsupported eviction result: '%s'", expectedResult)
+	}
+	return nil
+}
+
+func getKillCount(total, amount int, unit string) (int, error) {
+	var count int
+	switch unit {
+	case killUnitPod, killUnitPods:
+		count = amount
+	case killUnitPercent:
+		if amount > 100 {
+			return 0, errors.Errorf("percentage of pods to kill should be at most 100, got %d", amount)
+		}
+		count = int(math.Ceil(float64(total) * float64(amount) / 100))
+	default:
+		return 0, errors.Errorf("unsupported kill unit: '%s'", unit)
+	}
+	if count > total {
+		return 0, errors.Errorf("cannot kill %d pods, only %d pods matched", count, total)
+	}
+	return count, nil
+}
+
+// getDesiredPodCount returns the replicas desired by the controller owning the pod
+func getDesiredPodCount(kubeClientset kubernetes.Interface, pod corev1.Pod) (int, error) {
+	owner := metav1.GetControllerOf(&pod)
+	if owner == nil {
+		return 0, errors.Errorf("pod %s/%s is not owned by a controller and will not be restored", pod.Namespace, pod.Name)
+	}
+
+	switch owner.Kind {
+	case "ReplicaSet":
+		rs, err := kubeClientset.AppsV1().ReplicaSets(pod.Namespace).Get(context.Background(), owner.Name, metav1.GetOptions{})
+		if err != nil {
+			return 0, err
+		}
+		return int(replicasOrDefault(rs.Spec.Replicas)), nil
+	case "StatefulSet":
+		sts, err := kubeClientset.AppsV1().StatefulSets(pod.Namespace).Get(context.Background(), owner.Name, metav1.GetOptions{})
+		if err != nil {
+			return 0, err
+		}
+		return int(replicasOrDefault(sts.Spec.Replicas)), nil
+	case "DaemonSet":
+		ds, err := kubeClientset.AppsV1().DaemonSets(pod.Namespace).Get(context.Background(), owner.Name, metav1.GetOptions{})
+		if err != nil {
+			return 0, err
+		}
+		return int(ds.Status.DesiredNumberScheduled), nil
+	default:
+		return 0, errors.Errorf("unsupported controller kind '%s' owning pod %s/%s", owner.Kind, pod.Namespace, pod.Name)
+	}
+}
+
+// replicasOrDefault defaults replicas to 1, as the API server does when they are not set
+func replicasOrDefault(replicas *int32) int32 {
+	if replicas == nil {
+		return 1
+	}
+	return *replicas
+}
+
+func filterPodsWithSelector(pods []corev1.Pod, namespace, selector string) ([]corev1.Pod, error) {
+	labelSelector, err := labels.Parse(selector)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse selector '%s'", selector)
+	}
+
+	filtered := []corev1.Pod{}
+	for _, pod := range pods {
+		if pod.Namespace == namespace && labelSelector.Matches(labels.Set(pod.Labels)) {
+			filtered = append(filtered, pod)
+		}
+	}
+	return filtered, nil
+}
+
+func podsLogLinesMatchingShouldBe(kubeClientset kubernetes.Interface, target logTarget, comparison string, expectedCount int, namespace, selector, matchType, expression string, since time.Time) error {
+	matcher, err := newLogMatcher(matchType, expression)
+	if err != nil {
+		return err
+	}
+
+	pods, err := GetPodListWithLabelSelector(kubeClientset, namespace, selector)
+	if err != nil {
+		return err
+	}
+	if len(pods.Items) == 0 {
+		return errors.Errorf("No pods matched selector '%s'", selector)
+	}
+
+	var count int
+	for _, pod := range pods.Items {
+		podCount, err := countMatchesInPodLogs(kubeClientset, pod, since, target, matcher)
+		if err != nil {
+			return err
+		}
+		count += podCount
+	}
+
+	ok, err := common.CompareCount(comparison, count, expectedCount)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.Errorf("expected %s %d log lines matching %s in %s of pods in namespace %s with selector '%s', found %d", comparison, expectedCount, matcher.description, target.describe(""), namespace, selector, count)
+	}
+	log.Infof("found %d log lines matching %s in %s of pods in namespace %s with selector '%s'", count, matcher.description, target.describe(""), namespace, selector)
+	return nil
+}
+
+// podsWithSelectorShouldSatisfy waits until there are pods with the selector and all of them pass the check
+func podsWithSelectorShouldSatisfy(kubeClientset kubernetes.Interface, w common.WaiterConfig, namespace, selector, description string, check func(pod corev1.Pod) error) error {
+	var (
+		counter  int
+		checkErr error
+	)
+
+	for {
+		if counter >= w.GetTries() {
+			return errors.Wrapf(checkErr, "waiter timed out waiting for pods in namespace %s with selector '%s' to %s", namespace, selector, description)
+		}
+
+		pods, err := GetPodListWithLabelSelector(kubeClientset, namespace, selector)
+		if err != nil {
+			return err
+		}
+
+		checkErr = nil
+		if len(pods.Items) == 0 {
+			checkErr = errors.Errorf("no pods matched selector '%s'", selector)
+		}
+		for _, pod := range pods.Items {
+			if checkErr = check(pod); checkErr != nil {
+				break
+			}
+		}
+
+		if checkErr == nil {
+			log.Infof("%d pods in namespace %s with selector '%s' were verified to %s", len(pods.Items), namespace, selector, description)
+			return nil
+		}
+
+		log.Infof("waiting for pods in namespace %s with selector '%s' to %s: %v", namespace, selector, description, checkErr)
+		counter++
+		time.Sleep(w.GetInterval())
+	}
+}
+
+func getContainerStatus(pod corev1.Pod, container string) (corev1.ContainerStatus, error) {
+	statuses := append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...)
+	statuses = append(statuses, pod.Status.ContainerStatuses...)
+	statuses = append(statuses, pod.Status.EphemeralContainerStatuses...)
+	for _, status := range statuses {
+		if status.Name == container {
+			return status, nil
+		}
+	}
+	return corev1.ContainerStatus{}, errors.Errorf("pod '%s' has no status for container '%s'", pod.Name, container)
+}
+
+// getContainerStateReasons returns the reasons of the current and last state, e.g. OOMKilled while in CrashLoopBackOff
+func getContainerStateReasons(status corev1.ContainerStatus) []string {
+	var reasons []string
+	for _, state := range []corev1.ContainerState{status.State, status.LastTerminationState} {
+		if state.Waiting != nil {
+			reasons = append(reasons, state.Waiting.Reason)
+		}
+		if state.Terminated != nil {
+			reasons = append(reasons, state.Terminated.Reason)
+		}
+	}
+	return reasons
+}
+
+// isImageMatch accepts the image reference, or a digest the image id ends with
+func isImageMatch(status corev1.ContainerStatus, image string) bool {
+	if status.Image == image || status.ImageID == image {
+		return true
+	}
+	if strings.HasPrefix(image, "sha256:") || strings.Contains(image, "@") {
+		digest := image[strings.LastIndex(image, "@")+1:]
+		return strings.HasSuffix(status.ImageID, "@"+digest) || status.ImageID == digest
+	}
+	return false
+}
+
+func getPodNode(kubeClientset kubernetes.Interface, pod corev1.Pod) (*corev1.Node, error) {
+	if pod.Spec.NodeName == "" {
+		return nil, errors.Errorf("pod '%s' is not scheduled", pod.Name)
+	}
+	return kubeClientset.CoreV1().Nodes().Get(context.Background(), pod.Spec.NodeName, metav1.GetOptions{})
+}
+
+// validateNodeAffinity checks the node selector and the required node affinity of the pod against the node
+func validateNodeAffinity(pod corev1.Pod, node corev1.Node) error {
+	if !labels.SelectorFromSet(pod.Spec.NodeSelector).Matches(labels.Set(node.Labels)) {
+		return errors.Errorf("node '%s' of pod '%s' does not match node selector %v", node.Name, pod.Name, pod.Spec.NodeSelector)
+	}
+
+	affinity := pod.Spec.Affinity
+	if affinity == nil || affinity.NodeAffinity == nil || affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		return nil
+	}
+
+	for _, term := range affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms {
+		matches, err := isNodeSelectorTermMatch(term, node)
+		if err != nil {
+			return err
+		}
+		if matches {
+			return nil
+		}
+	}
+	return errors.Errorf("node '%s' of pod '%s' does not match any required node affinity term", node.Name, pod.Name)
+}
+
+func isNodeSelectorTermMatch(term corev1.NodeSelectorTerm, node corev1.Node) (bool, error) {
+	if len(term.MatchExpressions) == 0 && len(term.MatchFields) == 0 {
+		return false, nil
+	}
+
+	labelSelector, err := nodeSelectorRequirementsAsSelector(term.MatchExpressions)
+	if err != nil {
+		return false, err
+	}
+	if !labelSelector.Matches(labels.Set(node.Labels)) {
+		return false, nil
+	}
+
+	fieldSelector, err := nodeSelectorRequirementsAsSelector(term.MatchFields)
+	if err != nil {
+		return false, err
+	}
+	return fieldSelector.Matches(labels.Set{"metadata.name": node.Name}), nil
+}
+
+func nodeSelectorRequirementsAsSelector(requirements []corev1.NodeSelectorRequirement) (labels.Selector, error) {
+	operators := map[corev1.NodeSelectorOperator]selection.Operator{
+		corev1.NodeSelectorOpIn:           selection.In,
+		corev1.NodeSelectorOpNotIn:        selection.NotIn,
+		corev1.NodeSelectorOpExists:       selection.Exists,
+		corev1.NodeSelectorOpDoesNotExist: selection.DoesNotExist,
+		corev1.NodeSelectorOpGt:           selection.GreaterThan,
+		corev1.NodeSelectorOpLt:           selection.LessThan,
+	}
+
+	selector := labels.NewSelector()
+	for _, requirement := range requirements {
+		operator, ok := operators[requirement.Operator]
+		if !ok {
+			return nil, errors.Errorf("unsupported node selector operator: '%s'", requirement.Operator)
+		}
+		r, err := labels.NewRequirement(requirement.Key, operator, requirement.Values)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid node selector requirement on '%s'", requirement.Key)
+		}
+		selector = selector.Add(*r)
+	}
+	return selector, nil
+}
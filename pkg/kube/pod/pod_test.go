@@ -15,18 +15,32 @@ limitations under the License.
 package pod
 
 import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/keikoproj/kubedog/internal/util"
+	"github.com/keikoproj/kubedog/pkg/kube/common"
+	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
 	fakeDiscovery "k8s.io/client-go/discovery/fake"
 	"k8s.io/client-go/dynamic"
 	fakeDynamic "k8s.io/client-go/dynamic/fake"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/fake"
+	kTesting "k8s.io/client-go/testing"
 )
 
 func Test_PodsInNamespaceWithSelectorShouldHaveLabels(t *testing.T) {
@@ -124,6 +138,34 @@ func Test_PodsInNamespaceWithSelectorShouldHaveLabels(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "Pods should have set-based labels",
+			fields: fields{
+				KubeInterface:      clientNoErr,
+				DiscoveryInterface: &fakeDiscoveryClient,
+				DynamicInterface:   fakeDynamicClient,
+			},
+			args: args{
+				selector:  "app=foo",
+				namespace: "foo",
+				labels:    "app in (foo,bar),label notin (false),!missing",
+			},
+			wantErr: false,
+		},
+		{
+			name: "Error from malformed labels",
+			fields: fields{
+				KubeInterface:      clientNoErr,
+				DiscoveryInterface: &fakeDiscoveryClient,
+				DynamicInterface:   fakeDynamicClient,
+			},
+			args: args{
+				selector:  "app=foo",
+				namespace: "foo",
+				labels:    "app=foo,label=true=false",
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -191,3 +233,1096 @@ func TestPodsInNamespaceWithLabelSelectorConvergeToFieldSelector(t *testing.T) {
 		})
 	}
 }
+
+func TestPodsWithSelectorCountShouldBe(t *testing.T) {
+	type args struct {
+		kubeClientset kubernetes.Interface
+		w             common.WaiterConfig
+		comparison    string
+		expectedCount int
+		namespace     string
+		selector      string
+		state         string
+	}
+	namespaceName := "test-ns"
+	ns := v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespaceName}}
+	podReady := v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pod-xhhxj",
+			Namespace: namespaceName,
+			Labels: map[string]string{
+				"app": "test-service",
+			},
+		},
+		Status: v1.PodStatus{
+			Phase: v1.PodRunning,
+			Conditions: []v1.PodCondition{
+				{
+					Type:   v1.PodReady,
+					Status: v1.ConditionTrue,
+				},
+			},
+		},
+	}
+	podPending := v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pod-xhhzd",
+			Namespace: namespaceName,
+			Labels: map[string]string{
+				"app": "test-service",
+			},
+		},
+		Status: v1.PodStatus{
+			Phase: v1.PodPending,
+		},
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "Positive Test: exactly 2 pods found",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(&ns, &podReady, &podPending),
+				comparison:    common.ComparisonExactly,
+				expectedCount: 2,
+				namespace:     namespaceName,
+				selector:      "app=test-service",
+				state:         common.StateFound,
+			},
+		},
+		{
+			name: "Positive Test: at least 1 pod ready",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(&ns, &podReady, &podPending),
+				comparison:    common.ComparisonAtLeast,
+				expectedCount: 1,
+				namespace:     namespaceName,
+				selector:      "app=test-service",
+				state:         common.StateReady,
+			},
+		},
+		{
+			name: "Positive Test: at most 1 pod running",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(&ns, &podReady, &podPending),
+				comparison:    common.ComparisonAtMost,
+				expectedCount: 1,
+				namespace:     namespaceName,
+				selector:      "app=test-service",
+				state:         common.StateRunning,
+			},
+		},
+		{
+			name: "Negative Test: exactly 1 pod succeeded",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(&ns, &podReady, &podPending),
+				comparison:    common.ComparisonExactly,
+				expectedCount: 1,
+				namespace:     namespaceName,
+				selector:      "app=test-service",
+				state:         common.StateSucceeded,
+			},
+			wantErr: true,
+		},
+		{
+			name: "Negative Test: invalid state",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(&ns, &podReady),
+				comparison:    common.ComparisonExactly,
+				expectedCount: 1,
+				namespace:     namespaceName,
+				selector:      "app=test-service",
+				state:         "invalid-state",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.args.w = common.NewWaiterConfig(1, time.Second)
+			if err := PodsWithSelectorCountShouldBe(tt.args.kubeClientset, tt.args.w, tt.args.comparison, tt.args.expectedCount, tt.args.namespace, tt.args.selector, tt.args.state); (err != nil) != tt.wantErr {
+				t.Errorf("PodsWithSelectorCountShouldBe() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEvictPodsInNamespaceWithSelector(t *testing.T) {
+	type args struct {
+		kubeClientset  *fake.Clientset
+		count          int
+		namespace      string
+		selector       string
+		expectedResult string
+	}
+	namespaceName := "test-ns"
+	pod := v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pod-xhhxj",
+			Namespace: namespaceName,
+			Labels: map[string]string{
+				"app": "test-service",
+			},
+		},
+	}
+	otherPod := pod.DeepCopy()
+	otherPod.Name = "pod-zq8wd"
+	tests := []struct {
+		name        string
+		args        args
+		wantEvicted int
+		wantErr     bool
+	}{
+		{
+			name: "Positive Test: eviction allowed",
+			args: args{
+				kubeClientset:  newFakeClientsetWithEvictionError(nil, &pod),
+				count:          1,
+				namespace:      namespaceName,
+				selector:       "app=test-service",
+				expectedResult: evictionAllowed,
+			},
+			wantEvicted: 1,
+		},
+		{
+			name: "Positive Test: only the requested count is evicted",
+			args: args{
+				kubeClientset:  newFakeClientsetWithEvictionError(nil, &pod, otherPod),
+				count:          1,
+				namespace:      namespaceName,
+				selector:       "app=test-service",
+				expectedResult: evictionAllowed,
+			},
+			wantEvicted: 1,
+		},
+		{
+			name: "Positive Test: all requested pods are evicted",
+			args: args{
+				kubeClientset:  newFakeClientsetWithEvictionError(nil, &pod, otherPod),
+				count:          2,
+				namespace:      namespaceName,
+				selector:       "app=test-service",
+				expectedResult: evictionAllowed,
+			},
+			wantEvicted: 2,
+		},
+		{
+			name: "Positive Test: eviction rejected",
+			args: args{
+				kubeClientset:  newFakeClientsetWithEvictionError(kerrors.NewTooManyRequests("Cannot evict pod as it would violate the pod's disruption budget.", 0), &pod),
+				count:          1,
+				namespace:      namespaceName,
+				selector:       "app=test-service",
+				expectedResult: evictionRejected,
+			},
+			wantEvicted: 1,
+		},
+		{
+			name: "Negative Test: eviction expected to be rejected",
+			args: args{
+				kubeClientset:  newFakeClientsetWithEvictionError(nil, &pod),
+				count:          1,
+				namespace:      namespaceName,
+				selector:       "app=test-service",
+				expectedResult: evictionRejected,
+			},
+			wantErr: true,
+		},
+		{
+			name: "Negative Test: eviction fails with unexpected error",
+			args: args{
+				kubeClientset:  newFakeClientsetWithEvictionError(kerrors.NewInternalError(errors.New("an error")), &pod),
+				count:          1,
+				namespace:      namespaceName,
+				selector:       "app=test-service",
+				expectedResult: evictionRejected,
+			},
+			wantErr: true,
+		},
+		{
+			name: "Negative Test: no pods matched selector",
+			args: args{
+				kubeClientset:  newFakeClientsetWithEvictionError(nil),
+				count:          1,
+				namespace:      namespaceName,
+				selector:       "app=test-service",
+				expectedResult: evictionAllowed,
+			},
+			wantErr: true,
+		},
+		{
+			name: "Negative Test: fewer pods than requested",
+			args: args{
+				kubeClientset:  newFakeClientsetWithEvictionError(nil, &pod),
+				count:          2,
+				namespace:      namespaceName,
+				selector:       "app=test-service",
+				expectedResult: evictionAllowed,
+			},
+			wantErr: true,
+		},
+		{
+			name: "Negative Test: invalid count",
+			args: args{
+				kubeClientset:  newFakeClientsetWithEvictionError(nil, &pod),
+				count:          0,
+				namespace:      namespaceName,
+				selector:       "app=test-service",
+				expectedResult: evictionAllowed,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := EvictPodsInNamespaceWithSelector(tt.args.kubeClientset, tt.args.count, tt.args.namespace, tt.args.selector, tt.args.expectedResult)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("EvictPodsInNamespaceWithSelector() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			var evicted int
+			for _, action := range tt.args.kubeClientset.Actions() {
+				if action.GetVerb() == "create" && action.GetSubresource() == "eviction" {
+					evicted++
+				}
+			}
+			if evicted != tt.wantEvicted {
+				t.Errorf("EvictPodsInNamespaceWithSelector() evicted %d pods, want %d", evicted, tt.wantEvicted)
+			}
+		})
+	}
+}
+
+func newFakeClientsetWithEvictionError(evictionErr error, objects ...runtime.Object) *fake.Clientset {
+	client := fake.NewSimpleClientset(objects...)
+	client.PrependReactor("create", "pods", func(action kTesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "eviction" {
+			return false, nil, nil
+		}
+		return true, nil, evictionErr
+	})
+	return client
+}
+
+func TestKillRandomPodsInNamespaceWithSelector(t *testing.T) {
+	type args struct {
+		kubeClientset kubernetes.Interface
+		mode          string
+		amount        int
+		unit          string
+		namespace     string
+		selector      string
+	}
+	namespaceName := "test-ns"
+	newPod := func(name string) *v1.Pod {
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespaceName,
+				Labels: map[string]string{
+					"app": "test-service",
+				},
+			},
+		}
+	}
+	tests := []struct {
+		name       string
+		args       args
+		wantKilled int
+		wantErr    bool
+	}{
+		{
+			name: "Positive Test: gracefully kill 2 pods",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(newPod("pod-1"), newPod("pod-2"), newPod("pod-3")),
+				mode:          killGracefully,
+				amount:        2,
+				unit:          killUnitPods,
+				namespace:     namespaceName,
+				selector:      "app=test-service",
+			},
+			wantKilled: 2,
+		},
+		{
+			name: "Positive Test: forcefully kill 50 percent of pods rounds up",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(newPod("pod-1"), newPod("pod-2"), newPod("pod-3")),
+				mode:          killForcefully,
+				amount:        50,
+				unit:          killUnitPercent,
+				namespace:     namespaceName,
+				selector:      "app=test-service",
+			},
+			wantKilled: 2,
+		},
+		{
+			name: "Negative Test: more pods than matched",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(newPod("pod-1")),
+				mode:          killGracefully,
+				amount:        2,
+				unit:          killUnitPods,
+				namespace:     namespaceName,
+				selector:      "app=test-service",
+			},
+			wantErr: true,
+		},
+		{
+			name: "Negative Test: no pods matched selector",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(),
+				mode:          killGracefully,
+				amount:        1,
+				unit:          killUnitPod,
+				namespace:     namespaceName,
+				selector:      "app=test-service",
+			},
+			wantErr: true,
+		},
+		{
+			name: "Negative Test: invalid mode",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(newPod("pod-1")),
+				mode:          "invalid-mode",
+				amount:        1,
+				unit:          killUnitPod,
+				namespace:     namespaceName,
+				selector:      "app=test-service",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			killed, err := KillRandomPodsInNamespaceWithSelector(tt.args.kubeClientset, tt.args.mode, tt.args.amount, tt.args.unit, tt.args.namespace, tt.args.selector)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("KillRandomPodsInNamespaceWithSelector() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if len(killed) != tt.wantKilled {
+				t.Errorf("KillRandomPodsInNamespaceWithSelector() killed = %d, want %d", len(killed), tt.wantKilled)
+			}
+		})
+	}
+}
+
+func TestPodsInNamespaceWithSelectorShouldRecover(t *testing.T) {
+	type args struct {
+		kubeClientset kubernetes.Interface
+		w             common.WaiterConfig
+		namespace     string
+		selector      string
+		killedPods    []v1.Pod
+		timeout       time.Duration
+	}
+	namespaceName := "test-ns"
+	replicas := int32(2)
+	replicaSet := appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-service-rs", Namespace: namespaceName},
+		Spec:       appsv1.ReplicaSetSpec{Replicas: &replicas},
+	}
+	isController := true
+	newPod := func(name, uid string) *v1.Pod {
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespaceName,
+				UID:       types.UID(uid),
+				Labels: map[string]string{
+					"app": "test-service",
+				},
+				OwnerReferences: []metav1.OwnerReference{
+					{Kind: "ReplicaSet", Name: replicaSet.Name, Controller: &isController},
+				},
+			},
+			Status: v1.PodStatus{
+				Phase: v1.PodRunning,
+				Conditions: []v1.PodCondition{
+					{
+						Type:   v1.PodReady,
+						Status: v1.ConditionTrue,
+					},
+				},
+			},
+		}
+	}
+	killedPod := newPod("pod-1", "uid-1")
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "Positive Test: killed pod replaced",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(&replicaSet, newPod("pod-2", "uid-2"), newPod("pod-3", "uid-3")),
+				namespace:     namespaceName,
+				selector:      "app=test-service",
+				killedPods:    []v1.Pod{*killedPod},
+			},
+		},
+		{
+			name: "Negative Test: desired ready count not restored",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(&replicaSet, newPod("pod-2", "uid-2")),
+				namespace:     namespaceName,
+				selector:      "app=test-service",
+				killedPods:    []v1.Pod{*killedPod},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Negative Test: killed pod still present",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(&replicaSet, killedPod, newPod("pod-2", "uid-2")),
+				namespace:     namespaceName,
+				selector:      "app=test-service",
+				killedPods:    []v1.Pod{*killedPod},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Negative Test: no pods killed with selector",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(&replicaSet, newPod("pod-2", "uid-2")),
+				namespace:     namespaceName,
+				selector:      "app=other-service",
+				killedPods:    []v1.Pod{*killedPod},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.args.w = common.NewWaiterConfig(1, time.Millisecond)
+			if err := PodsInNamespaceWithSelectorShouldRecover(tt.args.kubeClientset, tt.args.w, tt.args.namespace, tt.args.selector, tt.args.killedPods, tt.args.timeout); (err != nil) != tt.wantErr {
+				t.Errorf("PodsInNamespaceWithSelectorShouldRecover() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPodsInNamespaceWithSelectorShouldHaveLogLinesMatching(t *testing.T) {
+	type args struct {
+		kubeClientset kubernetes.Interface
+		comparison    string
+		expectedCount int
+		matchType     string
+		expression    string
+	}
+	namespaceName := "test-ns"
+	selector := "app=test-service"
+	newPod := func(name string) *v1.Pod {
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespaceName,
+				Labels: map[string]string{
+					"app": "test-service",
+				},
+			},
+			Spec: v1.PodSpec{
+				Containers: []v1.Container{{Name: "main"}},
+			},
+		}
+	}
+	// the fake clientset returns 'fake logs' as the logs of every container
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "Positive Test: exactly 2 lines matching regex",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(newPod("pod-1"), newPod("pod-2")),
+				comparison:    common.ComparisonExactly,
+				expectedCount: 2,
+				matchType:     matchTypeRegex,
+				expression:    "^fake l.gs$",
+			},
+		},
+		{
+			name: "Positive Test: at most 0 lines matching json query",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(newPod("pod-1")),
+				comparison:    common.ComparisonAtMost,
+				expectedCount: 0,
+				matchType:     matchTypeJSONQuery,
+				expression:    "level = error",
+			},
+		},
+		{
+			name: "Negative Test: at least 3 lines matching substring",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(newPod("pod-1"), newPod("pod-2")),
+				comparison:    common.ComparisonAtLeast,
+				expectedCount: 3,
+				matchType:     matchTypeSubstring,
+				expression:    "fake",
+			},
+			wantErr: true,
+		},
+		{
+			name: "Negative Test: no pods matched selector",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(),
+				comparison:    common.ComparisonAtLeast,
+				expectedCount: 0,
+				matchType:     matchTypeSubstring,
+				expression:    "fake",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := PodsInNamespaceWithSelectorShouldHaveLogLinesMatching(tt.args.kubeClientset, tt.args.comparison, tt.args.expectedCount, namespaceName, selector, tt.args.matchType, tt.args.expression, time.Now()); (err != nil) != tt.wantErr {
+				t.Errorf("PodsInNamespaceWithSelectorShouldHaveLogLinesMatching() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPodsInNamespaceWithSelectorHaveNoErrorsInLogsSinceTime(t *testing.T) {
+	namespaceName := "test-ns"
+	pod := v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pod-1",
+			Namespace: namespaceName,
+			Labels: map[string]string{
+				"app": "test-service",
+			},
+		},
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{{Name: "main"}},
+		},
+	}
+	tests := []struct {
+		name          string
+		errorPatterns []string
+		wantErr       bool
+	}{
+		{
+			name:          "Positive Test: no line matches error patterns",
+			errorPatterns: []string{`"level":"error"`, "level=error"},
+		},
+		{
+			name:          "Negative Test: line matches custom error pattern",
+			errorPatterns: []string{`^fake`},
+			wantErr:       true,
+		},
+		{
+			name:          "Positive Test: quoted pattern matches literally",
+			errorPatterns: []string{regexp.QuoteMeta(`fake.*`)},
+		},
+		{
+			name:          "Negative Test: invalid error pattern",
+			errorPatterns: []string{`level=(error`},
+			wantErr:       true,
+		},
+		{
+			name:    "Negative Test: no error patterns",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kubeClientset := fake.NewSimpleClientset(&pod)
+			if err := PodsInNamespaceWithSelectorHaveNoErrorsInLogsSinceTime(kubeClientset, namespaceName, "app=test-service", time.Now(), tt.errorPatterns); (err != nil) != tt.wantErr {
+				t.Errorf("PodsInNamespaceWithSelectorHaveNoErrorsInLogsSinceTime() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestContainerInPodsInNamespaceWithSelectorShouldHaveLogLinesMatching(t *testing.T) {
+	type args struct {
+		instance  string
+		container string
+	}
+	namespaceName := "test-ns"
+	pod := v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pod-1",
+			Namespace: namespaceName,
+			Labels: map[string]string{
+				"app": "test-service",
+			},
+		},
+		Spec: v1.PodSpec{
+			InitContainers: []v1.Container{{Name: "init"}},
+			Containers:     []v1.Container{{Name: "main"}},
+		},
+		Status: v1.PodStatus{
+			ContainerStatuses: []v1.ContainerStatus{{Name: "main", RestartCount: 1}},
+		},
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "Positive Test: current instance of init container",
+			args: args{instance: logInstanceCurrent, container: "init"},
+		},
+		{
+			name: "Positive Test: previous instance of crashed container",
+			args: args{instance: logInstancePrevious, container: "main"},
+		},
+		{
+			name:    "Negative Test: previous instance of container never restarted",
+			args:    args{instance: logInstancePrevious, container: "init"},
+			wantErr: true,
+		},
+		{
+			name:    "Negative Test: invalid instance",
+			args:    args{instance: "next", container: "main"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kubeClientset := fake.NewSimpleClientset(&pod)
+			if err := ContainerInPodsInNamespaceWithSelectorShouldHaveLogLinesMatching(kubeClientset, tt.args.instance, tt.args.container, common.ComparisonExactly, 1, namespaceName, "app=test-service", matchTypeSubstring, "fake logs", time.Now()); (err != nil) != tt.wantErr {
+				t.Errorf("ContainerInPodsInNamespaceWithSelectorShouldHaveLogLinesMatching() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPodsInNamespaceWithSelectorShouldLogLineMatching(t *testing.T) {
+	type args struct {
+		existingPods []runtime.Object
+		createdPod   *v1.Pod
+		matchType    string
+		expression   string
+		timeout      time.Duration
+	}
+	namespaceName := "test-ns"
+	newPod := func(name string) *v1.Pod {
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespaceName,
+				UID:       types.UID(name),
+				Labels: map[string]string{
+					"app": "test-service",
+				},
+			},
+			Spec: v1.PodSpec{
+				Containers: []v1.Container{{Name: "main"}},
+			},
+		}
+	}
+	// the fake clientset returns 'fake logs' as the logs of every container
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "Positive Test: line logged by existing pod",
+			args: args{
+				existingPods: []runtime.Object{newPod("pod-1")},
+				matchType:    matchTypeRegex,
+				expression:   "^fake",
+				timeout:      time.Second,
+			},
+		},
+		{
+			name: "Positive Test: line logged by pod created after start",
+			args: args{
+				createdPod: newPod("pod-2"),
+				matchType:  matchTypeSubstring,
+				expression: "fake logs",
+				timeout:    5 * time.Second,
+			},
+		},
+		{
+			name: "Negative Test: no line matching before deadline",
+			args: args{
+				existingPods: []runtime.Object{newPod("pod-1")},
+				matchType:    matchTypeSubstring,
+				expression:   "ready to serve",
+				timeout:      100 * time.Millisecond,
+			},
+			wantErr: true,
+		},
+		{
+			name: "Negative Test: invalid regex",
+			args: args{
+				existingPods: []runtime.Object{newPod("pod-1")},
+				matchType:    matchTypeRegex,
+				expression:   "(unclosed",
+				timeout:      time.Second,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kubeClientset := fake.NewSimpleClientset(tt.args.existingPods...)
+			if tt.args.createdPod != nil {
+				go func() {
+					time.Sleep(100 * time.Millisecond)
+					_, _ = kubeClientset.CoreV1().Pods(namespaceName).Create(context.Background(), tt.args.createdPod, metav1.CreateOptions{})
+				}()
+			}
+			w := common.NewWaiterConfig(1, 10*time.Millisecond)
+			if err := PodsInNamespaceWithSelectorShouldLogLineMatching(kubeClientset, w, namespaceName, "app=test-service", tt.args.matchType, tt.args.expression, time.Now(), tt.args.timeout); (err != nil) != tt.wantErr {
+				t.Errorf("PodsInNamespaceWithSelectorShouldLogLineMatching() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestStartCapturingLogs(t *testing.T) {
+	namespaceName := "test-ns"
+	pod := v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pod-1",
+			Namespace: namespaceName,
+			Labels: map[string]string{
+				"app": "test-service",
+			},
+		},
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{{Name: "main"}},
+		},
+		Status: v1.PodStatus{
+			ContainerStatuses: []v1.ContainerStatus{{Name: "main"}},
+		},
+	}
+	notADirectory := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(notADirectory, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name        string
+		directory   string
+		restarts    int32
+		listErr     bool
+		wantFile    string
+		wantContent string
+		wantErr     bool
+	}{
+		{
+			name:        "Positive Test: logs captured to file",
+			directory:   filepath.Join(t.TempDir(), "scenario"),
+			wantFile:    "test-ns_pod-1_main.log",
+			wantContent: "fake logs",
+		},
+		{
+			name:        "Positive Test: logs of restarted container appended to file",
+			directory:   filepath.Join(t.TempDir(), "scenario"),
+			restarts:    2,
+			wantFile:    "test-ns_pod-1_main.log",
+			wantContent: "fake logsfake logsfake logs",
+		},
+		{
+			name:      "Negative Test: directory cannot be created",
+			directory: filepath.Join(notADirectory, "scenario"),
+			wantErr:   true,
+		},
+		{
+			name:      "Negative Test: pods cannot be listed",
+			directory: filepath.Join(t.TempDir(), "scenario"),
+			listErr:   true,
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kubeClientset := fake.NewSimpleClientset(pod.DeepCopy())
+			if tt.listErr {
+				kubeClientset.PrependReactor("list", "pods", func(action kTesting.Action) (bool, runtime.Object, error) {
+					return true, nil, errors.New("pods is forbidden")
+				})
+			}
+			watching := make(chan struct{})
+			var watchingOnce sync.Once
+			kubeClientset.PrependWatchReactor("pods", func(action kTesting.Action) (bool, watch.Interface, error) {
+				watchingOnce.Do(func() { close(watching) })
+				return false, nil, nil
+			})
+			w := common.NewWaiterConfig(1, 10*time.Millisecond)
+			capture, err := StartCapturingLogs(kubeClientset, w, namespaceName, "app=test-service", tt.directory)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("StartCapturingLogs() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+
+			filePath := filepath.Join(tt.directory, tt.wantFile)
+			var content []byte
+			waitForContent := func(want string) {
+				for i := 0; i < 100 && string(content) != want; i++ {
+					time.Sleep(10 * time.Millisecond)
+					content, _ = os.ReadFile(filePath)
+				}
+			}
+			waitForContent("fake logs")
+			<-watching
+			restarted := pod.DeepCopy()
+			for restartCount := int32(1); restartCount <= tt.restarts; restartCount++ {
+				restarted.Status.ContainerStatuses[0].RestartCount = restartCount
+				if _, err := kubeClientset.CoreV1().Pods(namespaceName).UpdateStatus(context.Background(), restarted, metav1.UpdateOptions{}); err != nil {
+					t.Fatal(err)
+				}
+				waitForContent(strings.Repeat("fake logs", int(restartCount)+1))
+			}
+			if err := capture.Stop(); err != nil {
+				t.Errorf("LogCapture.Stop() error = %v", err)
+			}
+			if string(content) != tt.wantContent {
+				t.Errorf("captured logs = '%s', want '%s'", string(content), tt.wantContent)
+			}
+		})
+	}
+}
+
+func newPodWithContainerStatus(namespace string, status v1.ContainerStatus) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pod-1",
+			Namespace: namespace,
+			Labels: map[string]string{
+				"app": "test-service",
+			},
+		},
+		Spec: v1.PodSpec{
+			NodeName:   "node-1",
+			Containers: []v1.Container{{Name: status.Name}},
+		},
+		Status: v1.PodStatus{
+			Phase:             v1.PodRunning,
+			QOSClass:          v1.PodQOSBurstable,
+			ContainerStatuses: []v1.ContainerStatus{status},
+		},
+	}
+}
+
+func TestPodsInNamespaceWithSelectorShouldBeInPhase(t *testing.T) {
+	namespaceName := "test-ns"
+	pod := newPodWithContainerStatus(namespaceName, v1.ContainerStatus{Name: "main"})
+	tests := []struct {
+		name    string
+		phase   string
+		wantErr bool
+	}{
+		{
+			name:  "Positive Test: pods running",
+			phase: string(v1.PodRunning),
+		},
+		{
+			name:    "Negative Test: pods not succeeded",
+			phase:   string(v1.PodSucceeded),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kubeClientset := fake.NewSimpleClientset(pod)
+			w := common.NewWaiterConfig(1, time.Millisecond)
+			if err := PodsInNamespaceWithSelectorShouldBeInPhase(kubeClientset, w, namespaceName, "app=test-service", tt.phase); (err != nil) != tt.wantErr {
+				t.Errorf("PodsInNamespaceWithSelectorShouldBeInPhase() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestContainerInPodsInNamespaceWithSelectorShouldHaveStateReason(t *testing.T) {
+	namespaceName := "test-ns"
+	crashLooping := v1.ContainerStatus{
+		Name: "main",
+		State: v1.ContainerState{
+			Waiting: &v1.ContainerStateWaiting{Reason: "CrashLoopBackOff"},
+		},
+		LastTerminationState: v1.ContainerState{
+			Terminated: &v1.ContainerStateTerminated{Reason: "OOMKilled"},
+		},
+	}
+	tests := []struct {
+		name      string
+		container string
+		reason    string
+		wantErr   bool
+	}{
+		{
+			name:      "Positive Test: current state reason",
+			container: "main",
+			reason:    "CrashLoopBackOff",
+		},
+		{
+			name:      "Positive Test: last termination reason",
+			container: "main",
+			reason:    "OOMKilled",
+		},
+		{
+			name:      "Negative Test: reason not found",
+			container: "main",
+			reason:    "ImagePullBackOff",
+			wantErr:   true,
+		},
+		{
+			name:      "Negative Test: unknown container",
+			container: "sidecar",
+			reason:    "CrashLoopBackOff",
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kubeClientset := fake.NewSimpleClientset(newPodWithContainerStatus(namespaceName, crashLooping))
+			w := common.NewWaiterConfig(1, time.Millisecond)
+			if err := ContainerInPodsInNamespaceWithSelectorShouldHaveStateReason(kubeClientset, w, tt.container, namespaceName, "app=test-service", tt.reason); (err != nil) != tt.wantErr {
+				t.Errorf("ContainerInPodsInNamespaceWithSelectorShouldHaveStateReason() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestContainerInPodsInNamespaceWithSelectorShouldBeReady(t *testing.T) {
+	namespaceName := "test-ns"
+	tests := []struct {
+		name      string
+		ready     bool
+		readiness string
+		wantErr   bool
+	}{
+		{
+			name:      "Positive Test: container ready",
+			ready:     true,
+			readiness: containerReady,
+		},
+		{
+			name:      "Positive Test: container not ready",
+			readiness: containerNotReady,
+		},
+		{
+			name:      "Negative Test: container expected to be ready",
+			readiness: containerReady,
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kubeClientset := fake.NewSimpleClientset(newPodWithContainerStatus(namespaceName, v1.ContainerStatus{Name: "main", Ready: tt.ready}))
+			w := common.NewWaiterConfig(1, time.Millisecond)
+			if err := ContainerInPodsInNamespaceWithSelectorShouldBeReady(kubeClientset, w, "main", namespaceName, "app=test-service", tt.readiness); (err != nil) != tt.wantErr {
+				t.Errorf("ContainerInPodsInNamespaceWithSelectorShouldBeReady() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestContainerInPodsInNamespaceWithSelectorShouldRunImage(t *testing.T) {
+	namespaceName := "test-ns"
+	status := v1.ContainerStatus{
+		Name:    "main",
+		Image:   "docker.io/library/nginx:1.25",
+		ImageID: "docker.io/library/nginx@sha256:0123456789abcdef",
+	}
+	tests := []struct {
+		name    string
+		image   string
+		wantErr bool
+	}{
+		{
+			name:  "Positive Test: image reference",
+			image: "docker.io/library/nginx:1.25",
+		},
+		{
+			name:  "Positive Test: image digest",
+			image: "nginx@sha256:0123456789abcdef",
+		},
+		{
+			name:    "Negative Test: other tag",
+			image:   "docker.io/library/nginx:1.26",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kubeClientset := fake.NewSimpleClientset(newPodWithContainerStatus(namespaceName, status))
+			w := common.NewWaiterConfig(1, time.Millisecond)
+			if err := ContainerInPodsInNamespaceWithSelectorShouldRunImage(kubeClientset, w, "main", namespaceName, "app=test-service", tt.image); (err != nil) != tt.wantErr {
+				t.Errorf("ContainerInPodsInNamespaceWithSelectorShouldRunImage() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPodsInNamespaceWithSelectorShouldSatisfyNodeAffinity(t *testing.T) {
+	namespaceName := "test-ns"
+	node := v1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "node-1",
+			Labels: map[string]string{
+				"kubernetes.io/os":                 "linux",
+				"topology.kubernetes.io/zone":      "us-west-2a",
+				"node.kubernetes.io/instance-type": "m5.large",
+			},
+		},
+	}
+	withAffinity := func(nodeSelector map[string]string, terms ...v1.NodeSelectorTerm) *v1.Pod {
+		pod := newPodWithContainerStatus(namespaceName, v1.ContainerStatus{Name: "main"})
+		pod.Spec.NodeSelector = nodeSelector
+		if len(terms) > 0 {
+			pod.Spec.Affinity = &v1.Affinity{
+				NodeAffinity: &v1.NodeAffinity{
+					RequiredDuringSchedulingIgnoredDuringExecution: &v1.NodeSelector{NodeSelectorTerms: terms},
+				},
+			}
+		}
+		return pod
+	}
+	zoneTerm := func(operator v1.NodeSelectorOperator, zones ...string) v1.NodeSelectorTerm {
+		return v1.NodeSelectorTerm{
+			MatchExpressions: []v1.NodeSelectorRequirement{
+				{Key: "topology.kubernetes.io/zone", Operator: operator, Values: zones},
+			},
+		}
+	}
+	tests := []struct {
+		name    string
+		pod     *v1.Pod
+		wantErr bool
+	}{
+		{
+			name: "Positive Test: node selector and affinity satisfied",
+			pod:  withAffinity(map[string]string{"kubernetes.io/os": "linux"}, zoneTerm(v1.NodeSelectorOpIn, "us-west-2a", "us-west-2b")),
+		},
+		{
+			name: "Positive Test: one of the affinity terms satisfied",
+			pod:  withAffinity(nil, zoneTerm(v1.NodeSelectorOpIn, "us-west-2c"), zoneTerm(v1.NodeSelectorOpNotIn, "us-west-2c")),
+		},
+		{
+			name:    "Negative Test: node selector not satisfied",
+			pod:     withAffinity(map[string]string{"kubernetes.io/os": "windows"}),
+			wantErr: true,
+		},
+		{
+			name:    "Negative Test: affinity not satisfied",
+			pod:     withAffinity(nil, zoneTerm(v1.NodeSelectorOpNotIn, "us-west-2a")),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kubeClientset := fake.NewSimpleClientset(&node, tt.pod)
+			w := common.NewWaiterConfig(1, time.Millisecond)
+			if err := PodsInNamespaceWithSelectorShouldSatisfyNodeAffinity(kubeClientset, w, namespaceName, "app=test-service"); (err != nil) != tt.wantErr {
+				t.Errorf("PodsInNamespaceWithSelectorShouldSatisfyNodeAffinity() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -15,25 +15,31 @@ limitations under the License.
 package structured
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"net/http"
 	"os"
 	"strconv"
 	"strings"
+	"text/tabwriter"
 	"time"
 
 	log "github.com/sirupsen/logrus"
 
 	"github.com/keikoproj/kubedog/internal/util"
 	"github.com/keikoproj/kubedog/pkg/kube/common"
+	"github.com/keikoproj/kubedog/pkg/kube/node"
 	"github.com/keikoproj/kubedog/pkg/kube/pod"
 	"github.com/pkg/errors"
 	vegeta "github.com/tsenart/vegeta/v12/lib"
 	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
 )
@@ -139,7 +145,14 @@ func ClusterRbacIsFound(kubeClientset kubernetes.Interface, resourceType, name s
 	return nil
 }
 
+// ListNodes logs a table of the cluster nodes.
 func ListNodes(kubeClientset kubernetes.Interface) error {
+	_, err := GetNodesTable(kubeClientset)
+	return err
+}
+
+// GetNodesTable logs a table of the cluster nodes and returns it.
+func GetNodesTable(kubeClientset kubernetes.Interface) (string, error) {
 
 	var readyStatus = func(conditions []corev1.NodeCondition) string {
 		var status = false
@@ -159,19 +172,30 @@ func ListNodes(kubeClientset kubernetes.Interface) error {
 		return "NotReady"
 	}
 	// List nodes
-	nodes, _ := GetNodeList(kubeClientset)
-	if nodes != nil {
-		tableFormat := "%-64s%-12s%-24s%-16s"
-		log.Infof(tableFormat, "NAME", "STATUS", "INSTANCEGROUP", "AZ")
-		for _, node := range nodes.Items {
-			log.Infof(tableFormat,
-				node.Name,
-				readyStatus(node.Status.Conditions),
-				node.Labels["node.kubernetes.io/instancegroup"],
-				node.Labels["failure-domain.beta.kubernetes.io/zone"])
-		}
+	nodes, err := GetNodeList(kubeClientset)
+	if err != nil {
+		return "", err
 	}
-	return nil
+	var table bytes.Buffer
+	w := tabwriter.NewWriter(&table, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "NAME\tSTATUS\tVERSION\tINSTANCEGROUP\tZONE\tCPU\tMEMORY\tPODS")
+	for _, n := range nodes.Items {
+		allocatable := n.Status.Allocatable
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			n.Name,
+			readyStatus(n.Status.Conditions),
+			n.Status.NodeInfo.KubeletVersion,
+			n.Labels["node.kubernetes.io/instancegroup"],
+			node.GetZone(n),
+			allocatable.Cpu(),
+			allocatable.Memory(),
+			allocatable.Pods())
+	}
+	w.Flush()
+	for _, row := range strings.Split(strings.TrimSpace(table.String()), "\n") {
+		log.Info(row)
+	}
+	return table.String(), nil
 }
 
 func DaemonSetIsRunning(kubeClientset kubernetes.Interface, expBackoff wait.Backoff, name, namespace string) error {
@@ -306,56 +330,82 @@ func SecretDelete(kubeClientset kubernetes.Interface, name, namespace string) er
 }
 
 func SecretOperationFromEnvironmentVariable(kubeClientset kubernetes.Interface, operation, name, namespace, environmentVariable string) error {
-	var (
-		secretValue string
-		ok          bool
-	)
 	if err := common.ValidateClientset(kubeClientset); err != nil {
 		return err
 	}
-	if operation != common.OperationDelete {
-		secretValue, ok = os.LookupEnv(environmentVariable)
-		if !ok {
-			return errors.Errorf("couldn't lookup environment variable '%s'", environmentVariable)
-		}
-	}
-	switch operation {
-	case common.OperationCreate, common.OperationSubmit:
-		secret := &corev1.Secret{
-			ObjectMeta: metav1.ObjectMeta{
-				Name: name,
-			},
-			Data: map[string][]byte{
-				environmentVariable: []byte(secretValue),
-			},
-		}
-		_, err := kubeClientset.CoreV1().Secrets(namespace).Create(context.TODO(), secret, metav1.CreateOptions{})
-		if kerrors.IsAlreadyExists(err) {
-			return fmt.Errorf("secret '%s' already created", name)
-		}
-		return err
-	case common.OperationUpdate:
-		currentSecret, err := kubeClientset.CoreV1().Secrets(namespace).Get(context.TODO(), name, metav1.GetOptions{})
-		if err != nil {
-			return err
-		}
-		secret := currentSecret.DeepCopy()
-		if len(secret.Data) == 0 {
-			secret.Data = map[string][]byte{}
-		}
-		secret.Data[environmentVariable] = []byte(secretValue)
-		_, err = kubeClientset.CoreV1().Secrets(namespace).Update(context.TODO(), secret, metav1.UpdateOptions{})
-		return err
-	case common.OperationDelete:
+	if operation == common.OperationDelete {
 		err := kubeClientset.CoreV1().Secrets(namespace).Delete(context.TODO(), name, metav1.DeleteOptions{})
 		if kerrors.IsNotFound(err) {
 			log.Infof("secret '%s' was not found", name)
 			return nil
 		}
 		return err
-	default:
-		return fmt.Errorf("unsupported operation: '%s'", operation)
 	}
+
+	secretValue, ok := os.LookupEnv(environmentVariable)
+	if !ok {
+		return errors.Errorf("couldn't lookup environment variable '%s'", environmentVariable)
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+		Data: map[string][]byte{
+			environmentVariable: []byte(secretValue),
+		},
+	}
+	return applySecret(kubeClientset, operation, namespace, secret)
+}
+
+// SecretOperationFromTable creates or updates a secret of the given type with the keys of a data table, where each row
+// has a key, a source (literal|file|env|template) and a value that is the literal, the file name in the templates
+// path or the environment variable name
+func SecretOperationFromTable(kubeClientset kubernetes.Interface, operation, secretType, name, namespace string, rows [][]string, filesPath string, templateArguments interface{}) error {
+	if err := common.ValidateClientset(kubeClientset); err != nil {
+		return err
+	}
+
+	data, err := getSecretDataFromRows(rows, filesPath, templateArguments)
+	if err != nil {
+		return err
+	}
+	if err := validateSecretData(corev1.SecretType(secretType), data); err != nil {
+		return errors.Wrapf(err, "invalid data for secret %s/%s", namespace, name)
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+		Type: corev1.SecretType(secretType),
+		Data: data,
+	}
+	return applySecret(kubeClientset, operation, namespace, secret)
+}
+
+// SecretOperationWithSelfSignedCertificate creates or updates a kubernetes.io/tls secret with a freshly generated
+// self-signed certificate for the comma separated hosts, which can be dns names or ip addresses
+func SecretOperationWithSelfSignedCertificate(kubeClientset kubernetes.Interface, operation, name, namespace, hosts string) error {
+	if err := common.ValidateClientset(kubeClientset); err != nil {
+		return err
+	}
+
+	certificate, key, err := newSelfSignedCertificate(hosts)
+	if err != nil {
+		return err
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+		Type: corev1.SecretTypeTLS,
+		Data: map[string][]byte{
+			corev1.TLSCertKey:       certificate,
+			corev1.TLSPrivateKeyKey: key,
+		},
+	}
+	return applySecret(kubeClientset, operation, namespace, secret)
 }
 
 func IngressAvailable(kubeClientset kubernetes.Interface, w common.WaiterConfig, name, namespace string, port int, path string) error {
@@ -400,14 +450,9 @@ func SendTrafficToIngress(kubeClientset kubernetes.Interface, w common.WaiterCon
 	}
 	log.Infof("sending traffic to %v with rate of %v tps for %v %s...", endpoint, tps, duration, durationUnits)
 	rate := vegeta.Rate{Freq: tps, Per: time.Second}
-	var d time.Duration
-	switch durationUnits {
-	case util.DurationMinutes:
-		d = time.Minute * time.Duration(duration)
-	case util.DurationSeconds:
-		d = time.Second * time.Duration(duration)
-	default:
-		return fmt.Errorf("unsupported duration units: '%s'", durationUnits)
+	d, err := util.GetDuration(duration, durationUnits)
+	if err != nil {
+		return err
 	}
 	targeter := vegeta.NewStaticTargeter(vegeta.Target{
 		Method: "GET",
@@ -461,3 +506,522 @@ func ResourceNotInNamespace(kubeClientset kubernetes.Interface, resourceType, na
 	}
 	return nil
 }
+
+func JobsWithSelectorCountShouldBe(kubeClientset kubernetes.Interface, w common.WaiterConfig, comparison string, expectedCount int, namespace, selector, state string) error {
+	description := fmt.Sprintf("%s jobs with selector '%s'", state, selector)
+	return common.WaitForCount(w, comparison, expectedCount, description, func() (int, error) {
+		jobs, err := GetJobListWithLabelSelector(kubeClientset, namespace, selector)
+		if err != nil {
+			return 0, err
+		}
+
+		var jobsCount int
+		for _, job := range jobs.Items {
+			switch state {
+			case common.StateFound:
+				jobsCount++
+			case common.StateCompleted:
+				if isJobConditionTrue(job, batchv1.JobComplete) {
+					jobsCount++
+				}
+			default:
+				return 0, errors.Errorf("unsupported job state: '%s'", state)
+			}
+		}
+		return jobsCount, nil
+	})
+}
+
+func PersistentVolumeClaimsWithSelectorCountShouldBe(kubeClientset kubernetes.Interface, w common.WaiterConfig, comparison string, expectedCount int, namespace, selector, state string) error {
+	description := fmt.Sprintf("%s persistentvolumeclaims with selector '%s'", state, selector)
+	return common.WaitForCount(w, comparison, expectedCount, description, func() (int, error) {
+		pvcs, err := GetPersistentVolumeClaimListWithLabelSelector(kubeClientset, namespace, selector)
+		if err != nil {
+			return 0, err
+		}
+
+		var pvcsCount int
+		for _, pvc := range pvcs.Items {
+			switch state {
+			case common.StateFound:
+				pvcsCount++
+			case common.StateBound:
+				if pvc.Status.Phase == corev1.ClaimBound {
+					pvcsCount++
+				}
+			default:
+				return 0, errors.Errorf("unsupported persistentvolumeclaim state: '%s'", state)
+			}
+		}
+		return pvcsCount, nil
+	})
+}
+
+func PodDisruptionBudgetStatusShouldBe(kubeClientset kubernetes.Interface, w common.WaiterConfig, name, namespace, comparison string, expectedValue int, statusField string) error {
+	description := fmt.Sprintf("%s of poddisruptionbudget %s/%s", statusField, namespace, name)
+	return common.WaitForCount(w, comparison, expectedValue, description, func() (int, error) {
+		pdb, err := GetPodDisruptionBudget(kubeClientset, name, namespace)
+		if err != nil {
+			return 0, err
+		}
+
+		switch statusField {
+		case "disruptionsAllowed":
+			return int(pdb.Status.DisruptionsAllowed), nil
+		case "currentHealthy":
+			return int(pdb.Status.CurrentHealthy), nil
+		case "desiredHealthy":
+			return int(pdb.Status.DesiredHealthy), nil
+		case "expectedPods":
+			return int(pdb.Status.ExpectedPods), nil
+		default:
+			return 0, errors.Errorf("unsupported poddisruptionbudget status field: '%s'", statusField)
+		}
+	})
+}
+
+func CreateJobFromCronJob(kubeClientset kubernetes.Interface, jobName, cronJobName, namespace string) error {
+	cronJob, err := GetCronJob(kubeClientset, cronJobName, namespace)
+	if err != nil {
+		return err
+	}
+
+	job := newJobFromCronJob(cronJob, jobName)
+	_, err = kubeClientset.BatchV1().Jobs(namespace).Create(context.Background(), job, metav1.CreateOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "failed to create job %s/%s from cronjob %s", namespace, jobName, cronJobName)
+	}
+	log.Infof("created job %s/%s from cronjob %s", namespace, jobName, cronJobName)
+	return nil
+}
+
+func JobShouldFinish(kubeClientset kubernetes.Interface, w common.WaiterConfig, name, namespace, outcome string) error {
+	var expected, unexpected batchv1.JobConditionType
+	switch outcome {
+	case jobOutcomeComplete:
+		expected, unexpected = batchv1.JobComplete, batchv1.JobFailed
+	case jobOutcomeFail:
+		expected, unexpected = batchv1.JobFailed, batchv1.JobComplete
+	default:
+		return errors.Errorf("unsupported job outcome: '%s'", outcome)
+	}
+
+	var counter int
+	for {
+		if counter >= w.GetTries() {
+			return errors.Errorf("waiter timed out waiting for job %s/%s to %s", namespace, name, outcome)
+		}
+
+		job, err := GetJob(kubeClientset, name, namespace)
+		if err != nil {
+			return err
+		}
+
+		if isJobConditionTrue(*job, expected) {
+			log.Infof("job %s/%s finished with condition %s: %d succeeded, %d failed", namespace, name, expected, job.Status.Succeeded, job.Status.Failed)
+			return nil
+		}
+		if isJobConditionTrue(*job, unexpected) {
+			return errors.Errorf("job %s/%s finished with condition %s, expected it to %s", namespace, name, unexpected, outcome)
+		}
+
+		log.Infof("job %s/%s has %d active, %d succeeded and %d failed pods, waiting for it to %s", namespace, name, job.Status.Active, job.Status.Succeeded, job.Status.Failed, outcome)
+		counter++
+		time.Sleep(w.GetInterval())
+	}
+}
+
+func JobShouldHavePodCount(kubeClientset kubernetes.Interface, w common.WaiterConfig, name, namespace, comparison string, expectedCount int, podStatus string) error {
+	description := fmt.Sprintf("%s pods of job %s/%s", podStatus, namespace, name)
+	return common.WaitForCount(w, comparison, expectedCount, description, func() (int, error) {
+		job, err := GetJob(kubeClientset, name, namespace)
+		if err != nil {
+			return 0, err
+		}
+
+		switch podStatus {
+		case jobPodsActive:
+			return int(job.Status.Active), nil
+		case jobPodsSucceeded:
+			return int(job.Status.Succeeded), nil
+		case jobPodsFailed:
+			return int(job.Status.Failed), nil
+		default:
+			return 0, errors.Errorf("unsupported job pod status: '%s'", podStatus)
+		}
+	})
+}
+
+func JobShouldExceedBackoffLimit(kubeClientset kubernetes.Interface, w common.WaiterConfig, name, namespace string) error {
+	if err := JobShouldFinish(kubeClientset, w, name, namespace, jobOutcomeFail); err != nil {
+		return err
+	}
+
+	job, err := GetJob(kubeClientset, name, namespace)
+	if err != nil {
+		return err
+	}
+
+	reason := getJobConditionReason(*job, batchv1.JobFailed)
+	if reason != jobReasonBackoffLimitExceeded {
+		return errors.Errorf("job %s/%s failed with reason '%s', expected '%s'", namespace, name, reason, jobReasonBackoffLimitExceeded)
+	}
+
+	backoffLimit := int32(6)
+	if job.Spec.BackoffLimit != nil {
+		backoffLimit = *job.Spec.BackoffLimit
+	}
+	// parallel pods can fail together, so more than one pod past the limit may have failed
+	if job.Status.Failed <= backoffLimit {
+		return errors.Errorf("job %s/%s has %d failed pods, expected more than its backoff limit %d", namespace, name, job.Status.Failed, backoffLimit)
+	}
+	log.Infof("job %s/%s exceeded its backoff limit %d after %d failed pods", namespace, name, backoffLimit, job.Status.Failed)
+	return nil
+}
+
+func CronJobOperation(kubeClientset kubernetes.Interface, operation, name, namespace string) error {
+	var suspend bool
+	switch operation {
+	case common.OperationSuspend:
+		suspend = true
+	case common.OperationResume:
+		suspend = false
+	default:
+		return errors.Errorf("unsupported cronjob operation: '%s'", operation)
+	}
+
+	_, err := util.RetryOnError(&util.DefaultRetry, util.IsRetriable, func() (interface{}, error) {
+		cronJob, err := GetCronJob(kubeClientset, name, namespace)
+		if err != nil {
+			return nil, err
+		}
+		cronJob.Spec.Suspend = &suspend
+		return kubeClientset.BatchV1().CronJobs(namespace).Update(context.Background(), cronJob, metav1.UpdateOptions{})
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to %s cronjob %s/%s", operation, namespace, name)
+	}
+	log.Infof("cronjob %s/%s suspend set to %t", namespace, name, suspend)
+	return nil
+}
+
+func HorizontalPodAutoscalerReplicasShouldBe(kubeClientset kubernetes.Interface, w common.WaiterConfig, name, namespace, comparison string, expectedValue int, replicasField string) error {
+	description := fmt.Sprintf("%s of horizontalpodautoscaler %s/%s", replicasField, namespace, name)
+	return common.WaitForCount(w, comparison, expectedValue, description, func() (int, error) {
+		hpa, err := GetHorizontalPodAutoscaler(kubeClientset, name, namespace)
+		if err != nil {
+			return 0, err
+		}
+
+		switch replicasField {
+		case hpaCurrentReplicas:
+			return int(hpa.Status.CurrentReplicas), nil
+		case hpaDesiredReplicas:
+			return int(hpa.Status.DesiredReplicas), nil
+		default:
+			return 0, errors.Errorf("unsupported horizontalpodautoscaler replicas field: '%s'", replicasField)
+		}
+	})
+}
+
+// HorizontalPodAutoscalerShouldScale waits for the current replicas of the hpa to go above its minReplicas when
+// scaling up, or to return to its minReplicas when scaling down
+func HorizontalPodAutoscalerShouldScale(kubeClientset kubernetes.Interface, w common.WaiterConfig, name, namespace, direction string, timeout time.Duration) error {
+	if direction != hpaScaleUp && direction != hpaScaleDown {
+		return errors.Errorf("unsupported horizontalpodautoscaler scale direction: '%s'", direction)
+	}
+
+	hpa, err := GetHorizontalPodAutoscaler(kubeClientset, name, namespace)
+	if err != nil {
+		return err
+	}
+	deadline := time.Now().Add(timeout)
+	for {
+		minReplicas := getHorizontalPodAutoscalerMinReplicas(*hpa)
+		current, desired := hpa.Status.CurrentReplicas, hpa.Status.DesiredReplicas
+		scaled := current > minReplicas
+		if direction == hpaScaleDown {
+			scaled = current <= minReplicas
+		}
+
+		if scaled {
+			log.Infof("horizontalpodautoscaler %s/%s scaled %s to %d replicas (min %d, max %d)", namespace, name, direction, current, minReplicas, hpa.Spec.MaxReplicas)
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return errors.Errorf("horizontalpodautoscaler %s/%s did not scale %s within %v: %d current and %d desired replicas (min %d, max %d)", namespace, name, direction, timeout, current, desired, minReplicas, hpa.Spec.MaxReplicas)
+		}
+		log.Infof("waiting for horizontalpodautoscaler %s/%s to scale %s: %d current and %d desired replicas (min %d, max %d)", namespace, name, direction, current, desired, minReplicas, hpa.Spec.MaxReplicas)
+		time.Sleep(w.GetInterval())
+
+		hpa, err = GetHorizontalPodAutoscaler(kubeClientset, name, namespace)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func HorizontalPodAutoscalerConditionShouldBe(kubeClientset kubernetes.Interface, w common.WaiterConfig, name, namespace, conditionType, expectedStatus string) error {
+	var counter int
+	for {
+		if counter >= w.GetTries() {
+			return errors.Errorf("waiter timed out waiting for horizontalpodautoscaler %s/%s condition %s to be %s", namespace, name, conditionType, expectedStatus)
+		}
+
+		hpa, err := GetHorizontalPodAutoscaler(kubeClientset, name, namespace)
+		if err != nil {
+			return err
+		}
+
+		status := getHorizontalPodAutoscalerConditionStatus(*hpa, autoscalingv2.HorizontalPodAutoscalerConditionType(conditionType))
+		if string(status) == expectedStatus {
+			log.Infof("horizontalpodautoscaler %s/%s condition %s is %s", namespace, name, conditionType, status)
+			return nil
+		}
+
+		log.Infof("horizontalpodautoscaler %s/%s condition %s is %s, waiting for %s", namespace, name, conditionType, status, expectedStatus)
+		counter++
+		time.Sleep(w.GetInterval())
+	}
+}
+
+func ServiceShouldHaveReadyEndpoints(kubeClientset kubernetes.Interface, w common.WaiterConfig, name, namespace, comparison string, expectedCount int) error {
+	description := fmt.Sprintf("ready endpoints of service %s/%s", namespace, name)
+	return common.WaitForCount(w, comparison, expectedCount, description, func() (int, error) {
+		endpointSlices, err := GetEndpointSliceListForService(kubeClientset, name, namespace)
+		if err != nil {
+			return 0, err
+		}
+
+		addresses := getReadyEndpointAddresses(*endpointSlices)
+		log.Infof("service %s/%s has ready endpoints %v", namespace, name, addresses)
+		return len(addresses), nil
+	})
+}
+
+func ServiceShouldSelectPodsWithSelector(kubeClientset kubernetes.Interface, name, namespace, selector string) error {
+	service, err := GetService(kubeClientset, name, namespace)
+	if err != nil {
+		return err
+	}
+	if len(service.Spec.Selector) == 0 {
+		return errors.Errorf("service %s/%s does not have a selector", namespace, name)
+	}
+
+	pods, err := pod.GetPodListWithLabelSelector(kubeClientset, namespace, selector)
+	if err != nil {
+		return err
+	}
+	if len(pods.Items) == 0 {
+		return errors.Errorf("no pods found in namespace %s with selector '%s'", namespace, selector)
+	}
+
+	serviceSelector := labels.SelectorFromSet(service.Spec.Selector)
+	var unmatched []string
+	for _, p := range pods.Items {
+		if !serviceSelector.Matches(labels.Set(p.Labels)) {
+			unmatched = append(unmatched, p.Name)
+		}
+	}
+	if len(unmatched) > 0 {
+		return errors.Errorf("service %s/%s selector '%s' does not match pods %v", namespace, name, serviceSelector, unmatched)
+	}
+	log.Infof("service %s/%s selector '%s' matches all %d pods with selector '%s'", namespace, name, serviceSelector, len(pods.Items), selector)
+	return nil
+}
+
+func ServiceShouldHaveLoadBalancerIngress(kubeClientset kubernetes.Interface, w common.WaiterConfig, name, namespace string) error {
+	var counter int
+	for {
+		if counter >= w.GetTries() {
+			return errors.Errorf("waiter timed out waiting for service %s/%s to have a load balancer ingress", namespace, name)
+		}
+
+		service, err := GetService(kubeClientset, name, namespace)
+		if err != nil {
+			return err
+		}
+		if service.Spec.Type != corev1.ServiceTypeLoadBalancer {
+			return errors.Errorf("service %s/%s is of type %s, expected %s", namespace, name, service.Spec.Type, corev1.ServiceTypeLoadBalancer)
+		}
+
+		for _, ingress := range service.Status.LoadBalancer.Ingress {
+			if ingress.Hostname != "" || ingress.IP != "" {
+				log.Infof("service %s/%s has load balancer ingress hostname '%s' ip '%s'", namespace, name, ingress.Hostname, ingress.IP)
+				return nil
+			}
+		}
+
+		log.Infof("service %s/%s does not have a load balancer ingress yet", namespace, name)
+		counter++
+		time.Sleep(w.GetInterval())
+	}
+}
+
+func ServiceShouldBeOfType(kubeClientset kubernetes.Interface, name, namespace, serviceType string) error {
+	service, err := GetService(kubeClientset, name, namespace)
+	if err != nil {
+		return err
+	}
+	if string(service.Spec.Type) != serviceType {
+		return errors.Errorf("service %s/%s is of type %s, expected %s", namespace, name, service.Spec.Type, serviceType)
+	}
+	return nil
+}
+
+func ServiceShouldHavePort(kubeClientset kubernetes.Interface, name, namespace, protocol string, port int32, targetPort string) error {
+	service, err := GetService(kubeClientset, name, namespace)
+	if err != nil {
+		return err
+	}
+
+	servicePort, err := getServicePort(*service, protocol, port)
+	if err != nil {
+		return err
+	}
+	if servicePort.TargetPort.String() != targetPort {
+		return errors.Errorf("service %s/%s %s port %d has target port %s, expected %s", namespace, name, protocol, port, servicePort.TargetPort.String(), targetPort)
+	}
+	return nil
+}
+
+func ServiceShouldHaveNodePort(kubeClientset kubernetes.Interface, name, namespace string, nodePort int32, protocol string, port int32) error {
+	service, err := GetService(kubeClientset, name, namespace)
+	if err != nil {
+		return err
+	}
+
+	servicePort, err := getServicePort(*service, protocol, port)
+	if err != nil {
+		return err
+	}
+	if servicePort.NodePort != nodePort {
+		return errors.Errorf("service %s/%s %s port %d has node port %d, expected %d", namespace, name, protocol, port, servicePort.NodePort, nodePort)
+	}
+	return nil
+}
+
+func DataKeyShouldMatch(kubeClientset kubernetes.Interface, kind, name, namespace, key, matchType, expected string) error {
+	actual, sensitive, err := getDataValue(kubeClientset, kind, name, namespace, key)
+	if err != nil {
+		return err
+	}
+
+	matched, err := matchDataValue(matchType, actual, expected)
+	if err != nil {
+		return err
+	}
+	if !matched {
+		return errors.Errorf("%s %s/%s key '%s' value %s does not %s %s", kind, namespace, name, key, describeDataValue(actual, sensitive), matchType, describeDataValue(expected, sensitive))
+	}
+	log.Infof("%s %s/%s key '%s' value does %s %s", kind, namespace, name, key, matchType, describeDataValue(expected, sensitive))
+	return nil
+}
+
+// DataKeyShouldMatchEnvironmentVariable matches the value against the one of an environment variable, so the expected
+// value of a secret does not appear in the step text
+func DataKeyShouldMatchEnvironmentVariable(kubeClientset kubernetes.Interface, kind, name, namespace, key, matchType, environmentVariable string) error {
+	expected, ok := os.LookupEnv(environmentVariable)
+	if !ok {
+		return errors.Errorf("couldn't lookup environment variable '%s'", environmentVariable)
+	}
+	return DataKeyShouldMatch(kubeClientset, kind, name, namespace, key, matchType, expected)
+}
+
+func DataKeyShouldMatchFile(kubeClientset kubernetes.Interface, kind, name, namespace, key, filePath string, templateArguments interface{}) error {
+	actual, sensitive, err := getDataValue(kubeClientset, kind, name, namespace, key)
+	if err != nil {
+		return err
+	}
+
+	expected, err := renderFile(filePath, templateArguments)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read file '%s'", filePath)
+	}
+	if actual != expected {
+		return errors.Errorf("%s %s/%s key '%s' value %s does not match file '%s'", kind, namespace, name, key, describeDataValue(actual, sensitive), filePath)
+	}
+	log.Infof("%s %s/%s key '%s' value matches file '%s'", kind, namespace, name, key, filePath)
+	return nil
+}
+
+func DataKeyAtPathShouldMatch(kubeClientset kubernetes.Interface, kind, name, namespace, key, format, path, matchType, expected string) error {
+	value, sensitive, err := getDataValue(kubeClientset, kind, name, namespace, key)
+	if err != nil {
+		return err
+	}
+
+	actual, err := getDataValueAtPath(value, format, path)
+	if err != nil {
+		return errors.Wrapf(err, "%s %s/%s key '%s'", kind, namespace, name, key)
+	}
+
+	matched, err := matchDataValue(matchType, actual, expected)
+	if err != nil {
+		return err
+	}
+	if !matched {
+		return errors.Errorf("%s %s/%s key '%s' %s path '%s' value %s does not %s %s", kind, namespace, name, key, format, path, describeDataValue(actual, sensitive), matchType, describeDataValue(expected, sensitive))
+	}
+	log.Infof("%s %s/%s key '%s' %s path '%s' value does %s %s", kind, namespace, name, key, format, path, matchType, describeDataValue(expected, sensitive))
+	return nil
+}
+
+func CreateNamespace(kubeClientset kubernetes.Interface, name string, labels map[string]string) error {
+	if err := common.ValidateClientset(kubeClientset); err != nil {
+		return err
+	}
+
+	namespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: labels,
+		},
+	}
+	_, err := util.RetryOnError(&util.DefaultRetry, util.IsRetriable, func() (interface{}, error) {
+		return kubeClientset.CoreV1().Namespaces().Create(context.Background(), namespace, metav1.CreateOptions{})
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to create namespace %s", name)
+	}
+	log.Infof("created namespace %s", name)
+	return nil
+}
+
+// DeleteNamespace deletes the namespace and waits for it to finish terminating
+func DeleteNamespace(kubeClientset kubernetes.Interface, w common.WaiterConfig, name string) error {
+	if err := common.ValidateClientset(kubeClientset); err != nil {
+		return err
+	}
+
+	err := kubeClientset.CoreV1().Namespaces().Delete(context.Background(), name, metav1.DeleteOptions{})
+	if kerrors.IsNotFound(err) {
+		log.Infof("namespace %s was not found", name)
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to delete namespace %s", name)
+	}
+
+	var counter int
+	for {
+		if counter >= w.GetTries() {
+			return errors.Errorf("waiter timed out waiting for namespace %s to be deleted", name)
+		}
+
+		namespace, err := kubeClientset.CoreV1().Namespaces().Get(context.Background(), name, metav1.GetOptions{})
+		if kerrors.IsNotFound(err) {
+			log.Infof("namespace %s was deleted", name)
+			return nil
+		}
+		if err != nil && !util.IsRetriable(err) {
+			return errors.Wrapf(err, "failed to get namespace %s", name)
+		}
+
+		if namespace != nil {
+			log.Infof("namespace %s is %s, waiting for it to be deleted", name, namespace.Status.Phase)
+		}
+		counter++
+		time.Sleep(w.GetInterval())
+	}
+}
@@ -16,7 +16,13 @@ package structured
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/keikoproj/kubedog/internal/util"
@@ -24,10 +30,56 @@ import (
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/cert"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	jobOutcomeComplete = "complete"
+	jobOutcomeFail     = "fail"
+
+	jobPodsActive    = "active"
+	jobPodsSucceeded = "succeeded"
+	jobPodsFailed    = "failed"
+
+	jobReasonBackoffLimitExceeded = "BackoffLimitExceeded"
+	cronJobInstantiateAnnotation  = "cronjob.kubernetes.io/instantiate"
+
+	hpaCurrentReplicas = "currentReplicas"
+	hpaDesiredReplicas = "desiredReplicas"
+
+	hpaScaleUp   = "up"
+	hpaScaleDown = "down"
+
+	dataKindConfigMap = "configmap"
+	dataKindSecret    = "secret"
+
+	dataMatchEqual   = "equal"
+	dataMatchContain = "contain"
+	dataMatchRegex   = "match regex"
+
+	dataFormatYAML = "yaml"
+	dataFormatJSON = "json"
+
+	redactedValue = "<redacted>"
+
+	secretSourceLiteral  = "literal"
+	secretSourceFile     = "file"
+	secretSourceEnv      = "env"
+	secretSourceTemplate = "template"
+
+	secretTableHeaderKey    = "key"
+	secretTableHeaderSource = "source"
+	secretTableHeaderValue  = "value"
 )
 
 func GetNodeList(kubeClientset kubernetes.Interface) (*corev1.NodeList, error) {
@@ -124,6 +176,48 @@ func GetStatefulSetList(kubeClientset kubernetes.Interface, namespace string) (*
 	return sts.(*appsv1.StatefulSetList), nil
 }
 
+func GetPersistentVolumeClaimListWithLabelSelector(kubeClientset kubernetes.Interface, namespace, labelSelector string) (*corev1.PersistentVolumeClaimList, error) {
+	if err := common.ValidateClientset(kubeClientset); err != nil {
+		return nil, err
+	}
+
+	pvcs, err := util.RetryOnError(&util.DefaultRetry, util.IsRetriable, func() (interface{}, error) {
+		return kubeClientset.CoreV1().PersistentVolumeClaims(namespace).List(context.Background(), metav1.ListOptions{LabelSelector: labelSelector})
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list persistentvolumeclaims")
+	}
+	return pvcs.(*corev1.PersistentVolumeClaimList), nil
+}
+
+func GetJobListWithLabelSelector(kubeClientset kubernetes.Interface, namespace, labelSelector string) (*batchv1.JobList, error) {
+	if err := common.ValidateClientset(kubeClientset); err != nil {
+		return nil, err
+	}
+
+	jobs, err := util.RetryOnError(&util.DefaultRetry, util.IsRetriable, func() (interface{}, error) {
+		return kubeClientset.BatchV1().Jobs(namespace).List(context.Background(), metav1.ListOptions{LabelSelector: labelSelector})
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list jobs")
+	}
+	return jobs.(*batchv1.JobList), nil
+}
+
+func GetPodDisruptionBudget(kubeClientset kubernetes.Interface, name, namespace string) (*policyv1.PodDisruptionBudget, error) {
+	if err := common.ValidateClientset(kubeClientset); err != nil {
+		return nil, err
+	}
+
+	pdb, err := util.RetryOnError(&util.DefaultRetry, util.IsRetriable, func() (interface{}, error) {
+		return kubeClientset.PolicyV1().PodDisruptionBudgets(namespace).Get(context.Background(), name, metav1.GetOptions{})
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get poddisruptionbudget")
+	}
+	return pdb.(*policyv1.PodDisruptionBudget), nil
+}
+
 func GetPersistentVolumeList(kubeClientset kubernetes.Interface) (*corev1.PersistentVolumeList, error) {
 	if err := common.ValidateClientset(kubeClientset); err != nil {
 		return nil, err
@@ -214,3 +308,442 @@ func isNodeReady(n corev1.Node) bool {
 	}
 	return false
 }
+
+func isJobConditionTrue(job batchv1.Job, conditionType batchv1.JobConditionType) bool {
+	for _, condition := range job.Status.Conditions {
+		if condition.Type == conditionType {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+func GetJob(kubeClientset kubernetes.Interface, name, namespace string) (*batchv1.Job, error) {
+	if err := common.ValidateClientset(kubeClientset); err != nil {
+		return nil, err
+	}
+
+	job, err := util.RetryOnError(&util.DefaultRetry, util.IsRetriable, func() (interface{}, error) {
+		return kubeClientset.BatchV1().Jobs(namespace).Get(context.Background(), name, metav1.GetOptions{})
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get job")
+	}
+	return job.(*batchv1.Job), nil
+}
+
+func GetCronJob(kubeClientset kubernetes.Interface, name, namespace string) (*batchv1.CronJob, error) {
+	if err := common.ValidateClientset(kubeClientset); err != nil {
+		return nil, err
+	}
+
+	cronJob, err := util.RetryOnError(&util.DefaultRetry, util.IsRetriable, func() (interface{}, error) {
+		return kubeClientset.BatchV1().CronJobs(namespace).Get(context.Background(), name, metav1.GetOptions{})
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get cronjob")
+	}
+	return cronJob.(*batchv1.CronJob), nil
+}
+
+// newJobFromCronJob builds a job from the cronjob template the same way 'kubectl create job --from' does
+func newJobFromCronJob(cronJob *batchv1.CronJob, jobName string) *batchv1.Job {
+	annotations := map[string]string{cronJobInstantiateAnnotation: "manual"}
+	for k, v := range cronJob.Spec.JobTemplate.Annotations {
+		annotations[k] = v
+	}
+
+	return &batchv1.Job{
+		TypeMeta: metav1.TypeMeta{APIVersion: batchv1.SchemeGroupVersion.String(), Kind: "Job"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        jobName,
+			Namespace:   cronJob.Namespace,
+			Annotations: annotations,
+			Labels:      cronJob.Spec.JobTemplate.Labels,
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(cronJob, batchv1.SchemeGroupVersion.WithKind("CronJob")),
+			},
+		},
+		Spec: cronJob.Spec.JobTemplate.Spec,
+	}
+}
+
+func getJobConditionReason(job batchv1.Job, conditionType batchv1.JobConditionType) string {
+	for _, condition := range job.Status.Conditions {
+		if condition.Type == conditionType {
+			return condition.Reason
+		}
+	}
+	return ""
+}
+
+func GetHorizontalPodAutoscaler(kubeClientset kubernetes.Interface, name, namespace string) (*autoscalingv2.HorizontalPodAutoscaler, error) {
+	if err := common.ValidateClientset(kubeClientset); err != nil {
+		return nil, err
+	}
+
+	hpa, err := util.RetryOnError(&util.DefaultRetry, util.IsRetriable, func() (interface{}, error) {
+		return kubeClientset.AutoscalingV2().HorizontalPodAutoscalers(namespace).Get(context.Background(), name, metav1.GetOptions{})
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get horizontalpodautoscaler")
+	}
+	return hpa.(*autoscalingv2.HorizontalPodAutoscaler), nil
+}
+
+func getHorizontalPodAutoscalerMinReplicas(hpa autoscalingv2.HorizontalPodAutoscaler) int32 {
+	if hpa.Spec.MinReplicas != nil {
+		return *hpa.Spec.MinReplicas
+	}
+	return 1
+}
+
+func getHorizontalPodAutoscalerConditionStatus(hpa autoscalingv2.HorizontalPodAutoscaler, conditionType autoscalingv2.HorizontalPodAutoscalerConditionType) corev1.ConditionStatus {
+	for _, condition := range hpa.Status.Conditions {
+		if condition.Type == conditionType {
+			return condition.Status
+		}
+	}
+	return corev1.ConditionUnknown
+}
+
+func GetService(kubeClientset kubernetes.Interface, name, namespace string) (*corev1.Service, error) {
+	if err := common.ValidateClientset(kubeClientset); err != nil {
+		return nil, err
+	}
+
+	service, err := util.RetryOnError(&util.DefaultRetry, util.IsRetriable, func() (interface{}, error) {
+		return kubeClientset.CoreV1().Services(namespace).Get(context.Background(), name, metav1.GetOptions{})
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get service")
+	}
+	return service.(*corev1.Service), nil
+}
+
+func GetEndpointSliceListForService(kubeClientset kubernetes.Interface, name, namespace string) (*discoveryv1.EndpointSliceList, error) {
+	if err := common.ValidateClientset(kubeClientset); err != nil {
+		return nil, err
+	}
+
+	endpointSlices, err := util.RetryOnError(&util.DefaultRetry, util.IsRetriable, func() (interface{}, error) {
+		return kubeClientset.DiscoveryV1().EndpointSlices(namespace).List(context.Background(), metav1.ListOptions{
+			LabelSelector: discoveryv1.LabelServiceName + "=" + name,
+		})
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list endpointslices")
+	}
+	return endpointSlices.(*discoveryv1.EndpointSliceList), nil
+}
+
+// getReadyEndpointAddresses returns the unique addresses of ready endpoints across the slices of a service,
+// since an endpoint may be repeated in more than one slice while they are being updated
+func getReadyEndpointAddresses(endpointSlices discoveryv1.EndpointSliceList) []string {
+	var (
+		seen      = map[string]bool{}
+		addresses []string
+	)
+	for _, slice := range endpointSlices.Items {
+		for _, endpoint := range slice.Endpoints {
+			// a nil ready condition should be interpreted as ready
+			if endpoint.Conditions.Ready != nil && !*endpoint.Conditions.Ready {
+				continue
+			}
+			if len(endpoint.Addresses) == 0 || seen[endpoint.Addresses[0]] {
+				continue
+			}
+			seen[endpoint.Addresses[0]] = true
+			addresses = append(addresses, endpoint.Addresses[0])
+		}
+	}
+	return addresses
+}
+
+func getServicePort(service corev1.Service, protocol string, port int32) (*corev1.ServicePort, error) {
+	for i, servicePort := range service.Spec.Ports {
+		if servicePort.Port == port && string(servicePort.Protocol) == protocol {
+			return &service.Spec.Ports[i], nil
+		}
+	}
+	return nil, errors.Errorf("service %s/%s does not have %s port %d", service.Namespace, service.Name, protocol, port)
+}
+
+func GetSecret(kubeClientset kubernetes.Interface, name, namespace string) (*corev1.Secret, error) {
+	if err := common.ValidateClientset(kubeClientset); err != nil {
+		return nil, err
+	}
+
+	secret, err := util.RetryOnError(&util.DefaultRetry, util.IsRetriable, func() (interface{}, error) {
+		return kubeClientset.CoreV1().Secrets(namespace).Get(context.Background(), name, metav1.GetOptions{})
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get secret")
+	}
+	return secret.(*corev1.Secret), nil
+}
+
+// getDataValue returns the value of a key in the data or binaryData of a configmap, or the decoded data of a secret,
+// and whether the value is sensitive and should be redacted
+func getDataValue(kubeClientset kubernetes.Interface, kind, name, namespace, key string) (string, bool, error) {
+	switch kind {
+	case dataKindConfigMap:
+		configMap, err := GetConfigMap(kubeClientset, name, namespace)
+		if err != nil {
+			return "", false, err
+		}
+		if value, ok := configMap.Data[key]; ok {
+			return value, false, nil
+		}
+		if value, ok := configMap.BinaryData[key]; ok {
+			return string(value), false, nil
+		}
+		return "", false, errors.Errorf("configmap %s/%s does not have key '%s'", namespace, name, key)
+	case dataKindSecret:
+		secret, err := GetSecret(kubeClientset, name, namespace)
+		if err != nil {
+			return "", true, err
+		}
+		if value, ok := secret.Data[key]; ok {
+			return string(value), true, nil
+		}
+		return "", true, errors.Errorf("secret %s/%s does not have key '%s'", namespace, name, key)
+	default:
+		return "", false, errors.Errorf("unsupported data kind: '%s'", kind)
+	}
+}
+
+func matchDataValue(matchType, actual, expected string) (bool, error) {
+	switch matchType {
+	case dataMatchEqual:
+		return actual == expected, nil
+	case dataMatchContain:
+		return strings.Contains(actual, expected), nil
+	case dataMatchRegex:
+		re, err := regexp.Compile(expected)
+		if err != nil {
+			// the expression is not included in the error as it may hold sensitive data
+			return false, errors.New("failed to compile regex")
+		}
+		return re.MatchString(actual), nil
+	default:
+		return false, errors.Errorf("unsupported data match type: '%s'", matchType)
+	}
+}
+
+func describeDataValue(value string, sensitive bool) string {
+	if sensitive {
+		return redactedValue
+	}
+	return fmt.Sprintf("'%s'", value)
+}
+
+// getDataValueAtPath parses the value as yaml or json and returns the field at the dot separated path, where list
+// elements are referenced as 'field[index]'. Objects and lists are returned as json.
+func getDataValueAtPath(value, format, path string) (string, error) {
+	var data any
+	switch format {
+	case dataFormatYAML:
+		if err := yaml.Unmarshal([]byte(value), &data); err != nil {
+			return "", errors.New("failed to parse value as yaml")
+		}
+	case dataFormatJSON:
+		if err := json.Unmarshal([]byte(value), &data); err != nil {
+			return "", errors.New("failed to parse value as json")
+		}
+	default:
+		return "", errors.Errorf("unsupported data format: '%s'", format)
+	}
+
+	field, err := util.ExtractField(data, util.DeleteEmpty(strings.Split(path, ".")))
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get path '%s'", path)
+	}
+
+	switch field := field.(type) {
+	case string:
+		return field, nil
+	case nil:
+		return "", errors.Errorf("path '%s' is null", path)
+	case map[string]any, []any:
+		fieldJSON, err := json.Marshal(field)
+		if err != nil {
+			return "", err
+		}
+		return string(fieldJSON), nil
+	default:
+		return fmt.Sprint(field), nil
+	}
+}
+
+func renderFile(filePath string, templateArguments interface{}) (string, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return "", err
+	}
+	rendered, err := common.RenderTemplate(filePath, string(data), templateArguments)
+	if err != nil {
+		return "", err
+	}
+	return string(rendered), nil
+}
+
+// applySecret creates the secret, or merges its data into the existing secret when updating
+func applySecret(kubeClientset kubernetes.Interface, operation, namespace string, secret *corev1.Secret) error {
+	switch operation {
+	case common.OperationCreate, common.OperationSubmit:
+		_, err := kubeClientset.CoreV1().Secrets(namespace).Create(context.TODO(), secret, metav1.CreateOptions{})
+		if kerrors.IsAlreadyExists(err) {
+			return fmt.Errorf("secret '%s' already created", secret.Name)
+		}
+		return err
+	case common.OperationUpdate:
+		return updateSecret(kubeClientset, namespace, secret)
+	case common.OperationUpsert:
+		_, err := kubeClientset.CoreV1().Secrets(namespace).Create(context.TODO(), secret, metav1.CreateOptions{})
+		if kerrors.IsAlreadyExists(err) {
+			return updateSecret(kubeClientset, namespace, secret)
+		}
+		return err
+	default:
+		return fmt.Errorf("unsupported operation: '%s'", operation)
+	}
+}
+
+func updateSecret(kubeClientset kubernetes.Interface, namespace string, secret *corev1.Secret) error {
+	currentSecret, err := kubeClientset.CoreV1().Secrets(namespace).Get(context.TODO(), secret.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if secret.Type != "" && currentSecret.Type != secret.Type {
+		return errors.Errorf("secret '%s' is of type %s and its type cannot be changed to %s", secret.Name, currentSecret.Type, secret.Type)
+	}
+
+	updatedSecret := currentSecret.DeepCopy()
+	if len(updatedSecret.Data) == 0 {
+		updatedSecret.Data = map[string][]byte{}
+	}
+	for key, value := range secret.Data {
+		updatedSecret.Data[key] = value
+	}
+	_, err = kubeClientset.CoreV1().Secrets(namespace).Update(context.TODO(), updatedSecret, metav1.UpdateOptions{})
+	return err
+}
+
+func getSecretDataFromRows(rows [][]string, filesPath string, templateArguments interface{}) (map[string][]byte, error) {
+	if len(rows) < 2 {
+		return nil, errors.New("secret table should have a header and at least one row")
+	}
+
+	columns := map[string]int{}
+	for i, header := range rows[0] {
+		columns[strings.ToLower(strings.TrimSpace(header))] = i
+	}
+	for _, header := range []string{secretTableHeaderKey, secretTableHeaderSource, secretTableHeaderValue} {
+		if _, ok := columns[header]; !ok {
+			return nil, errors.Errorf("secret table is missing column '%s'", header)
+		}
+	}
+
+	data := map[string][]byte{}
+	for i, row := range rows[1:] {
+		if len(row) != len(rows[0]) {
+			return nil, errors.Errorf("secret table row %d has %d cells, expected %d", i+1, len(row), len(rows[0]))
+		}
+		key := strings.TrimSpace(row[columns[secretTableHeaderKey]])
+		source := strings.TrimSpace(row[columns[secretTableHeaderSource]])
+		value := row[columns[secretTableHeaderValue]]
+		if key == "" {
+			return nil, errors.Errorf("secret table row %d has an empty key", i+1)
+		}
+		if _, ok := data[key]; ok {
+			return nil, errors.Errorf("secret table has duplicated key '%s'", key)
+		}
+
+		switch source {
+		case secretSourceLiteral:
+			data[key] = []byte(value)
+		case secretSourceFile:
+			fileData, err := os.ReadFile(filepath.Join(filesPath, value))
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to read file for secret key '%s'", key)
+			}
+			data[key] = fileData
+		case secretSourceTemplate:
+			rendered, err := renderFile(filepath.Join(filesPath, value), templateArguments)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to render template for secret key '%s'", key)
+			}
+			data[key] = []byte(rendered)
+		case secretSourceEnv:
+			envValue, ok := os.LookupEnv(value)
+			if !ok {
+				return nil, errors.Errorf("couldn't lookup environment variable '%s' for secret key '%s'", value, key)
+			}
+			data[key] = []byte(envValue)
+		default:
+			return nil, errors.Errorf("unsupported source '%s' for secret key '%s'", source, key)
+		}
+	}
+	return data, nil
+}
+
+// validateSecretData checks the keys required by the secret type, which the api server would reject otherwise
+func validateSecretData(secretType corev1.SecretType, data map[string][]byte) error {
+	var requiredKeys []string
+	switch secretType {
+	case corev1.SecretTypeOpaque:
+	case corev1.SecretTypeTLS:
+		requiredKeys = []string{corev1.TLSCertKey, corev1.TLSPrivateKeyKey}
+	case corev1.SecretTypeDockerConfigJson:
+		requiredKeys = []string{corev1.DockerConfigJsonKey}
+		if value, ok := data[corev1.DockerConfigJsonKey]; ok && !json.Valid(value) {
+			return errors.Errorf("key '%s' is not valid json", corev1.DockerConfigJsonKey)
+		}
+	case corev1.SecretTypeBasicAuth:
+		_, hasUsername := data[corev1.BasicAuthUsernameKey]
+		_, hasPassword := data[corev1.BasicAuthPasswordKey]
+		if !hasUsername && !hasPassword {
+			return errors.Errorf("one of the keys '%s' or '%s' is required", corev1.BasicAuthUsernameKey, corev1.BasicAuthPasswordKey)
+		}
+	default:
+		return errors.Errorf("unsupported secret type: '%s'", secretType)
+	}
+
+	for _, key := range requiredKeys {
+		if _, ok := data[key]; !ok {
+			return errors.Errorf("key '%s' is required for secret type %s", key, secretType)
+		}
+	}
+	return nil
+}
+
+func newSelfSignedCertificate(hosts string) ([]byte, []byte, error) {
+	var (
+		dnsNames    []string
+		ipAddresses []net.IP
+	)
+	for _, host := range util.DeleteEmpty(strings.Split(hosts, ",")) {
+		host = strings.TrimSpace(host)
+		if ip := net.ParseIP(host); ip != nil {
+			ipAddresses = append(ipAddresses, ip)
+			continue
+		}
+		dnsNames = append(dnsNames, host)
+	}
+	if len(dnsNames) == 0 && len(ipAddresses) == 0 {
+		return nil, nil, errors.Errorf("no hosts found in '%s'", hosts)
+	}
+
+	var commonName string
+	if len(dnsNames) > 0 {
+		commonName = dnsNames[0]
+	} else {
+		commonName = ipAddresses[0].String()
+	}
+	certificate, key, err := cert.GenerateSelfSignedCertKey(commonName, ipAddresses, dnsNames)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to generate self-signed certificate")
+	}
+	return certificate, key, nil
+}
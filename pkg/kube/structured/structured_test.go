@@ -15,8 +15,15 @@ limitations under the License.
 package structured
 
 import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -24,15 +31,20 @@ import (
 	"github.com/keikoproj/kubedog/pkg/kube/common"
 	appsv1 "k8s.io/api/apps/v1"
 	v2 "k8s.io/api/autoscaling/v2"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	v1 "k8s.io/api/policy/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/fake"
+	kTesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/util/cert"
 )
 
 const (
@@ -51,6 +63,7 @@ const (
 	statefulSetType           = "statefulset"
 	secretType                = "secret"
 	ingressType               = "ingress"
+	jobType                   = "job"
 )
 
 func TestNodesWithSelectorShouldBe(t *testing.T) {
@@ -314,17 +327,29 @@ func TestListNodes(t *testing.T) {
 	type args struct {
 		kubeClientset kubernetes.Interface
 	}
+	failingClientset := fake.NewSimpleClientset()
+	failingClientset.PrependReactor("list", "nodes", func(action kTesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.New("list nodes failed")
+	})
 	tests := []struct {
-		name    string
-		args    args
-		wantErr bool
+		name      string
+		args      args
+		wantNodes []string
+		wantErr   bool
 	}{
-		// TODO: add negative tests and other positive test cases
 		{
 			name: "Positive Test",
 			args: args{
 				kubeClientset: fake.NewSimpleClientset(getResource(t, nodeType, "node1")),
 			},
+			wantNodes: []string{"node1"},
+		},
+		{
+			name: "Negative Test: list fails",
+			args: args{
+				kubeClientset: failingClientset,
+			},
+			wantErr: true,
 		},
 	}
 	for _, tt := range tests {
@@ -332,6 +357,15 @@ func TestListNodes(t *testing.T) {
 			if err := ListNodes(tt.args.kubeClientset); (err != nil) != tt.wantErr {
 				t.Errorf("ListNodes() error = %v, wantErr %v", err, tt.wantErr)
 			}
+			table, err := GetNodesTable(tt.args.kubeClientset)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetNodesTable() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			for _, name := range tt.wantNodes {
+				if !strings.Contains(table, name) {
+					t.Errorf("GetNodesTable() table = %q, want node %s", table, name)
+				}
+			}
 		})
 	}
 }
@@ -615,7 +649,6 @@ func TestSecretOperationFromEnvironmentVariable(t *testing.T) {
 		args    args
 		wantErr bool
 	}{
-		// TODO: add negative tests
 		{
 			name: "Positive Test: create/submit",
 			args: args{
@@ -636,6 +669,27 @@ func TestSecretOperationFromEnvironmentVariable(t *testing.T) {
 				environmentVariable: "MY_TEST_SECRET",
 			},
 		},
+		{
+			name: "Positive Test: upsert existing",
+			args: args{
+				kubeClientset:       fake.NewSimpleClientset(getResourceWithNamespace(t, secretType, secretName, namespace)),
+				operation:           common.OperationUpsert,
+				name:                secretName,
+				namespace:           namespace,
+				environmentVariable: "MY_TEST_SECRET",
+			},
+		},
+		{
+			name: "Negative Test: create existing",
+			args: args{
+				kubeClientset:       fake.NewSimpleClientset(getResourceWithNamespace(t, secretType, secretName, namespace)),
+				operation:           common.OperationCreate,
+				name:                secretName,
+				namespace:           namespace,
+				environmentVariable: "MY_TEST_SECRET",
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -647,6 +701,214 @@ func TestSecretOperationFromEnvironmentVariable(t *testing.T) {
 	}
 }
 
+func TestSecretOperationFromTable(t *testing.T) {
+	type args struct {
+		kubeClientset kubernetes.Interface
+		operation     string
+		secretType    string
+		rows          [][]string
+	}
+	secretName := "secret1"
+	namespace := "namespace1"
+	filesPath := t.TempDir()
+	if err := os.WriteFile(filepath.Join(filesPath, "ca.crt"), []byte("certificate-data"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(filesPath, "config.tmpl"), []byte("region: {{.Region}}"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	t.Setenv("MY_TEST_PASSWORD", "some-test-password")
+	header := []string{"key", "source", "value"}
+	existingSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: secretName, Namespace: namespace},
+		Type:       corev1.SecretTypeOpaque,
+		Data:       map[string][]byte{"existing": []byte("value")},
+	}
+	tests := []struct {
+		name     string
+		args     args
+		wantData map[string]string
+		wantErr  bool
+	}{
+		{
+			name: "Positive Test: create opaque secret from every source",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(),
+				operation:     common.OperationCreate,
+				secretType:    string(corev1.SecretTypeOpaque),
+				rows: [][]string{
+					header,
+					{"username", "literal", "admin"},
+					{"password", "env", "MY_TEST_PASSWORD"},
+					{"ca.crt", "file", "ca.crt"},
+					{"config.yaml", "template", "config.tmpl"},
+				},
+			},
+			wantData: map[string]string{
+				"username":    "admin",
+				"password":    "some-test-password",
+				"ca.crt":      "certificate-data",
+				"config.yaml": "region: us-west-2",
+			},
+		},
+		{
+			name: "Positive Test: upsert merges into existing secret",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(existingSecret.DeepCopy()),
+				operation:     common.OperationUpsert,
+				secretType:    string(corev1.SecretTypeOpaque),
+				rows:          [][]string{header, {"added", "literal", "new"}},
+			},
+			wantData: map[string]string{"existing": "value", "added": "new"},
+		},
+		{
+			name: "Positive Test: basic-auth secret",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(),
+				operation:     common.OperationSubmit,
+				secretType:    string(corev1.SecretTypeBasicAuth),
+				rows:          [][]string{header, {"username", "literal", "admin"}},
+			},
+			wantData: map[string]string{"username": "admin"},
+		},
+		{
+			name: "Negative Test: create existing secret",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(existingSecret.DeepCopy()),
+				operation:     common.OperationCreate,
+				secretType:    string(corev1.SecretTypeOpaque),
+				rows:          [][]string{header, {"added", "literal", "new"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Negative Test: update type of existing secret",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(existingSecret.DeepCopy()),
+				operation:     common.OperationUpdate,
+				secretType:    string(corev1.SecretTypeBasicAuth),
+				rows:          [][]string{header, {"username", "literal", "admin"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Negative Test: tls secret without key",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(),
+				operation:     common.OperationCreate,
+				secretType:    string(corev1.SecretTypeTLS),
+				rows:          [][]string{header, {"tls.crt", "file", "ca.crt"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Negative Test: invalid dockerconfigjson",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(),
+				operation:     common.OperationCreate,
+				secretType:    string(corev1.SecretTypeDockerConfigJson),
+				rows:          [][]string{header, {".dockerconfigjson", "literal", "{"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Negative Test: unsupported source",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(),
+				operation:     common.OperationCreate,
+				secretType:    string(corev1.SecretTypeOpaque),
+				rows:          [][]string{header, {"username", "vault", "admin"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Negative Test: missing environment variable",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(),
+				operation:     common.OperationCreate,
+				secretType:    string(corev1.SecretTypeOpaque),
+				rows:          [][]string{header, {"password", "env", "MY_MISSING_TEST_PASSWORD"}},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			templateArguments := struct{ Region string }{Region: "us-west-2"}
+			if err := SecretOperationFromTable(tt.args.kubeClientset, tt.args.operation, tt.args.secretType, secretName, namespace, tt.args.rows, filesPath, templateArguments); (err != nil) != tt.wantErr {
+				t.Errorf("SecretOperationFromTable() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			secret, err := GetSecret(tt.args.kubeClientset, secretName, namespace)
+			if err != nil {
+				t.Fatalf("GetSecret() error = %v", err)
+			}
+			if len(secret.Data) != len(tt.wantData) {
+				t.Errorf("SecretOperationFromTable() created %d keys, want %d", len(secret.Data), len(tt.wantData))
+			}
+			for key, value := range tt.wantData {
+				if string(secret.Data[key]) != value {
+					t.Errorf("SecretOperationFromTable() key '%s' = '%s', want '%s'", key, secret.Data[key], value)
+				}
+			}
+		})
+	}
+}
+
+func TestSecretOperationWithSelfSignedCertificate(t *testing.T) {
+	type args struct {
+		hosts string
+	}
+	secretName := "secret1"
+	namespace := "namespace1"
+	tests := []struct {
+		name         string
+		args         args
+		wantDNSNames []string
+		wantErr      bool
+	}{
+		{
+			name:         "Positive Test: dns names and ip",
+			args:         args{hosts: "example.com,www.example.com,10.0.0.1"},
+			wantDNSNames: []string{"example.com", "www.example.com"},
+		},
+		{
+			name:    "Negative Test: no hosts",
+			args:    args{hosts: ","},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kubeClientset := fake.NewSimpleClientset()
+			if err := SecretOperationWithSelfSignedCertificate(kubeClientset, common.OperationCreate, secretName, namespace, tt.args.hosts); (err != nil) != tt.wantErr {
+				t.Errorf("SecretOperationWithSelfSignedCertificate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			secret, err := GetSecret(kubeClientset, secretName, namespace)
+			if err != nil {
+				t.Fatalf("GetSecret() error = %v", err)
+			}
+			if secret.Type != corev1.SecretTypeTLS || len(secret.Data[corev1.TLSPrivateKeyKey]) == 0 {
+				t.Errorf("SecretOperationWithSelfSignedCertificate() created secret of type %s without a private key", secret.Type)
+			}
+			certificates, err := cert.ParseCertsPEM(secret.Data[corev1.TLSCertKey])
+			if err != nil {
+				t.Fatalf("failed to parse certificate: %v", err)
+			}
+			for _, dnsName := range tt.wantDNSNames {
+				if err := certificates[0].VerifyHostname(dnsName); err != nil {
+					t.Errorf("SecretOperationWithSelfSignedCertificate() certificate is not valid for %s: %v", dnsName, err)
+				}
+			}
+		})
+	}
+}
+
 func TestIngressAvailable(t *testing.T) {
 	type args struct {
 		kubeClientset kubernetes.Interface
@@ -732,72 +994,1410 @@ func TestSendTrafficToIngress(t *testing.T) {
 	}
 }
 
-func getIngressWithHostname(t *testing.T, name, namespace, hostname string) runtime.Object {
-	ingressInterface := getResourceWithNamespace(t, ingressType, name, namespace)
-	ingress, ok := ingressInterface.(*networkingv1.Ingress)
-	if !ok {
-		t.Errorf("'runtime.Object' could not be cast to '*appsv1.StatefulSet': %v", ingressInterface)
-	}
-	ingress.Status.LoadBalancer.Ingress = []networkingv1.IngressLoadBalancerIngress{
-		{Hostname: hostname},
-	}
-	return ingress
-}
-
-func getStatefulSetWithVolumeClaimTemplate(t *testing.T, name, namespace, volumeClaimTemplatesName string) runtime.Object {
-	statefulSetInterface := getResourceWithNamespace(t, statefulSetType, name, namespace)
-	statefulSet, ok := statefulSetInterface.(*appsv1.StatefulSet)
-	if !ok {
-		t.Errorf("'runtime.Object' could not be cast to '*appsv1.StatefulSet': %v", statefulSetInterface)
+func TestJobsWithSelectorCountShouldBe(t *testing.T) {
+	type args struct {
+		kubeClientset kubernetes.Interface
+		w             common.WaiterConfig
+		comparison    string
+		expectedCount int
+		namespace     string
+		selector      string
+		state         string
 	}
-	statefulSet.Spec.VolumeClaimTemplates = []corev1.PersistentVolumeClaim{
+	label := "some-label-key=some-label-value"
+	namespace := "namespace1"
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
 		{
-			ObjectMeta: metav1.ObjectMeta{
-				Name: volumeClaimTemplatesName,
+			name: "Positive Test: exactly 1 job completed",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(getResourceWithAll(t, jobType, "job1", namespace, label)),
+				comparison:    common.ComparisonExactly,
+				expectedCount: 1,
+				namespace:     namespace,
+				selector:      label,
+				state:         common.StateCompleted,
+			},
+		},
+		{
+			name: "Positive Test: at most 1 job found",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(),
+				comparison:    common.ComparisonAtMost,
+				expectedCount: 1,
+				namespace:     namespace,
+				selector:      label,
+				state:         common.StateFound,
+			},
+		},
+		{
+			name: "Negative Test: at least 2 jobs completed",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(getResourceWithAll(t, jobType, "job1", namespace, label)),
+				comparison:    common.ComparisonAtLeast,
+				expectedCount: 2,
+				namespace:     namespace,
+				selector:      label,
+				state:         common.StateCompleted,
+			},
+			wantErr: true,
+		},
+		{
+			name: "Negative Test: invalid state",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(getResourceWithAll(t, jobType, "job1", namespace, label)),
+				comparison:    common.ComparisonExactly,
+				expectedCount: 1,
+				namespace:     namespace,
+				selector:      label,
+				state:         "invalid-state",
 			},
+			wantErr: true,
 		},
 	}
-	return statefulSet
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.args.w = common.NewWaiterConfig(1, time.Second)
+			if err := JobsWithSelectorCountShouldBe(tt.args.kubeClientset, tt.args.w, tt.args.comparison, tt.args.expectedCount, tt.args.namespace, tt.args.selector, tt.args.state); (err != nil) != tt.wantErr {
+				t.Errorf("JobsWithSelectorCountShouldBe() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
 }
 
-func getNodeWithStatus(t *testing.T, name, label string, statusType corev1.NodeConditionType, status corev1.ConditionStatus) runtime.Object {
-	nodeInterface := getResourceWithLabel(t, nodeType, name, label)
-	node, ok := nodeInterface.(*corev1.Node)
-	if !ok {
-		t.Errorf("'runtime.Object' could not be cast to '*corev1.Node': %v", nodeInterface)
+func TestPersistentVolumeClaimsWithSelectorCountShouldBe(t *testing.T) {
+	type args struct {
+		kubeClientset kubernetes.Interface
+		w             common.WaiterConfig
+		comparison    string
+		expectedCount int
+		namespace     string
+		selector      string
+		state         string
 	}
-	node.Status = corev1.NodeStatus{
-		Conditions: []corev1.NodeCondition{
-			{
-				Type:   statusType,
-				Status: status,
+	label := "some-label-key=some-label-value"
+	namespace := "namespace1"
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "Positive Test: exactly 1 pvc bound",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(getResourceWithAll(t, persistentVolumeClaimType, "pvc1", namespace, label)),
+				comparison:    common.ComparisonExactly,
+				expectedCount: 1,
+				namespace:     namespace,
+				selector:      label,
+				state:         common.StateBound,
+			},
+		},
+		{
+			name: "Negative Test: invalid comparison",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(getResourceWithAll(t, persistentVolumeClaimType, "pvc1", namespace, label)),
+				comparison:    "more than",
+				expectedCount: 1,
+				namespace:     namespace,
+				selector:      label,
+				state:         common.StateBound,
+			},
+			wantErr: true,
+		},
+		{
+			name: "Negative Test: no pvcs found",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(),
+				comparison:    common.ComparisonAtLeast,
+				expectedCount: 1,
+				namespace:     namespace,
+				selector:      label,
+				state:         common.StateFound,
 			},
+			wantErr: true,
 		},
 	}
-	return node
-}
-
-func getResource(t *testing.T, resourceType, name string) runtime.Object {
-	return getResourceWithAll(t, resourceType, name, "", "")
-}
-
-func getResourceWithNamespace(t *testing.T, resourceType, name, namespace string) runtime.Object {
-	return getResourceWithAll(t, resourceType, name, namespace, "")
-}
-
-func getResourceWithLabel(t *testing.T, resourceType, name, label string) runtime.Object {
-	return getResourceWithAll(t, resourceType, name, "", label)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.args.w = common.NewWaiterConfig(1, time.Second)
+			if err := PersistentVolumeClaimsWithSelectorCountShouldBe(tt.args.kubeClientset, tt.args.w, tt.args.comparison, tt.args.expectedCount, tt.args.namespace, tt.args.selector, tt.args.state); (err != nil) != tt.wantErr {
+				t.Errorf("PersistentVolumeClaimsWithSelectorCountShouldBe() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
 }
 
-func getResourceWithAll(t *testing.T, resourceType, name, namespace, label string) runtime.Object {
-	labels := map[string]string{}
-	if label != "" {
-		key, value := getLabelParts(t, label)
-		labels[key] = value
+func TestPodDisruptionBudgetStatusShouldBe(t *testing.T) {
+	type args struct {
+		kubeClientset kubernetes.Interface
+		w             common.WaiterConfig
+		name          string
+		namespace     string
+		comparison    string
+		expectedValue int
+		statusField   string
 	}
-
-	switch resourceType {
-	case deploymentType:
+	pdbName := "poddisruptionbudget1"
+	namespace := "namespace1"
+	pdb := &v1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      pdbName,
+			Namespace: namespace,
+		},
+		Status: v1.PodDisruptionBudgetStatus{
+			DisruptionsAllowed: 1,
+			CurrentHealthy:     3,
+			DesiredHealthy:     2,
+			ExpectedPods:       3,
+		},
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "Positive Test: exactly 1 disruptionsAllowed",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(pdb),
+				name:          pdbName,
+				namespace:     namespace,
+				comparison:    common.ComparisonExactly,
+				expectedValue: 1,
+				statusField:   "disruptionsAllowed",
+			},
+		},
+		{
+			name: "Positive Test: at least 2 currentHealthy",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(pdb),
+				name:          pdbName,
+				namespace:     namespace,
+				comparison:    common.ComparisonAtLeast,
+				expectedValue: 2,
+				statusField:   "currentHealthy",
+			},
+		},
+		{
+			name: "Negative Test: at most 0 disruptionsAllowed",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(pdb),
+				name:          pdbName,
+				namespace:     namespace,
+				comparison:    common.ComparisonAtMost,
+				expectedValue: 0,
+				statusField:   "disruptionsAllowed",
+			},
+			wantErr: true,
+		},
+		{
+			name: "Negative Test: invalid status field",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(pdb),
+				name:          pdbName,
+				namespace:     namespace,
+				comparison:    common.ComparisonExactly,
+				expectedValue: 1,
+				statusField:   "observedGeneration",
+			},
+			wantErr: true,
+		},
+		{
+			name: "Negative Test: poddisruptionbudget not found",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(),
+				name:          pdbName,
+				namespace:     namespace,
+				comparison:    common.ComparisonExactly,
+				expectedValue: 1,
+				statusField:   "disruptionsAllowed",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.args.w = common.NewWaiterConfig(1, time.Second)
+			if err := PodDisruptionBudgetStatusShouldBe(tt.args.kubeClientset, tt.args.w, tt.args.name, tt.args.namespace, tt.args.comparison, tt.args.expectedValue, tt.args.statusField); (err != nil) != tt.wantErr {
+				t.Errorf("PodDisruptionBudgetStatusShouldBe() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCreateJobFromCronJob(t *testing.T) {
+	type args struct {
+		kubeClientset kubernetes.Interface
+		jobName       string
+		cronJobName   string
+		namespace     string
+	}
+	cronJobName := "cronjob1"
+	namespace := "namespace1"
+	cronJob := &batchv1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cronJobName,
+			Namespace: namespace,
+			UID:       "cronjob1-uid",
+		},
+		Spec: batchv1.CronJobSpec{
+			JobTemplate: batchv1.JobTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"app": "cronjob1"},
+				},
+			},
+		},
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "Positive Test",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(cronJob),
+				jobName:       "job1",
+				cronJobName:   cronJobName,
+				namespace:     namespace,
+			},
+		},
+		{
+			name: "Negative Test: cronjob not found",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(),
+				jobName:       "job1",
+				cronJobName:   cronJobName,
+				namespace:     namespace,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := CreateJobFromCronJob(tt.args.kubeClientset, tt.args.jobName, tt.args.cronJobName, tt.args.namespace); (err != nil) != tt.wantErr {
+				t.Errorf("CreateJobFromCronJob() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			job, err := GetJob(tt.args.kubeClientset, tt.args.jobName, tt.args.namespace)
+			if err != nil {
+				t.Fatalf("GetJob() error = %v", err)
+			}
+			if job.Annotations[cronJobInstantiateAnnotation] != "manual" {
+				t.Errorf("CreateJobFromCronJob() annotations = %v, want %s=manual", job.Annotations, cronJobInstantiateAnnotation)
+			}
+			if job.Labels["app"] != "cronjob1" {
+				t.Errorf("CreateJobFromCronJob() labels = %v, want app=cronjob1", job.Labels)
+			}
+			if len(job.OwnerReferences) != 1 || job.OwnerReferences[0].Name != tt.args.cronJobName {
+				t.Errorf("CreateJobFromCronJob() ownerReferences = %v, want cronjob %s", job.OwnerReferences, tt.args.cronJobName)
+			}
+		})
+	}
+}
+
+func TestJobShouldFinish(t *testing.T) {
+	type args struct {
+		kubeClientset kubernetes.Interface
+		w             common.WaiterConfig
+		name          string
+		namespace     string
+		outcome       string
+	}
+	namespace := "namespace1"
+	completeJob := getJobWithCondition("job-complete", namespace, batchv1.JobComplete, "")
+	failedJob := getJobWithCondition("job-failed", namespace, batchv1.JobFailed, jobReasonBackoffLimitExceeded)
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "Positive Test: job completes",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(completeJob),
+				w:             common.NewWaiterConfig(1, time.Millisecond),
+				name:          completeJob.Name,
+				namespace:     namespace,
+				outcome:       jobOutcomeComplete,
+			},
+		},
+		{
+			name: "Positive Test: job fails",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(failedJob),
+				w:             common.NewWaiterConfig(1, time.Millisecond),
+				name:          failedJob.Name,
+				namespace:     namespace,
+				outcome:       jobOutcomeFail,
+			},
+		},
+		{
+			name: "Negative Test: job fails when expected to complete",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(failedJob),
+				w:             common.NewWaiterConfig(1, time.Millisecond),
+				name:          failedJob.Name,
+				namespace:     namespace,
+				outcome:       jobOutcomeComplete,
+			},
+			wantErr: true,
+		},
+		{
+			name: "Negative Test: unsupported outcome",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(completeJob),
+				w:             common.NewWaiterConfig(1, time.Millisecond),
+				name:          completeJob.Name,
+				namespace:     namespace,
+				outcome:       "succeed",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := JobShouldFinish(tt.args.kubeClientset, tt.args.w, tt.args.name, tt.args.namespace, tt.args.outcome); (err != nil) != tt.wantErr {
+				t.Errorf("JobShouldFinish() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestJobShouldHavePodCount(t *testing.T) {
+	type args struct {
+		kubeClientset kubernetes.Interface
+		w             common.WaiterConfig
+		comparison    string
+		expectedCount int
+		podStatus     string
+	}
+	namespace := "namespace1"
+	job := getJobWithCondition("job1", namespace, batchv1.JobComplete, "")
+	job.Status.Succeeded = 3
+	job.Status.Failed = 1
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "Positive Test: exactly 3 succeeded",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(job),
+				w:             common.NewWaiterConfig(1, time.Millisecond),
+				comparison:    common.ComparisonExactly,
+				expectedCount: 3,
+				podStatus:     jobPodsSucceeded,
+			},
+		},
+		{
+			name: "Positive Test: at most 1 failed",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(job),
+				w:             common.NewWaiterConfig(1, time.Millisecond),
+				comparison:    common.ComparisonAtMost,
+				expectedCount: 1,
+				podStatus:     jobPodsFailed,
+			},
+		},
+		{
+			name: "Negative Test: at least 1 active",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(job),
+				w:             common.NewWaiterConfig(1, time.Millisecond),
+				comparison:    common.ComparisonAtLeast,
+				expectedCount: 1,
+				podStatus:     jobPodsActive,
+			},
+			wantErr: true,
+		},
+		{
+			name: "Negative Test: unsupported pod status",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(job),
+				w:             common.NewWaiterConfig(1, time.Millisecond),
+				comparison:    common.ComparisonExactly,
+				expectedCount: 1,
+				podStatus:     "pending",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := JobShouldHavePodCount(tt.args.kubeClientset, tt.args.w, job.Name, namespace, tt.args.comparison, tt.args.expectedCount, tt.args.podStatus); (err != nil) != tt.wantErr {
+				t.Errorf("JobShouldHavePodCount() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestJobShouldExceedBackoffLimit(t *testing.T) {
+	type args struct {
+		kubeClientset kubernetes.Interface
+		w             common.WaiterConfig
+		name          string
+	}
+	namespace := "namespace1"
+	backoffLimit := int32(2)
+	exceededJob := getJobWithCondition("job-exceeded", namespace, batchv1.JobFailed, jobReasonBackoffLimitExceeded)
+	exceededJob.Spec.BackoffLimit = &backoffLimit
+	exceededJob.Status.Failed = backoffLimit + 1
+	parallelJob := getJobWithCondition("job-parallel", namespace, batchv1.JobFailed, jobReasonBackoffLimitExceeded)
+	parallelJob.Spec.BackoffLimit = &backoffLimit
+	parallelJob.Status.Failed = backoffLimit + 3
+	underLimitJob := getJobWithCondition("job-under-limit", namespace, batchv1.JobFailed, jobReasonBackoffLimitExceeded)
+	underLimitJob.Spec.BackoffLimit = &backoffLimit
+	underLimitJob.Status.Failed = backoffLimit
+	deadlineJob := getJobWithCondition("job-deadline", namespace, batchv1.JobFailed, "DeadlineExceeded")
+	deadlineJob.Spec.BackoffLimit = &backoffLimit
+	deadlineJob.Status.Failed = 1
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "Positive Test",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(exceededJob),
+				w:             common.NewWaiterConfig(1, time.Millisecond),
+				name:          exceededJob.Name,
+			},
+		},
+		{
+			name: "Positive Test: parallel pods failed past the limit",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(parallelJob),
+				w:             common.NewWaiterConfig(1, time.Millisecond),
+				name:          parallelJob.Name,
+			},
+		},
+		{
+			name: "Negative Test: failed pods within the limit",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(underLimitJob),
+				w:             common.NewWaiterConfig(1, time.Millisecond),
+				name:          underLimitJob.Name,
+			},
+			wantErr: true,
+		},
+		{
+			name: "Negative Test: failed for another reason",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(deadlineJob),
+				w:             common.NewWaiterConfig(1, time.Millisecond),
+				name:          deadlineJob.Name,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := JobShouldExceedBackoffLimit(tt.args.kubeClientset, tt.args.w, tt.args.name, namespace); (err != nil) != tt.wantErr {
+				t.Errorf("JobShouldExceedBackoffLimit() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCronJobOperation(t *testing.T) {
+	type args struct {
+		operation string
+	}
+	cronJobName := "cronjob1"
+	namespace := "namespace1"
+	tests := []struct {
+		name        string
+		args        args
+		wantSuspend bool
+		wantErr     bool
+	}{
+		{
+			name:        "Positive Test: suspend",
+			args:        args{operation: common.OperationSuspend},
+			wantSuspend: true,
+		},
+		{
+			name:        "Positive Test: resume",
+			args:        args{operation: common.OperationResume},
+			wantSuspend: false,
+		},
+		{
+			name:    "Negative Test: unsupported operation",
+			args:    args{operation: common.OperationDelete},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kubeClientset := fake.NewSimpleClientset(&batchv1.CronJob{
+				ObjectMeta: metav1.ObjectMeta{Name: cronJobName, Namespace: namespace},
+			})
+			if err := CronJobOperation(kubeClientset, tt.args.operation, cronJobName, namespace); (err != nil) != tt.wantErr {
+				t.Errorf("CronJobOperation() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			cronJob, err := GetCronJob(kubeClientset, cronJobName, namespace)
+			if err != nil {
+				t.Fatalf("GetCronJob() error = %v", err)
+			}
+			if cronJob.Spec.Suspend == nil || *cronJob.Spec.Suspend != tt.wantSuspend {
+				t.Errorf("CronJobOperation() suspend = %v, want %t", cronJob.Spec.Suspend, tt.wantSuspend)
+			}
+		})
+	}
+}
+
+func TestHorizontalPodAutoscalerReplicasShouldBe(t *testing.T) {
+	type args struct {
+		comparison    string
+		expectedValue int
+		replicasField string
+	}
+	namespace := "namespace1"
+	hpa := getHorizontalPodAutoscalerWithStatus("hpa1", namespace, 1, 3, 4)
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "Positive Test: exactly 3 currentReplicas",
+			args: args{comparison: common.ComparisonExactly, expectedValue: 3, replicasField: hpaCurrentReplicas},
+		},
+		{
+			name: "Positive Test: at least 4 desiredReplicas",
+			args: args{comparison: common.ComparisonAtLeast, expectedValue: 4, replicasField: hpaDesiredReplicas},
+		},
+		{
+			name:    "Negative Test: at most 2 currentReplicas",
+			args:    args{comparison: common.ComparisonAtMost, expectedValue: 2, replicasField: hpaCurrentReplicas},
+			wantErr: true,
+		},
+		{
+			name:    "Negative Test: unsupported replicas field",
+			args:    args{comparison: common.ComparisonExactly, expectedValue: 1, replicasField: "maxReplicas"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kubeClientset := fake.NewSimpleClientset(hpa)
+			if err := HorizontalPodAutoscalerReplicasShouldBe(kubeClientset, common.NewWaiterConfig(1, time.Millisecond), hpa.Name, namespace, tt.args.comparison, tt.args.expectedValue, tt.args.replicasField); (err != nil) != tt.wantErr {
+				t.Errorf("HorizontalPodAutoscalerReplicasShouldBe() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestHorizontalPodAutoscalerShouldScale(t *testing.T) {
+	type args struct {
+		currentReplicas []int32
+		direction       string
+	}
+	namespace := "namespace1"
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "Positive Test: scaled up",
+			args: args{currentReplicas: []int32{2, 2, 5}, direction: hpaScaleUp},
+		},
+		{
+			name: "Positive Test: already scaled up by traffic sent before the step",
+			args: args{currentReplicas: []int32{5, 5, 5}, direction: hpaScaleUp},
+		},
+		{
+			name: "Positive Test: scaled down",
+			args: args{currentReplicas: []int32{5, 3, 2}, direction: hpaScaleDown},
+		},
+		{
+			name: "Positive Test: already at min replicas",
+			args: args{currentReplicas: []int32{2, 2, 2}, direction: hpaScaleDown},
+		},
+		{
+			name:    "Negative Test: not scaled up from min replicas",
+			args:    args{currentReplicas: []int32{2, 2, 2}, direction: hpaScaleUp},
+			wantErr: true,
+		},
+		{
+			name:    "Negative Test: not scaled down to min replicas",
+			args:    args{currentReplicas: []int32{5, 5, 5}, direction: hpaScaleDown},
+			wantErr: true,
+		},
+		{
+			name:    "Negative Test: unsupported direction",
+			args:    args{currentReplicas: []int32{2}, direction: "sideways"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hpa := getHorizontalPodAutoscalerWithStatus("hpa1", namespace, 2, tt.args.currentReplicas[0], tt.args.currentReplicas[0])
+			kubeClientset := fake.NewSimpleClientset(hpa)
+			var gets int
+			kubeClientset.PrependReactor("get", "horizontalpodautoscalers", func(action kTesting.Action) (bool, runtime.Object, error) {
+				replicas := tt.args.currentReplicas[min(gets, len(tt.args.currentReplicas)-1)]
+				gets++
+				return true, getHorizontalPodAutoscalerWithStatus(hpa.Name, namespace, 2, replicas, replicas), nil
+			})
+			if err := HorizontalPodAutoscalerShouldScale(kubeClientset, common.NewWaiterConfig(1, time.Millisecond), hpa.Name, namespace, tt.args.direction, 10*time.Millisecond); (err != nil) != tt.wantErr {
+				t.Errorf("HorizontalPodAutoscalerShouldScale() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestHorizontalPodAutoscalerShouldScaleAfterTraffic(t *testing.T) {
+	namespace := "namespace1"
+	ingressName := "ingress1"
+	hpaName := "hpa1"
+
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+	}))
+	defer server.Close()
+	serverURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	port, err := strconv.Atoi(serverURL.Port())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	kubeClientset := fake.NewSimpleClientset(
+		getIngressWithHostname(t, ingressName, namespace, serverURL.Hostname()),
+		getHorizontalPodAutoscalerWithStatus(hpaName, namespace, 2, 2, 2),
+	)
+	// the hpa scales up while traffic is being sent, before the scale step starts waiting
+	kubeClientset.PrependReactor("get", "horizontalpodautoscalers", func(action kTesting.Action) (bool, runtime.Object, error) {
+		replicas := int32(2)
+		if atomic.LoadInt32(&requests) > 0 {
+			replicas = 4
+		}
+		return true, getHorizontalPodAutoscalerWithStatus(hpaName, namespace, 2, replicas, replicas), nil
+	})
+
+	w := common.NewWaiterConfig(1, time.Millisecond)
+	if err := SendTrafficToIngress(kubeClientset, w, 5, ingressName, namespace, port, "/", 1, util.DurationSeconds, 0); err != nil {
+		t.Fatalf("SendTrafficToIngress() error = %v", err)
+	}
+	if err := HorizontalPodAutoscalerShouldScale(kubeClientset, w, hpaName, namespace, hpaScaleUp, 10*time.Millisecond); err != nil {
+		t.Errorf("HorizontalPodAutoscalerShouldScale() error = %v", err)
+	}
+}
+
+func TestHorizontalPodAutoscalerConditionShouldBe(t *testing.T) {
+	type args struct {
+		conditionType  string
+		expectedStatus string
+	}
+	namespace := "namespace1"
+	hpa := getHorizontalPodAutoscalerWithStatus("hpa1", namespace, 1, 1, 1)
+	hpa.Status.Conditions = []v2.HorizontalPodAutoscalerCondition{
+		{Type: v2.AbleToScale, Status: corev1.ConditionTrue},
+		{Type: v2.ScalingActive, Status: corev1.ConditionTrue},
+		{Type: v2.ScalingLimited, Status: corev1.ConditionFalse},
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "Positive Test: AbleToScale is True",
+			args: args{conditionType: string(v2.AbleToScale), expectedStatus: string(corev1.ConditionTrue)},
+		},
+		{
+			name: "Positive Test: ScalingLimited is False",
+			args: args{conditionType: string(v2.ScalingLimited), expectedStatus: string(corev1.ConditionFalse)},
+		},
+		{
+			name:    "Negative Test: ScalingActive is not False",
+			args:    args{conditionType: string(v2.ScalingActive), expectedStatus: string(corev1.ConditionFalse)},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kubeClientset := fake.NewSimpleClientset(hpa)
+			if err := HorizontalPodAutoscalerConditionShouldBe(kubeClientset, common.NewWaiterConfig(1, time.Millisecond), hpa.Name, namespace, tt.args.conditionType, tt.args.expectedStatus); (err != nil) != tt.wantErr {
+				t.Errorf("HorizontalPodAutoscalerConditionShouldBe() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestServiceShouldHaveReadyEndpoints(t *testing.T) {
+	type args struct {
+		comparison    string
+		expectedCount int
+	}
+	serviceName := "service1"
+	namespace := "namespace1"
+	ready, notReady := true, false
+	endpointSlice := func(name string, endpoints ...discoveryv1.Endpoint) *discoveryv1.EndpointSlice {
+		return &discoveryv1.EndpointSlice{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+				Labels:    map[string]string{discoveryv1.LabelServiceName: serviceName},
+			},
+			Endpoints: endpoints,
+		}
+	}
+	kubeClientset := fake.NewSimpleClientset(
+		endpointSlice("slice1",
+			discoveryv1.Endpoint{Addresses: []string{"10.0.0.1"}, Conditions: discoveryv1.EndpointConditions{Ready: &ready}},
+			discoveryv1.Endpoint{Addresses: []string{"10.0.0.2"}},
+			discoveryv1.Endpoint{Addresses: []string{"10.0.0.3"}, Conditions: discoveryv1.EndpointConditions{Ready: &notReady}},
+		),
+		endpointSlice("slice2",
+			discoveryv1.Endpoint{Addresses: []string{"10.0.0.1"}, Conditions: discoveryv1.EndpointConditions{Ready: &ready}},
+		),
+	)
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "Positive Test: exactly 2 ready endpoints",
+			args: args{comparison: common.ComparisonExactly, expectedCount: 2},
+		},
+		{
+			name:    "Negative Test: at least 3 ready endpoints",
+			args:    args{comparison: common.ComparisonAtLeast, expectedCount: 3},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ServiceShouldHaveReadyEndpoints(kubeClientset, common.NewWaiterConfig(1, time.Millisecond), serviceName, namespace, tt.args.comparison, tt.args.expectedCount); (err != nil) != tt.wantErr {
+				t.Errorf("ServiceShouldHaveReadyEndpoints() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestServiceShouldSelectPodsWithSelector(t *testing.T) {
+	type args struct {
+		selector string
+	}
+	serviceName := "service1"
+	namespace := "namespace1"
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: serviceName, Namespace: namespace},
+		Spec:       corev1.ServiceSpec{Selector: map[string]string{"app": "web"}},
+	}
+	newPod := func(name string, labels map[string]string) *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: labels}}
+	}
+	kubeClientset := fake.NewSimpleClientset(
+		service,
+		newPod("web-1", map[string]string{"app": "web", "tier": "frontend"}),
+		newPod("web-2", map[string]string{"app": "web", "tier": "frontend"}),
+		newPod("api-1", map[string]string{"app": "api", "tier": "frontend"}),
+	)
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "Positive Test",
+			args: args{selector: "app=web"},
+		},
+		{
+			name:    "Negative Test: selector does not match all pods",
+			args:    args{selector: "tier=frontend"},
+			wantErr: true,
+		},
+		{
+			name:    "Negative Test: no pods found",
+			args:    args{selector: "app=db"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ServiceShouldSelectPodsWithSelector(kubeClientset, serviceName, namespace, tt.args.selector); (err != nil) != tt.wantErr {
+				t.Errorf("ServiceShouldSelectPodsWithSelector() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestServiceShouldHaveLoadBalancerIngress(t *testing.T) {
+	type args struct {
+		service *corev1.Service
+	}
+	namespace := "namespace1"
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "Positive Test",
+			args: args{service: &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{Name: "service1", Namespace: namespace},
+				Spec:       corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer},
+				Status: corev1.ServiceStatus{LoadBalancer: corev1.LoadBalancerStatus{
+					Ingress: []corev1.LoadBalancerIngress{{Hostname: "example.elb.amazonaws.com"}},
+				}},
+			}},
+		},
+		{
+			name: "Negative Test: no ingress assigned",
+			args: args{service: &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{Name: "service1", Namespace: namespace},
+				Spec:       corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "Negative Test: not a load balancer",
+			args: args{service: &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{Name: "service1", Namespace: namespace},
+				Spec:       corev1.ServiceSpec{Type: corev1.ServiceTypeClusterIP},
+			}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kubeClientset := fake.NewSimpleClientset(tt.args.service)
+			if err := ServiceShouldHaveLoadBalancerIngress(kubeClientset, common.NewWaiterConfig(1, time.Millisecond), tt.args.service.Name, namespace); (err != nil) != tt.wantErr {
+				t.Errorf("ServiceShouldHaveLoadBalancerIngress() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestServiceShouldBeOfType(t *testing.T) {
+	type args struct {
+		serviceType string
+	}
+	service := getServiceWithPorts("service1", "namespace1")
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "Positive Test",
+			args: args{serviceType: "NodePort"},
+		},
+		{
+			name:    "Negative Test",
+			args:    args{serviceType: "ClusterIP"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ServiceShouldBeOfType(fake.NewSimpleClientset(service), service.Name, service.Namespace, tt.args.serviceType); (err != nil) != tt.wantErr {
+				t.Errorf("ServiceShouldBeOfType() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestServiceShouldHavePort(t *testing.T) {
+	type args struct {
+		protocol   string
+		port       int32
+		targetPort string
+	}
+	service := getServiceWithPorts("service1", "namespace1")
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "Positive Test: named target port",
+			args: args{protocol: "TCP", port: 80, targetPort: "http"},
+		},
+		{
+			name: "Positive Test: numeric target port",
+			args: args{protocol: "UDP", port: 53, targetPort: "5353"},
+		},
+		{
+			name:    "Negative Test: protocol mismatch",
+			args:    args{protocol: "UDP", port: 80, targetPort: "http"},
+			wantErr: true,
+		},
+		{
+			name:    "Negative Test: target port mismatch",
+			args:    args{protocol: "TCP", port: 80, targetPort: "8080"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ServiceShouldHavePort(fake.NewSimpleClientset(service), service.Name, service.Namespace, tt.args.protocol, tt.args.port, tt.args.targetPort); (err != nil) != tt.wantErr {
+				t.Errorf("ServiceShouldHavePort() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestServiceShouldHaveNodePort(t *testing.T) {
+	type args struct {
+		nodePort int32
+		protocol string
+		port     int32
+	}
+	service := getServiceWithPorts("service1", "namespace1")
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "Positive Test",
+			args: args{nodePort: 30080, protocol: "TCP", port: 80},
+		},
+		{
+			name:    "Negative Test",
+			args:    args{nodePort: 30081, protocol: "TCP", port: 80},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ServiceShouldHaveNodePort(fake.NewSimpleClientset(service), service.Name, service.Namespace, tt.args.nodePort, tt.args.protocol, tt.args.port); (err != nil) != tt.wantErr {
+				t.Errorf("ServiceShouldHaveNodePort() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDataKeyShouldMatch(t *testing.T) {
+	type args struct {
+		kind      string
+		key       string
+		matchType string
+		expected  string
+	}
+	namespace := "namespace1"
+	kubeClientset := fake.NewSimpleClientset(
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "data1", Namespace: namespace},
+			Data:       map[string]string{"mode": "production-eu"},
+			BinaryData: map[string][]byte{"blob": []byte("binary-value")},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "data1", Namespace: namespace},
+			Data:       map[string][]byte{"password": []byte("s3cr3t-value")},
+		},
+	)
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "Positive Test: configmap equal",
+			args: args{kind: "configmap", key: "mode", matchType: "equal", expected: "production-eu"},
+		},
+		{
+			name: "Positive Test: configmap binaryData contain",
+			args: args{kind: "configmap", key: "blob", matchType: "contain", expected: "binary"},
+		},
+		{
+			name: "Positive Test: secret match regex",
+			args: args{kind: "secret", key: "password", matchType: "match regex", expected: "^s3cr3t-.+$"},
+		},
+		{
+			name:    "Negative Test: configmap not equal",
+			args:    args{kind: "configmap", key: "mode", matchType: "equal", expected: "production"},
+			wantErr: true,
+		},
+		{
+			name:    "Negative Test: missing key",
+			args:    args{kind: "secret", key: "token", matchType: "equal", expected: "value"},
+			wantErr: true,
+		},
+		{
+			name:    "Negative Test: invalid regex",
+			args:    args{kind: "configmap", key: "mode", matchType: "match regex", expected: "("},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := DataKeyShouldMatch(kubeClientset, tt.args.kind, "data1", namespace, tt.args.key, tt.args.matchType, tt.args.expected); (err != nil) != tt.wantErr {
+				t.Errorf("DataKeyShouldMatch() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("Negative Test: secret values are redacted", func(t *testing.T) {
+		err := DataKeyShouldMatch(kubeClientset, "secret", "data1", namespace, "password", "equal", "other-value")
+		if err == nil {
+			t.Fatalf("DataKeyShouldMatch() expected an error")
+		}
+		if strings.Contains(err.Error(), "s3cr3t-value") || strings.Contains(err.Error(), "other-value") {
+			t.Errorf("DataKeyShouldMatch() error is not redacted: %v", err)
+		}
+	})
+}
+
+func TestDataKeyShouldMatchEnvironmentVariable(t *testing.T) {
+	namespace := "namespace1"
+	kubeClientset := fake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "data1", Namespace: namespace},
+		Data:       map[string][]byte{"password": []byte("s3cr3t-value")},
+	})
+	t.Setenv("KUBEDOG_TEST_PASSWORD", "s3cr3t-value")
+	t.Setenv("KUBEDOG_TEST_OTHER_PASSWORD", "other-value")
+	tests := []struct {
+		name                string
+		environmentVariable string
+		wantErr             bool
+	}{
+		{
+			name:                "Positive Test: value matches environment variable",
+			environmentVariable: "KUBEDOG_TEST_PASSWORD",
+		},
+		{
+			name:                "Negative Test: value does not match environment variable",
+			environmentVariable: "KUBEDOG_TEST_OTHER_PASSWORD",
+			wantErr:             true,
+		},
+		{
+			name:                "Negative Test: environment variable not set",
+			environmentVariable: "KUBEDOG_TEST_MISSING_PASSWORD",
+			wantErr:             true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := DataKeyShouldMatchEnvironmentVariable(kubeClientset, "secret", "data1", namespace, "password", "equal", tt.environmentVariable); (err != nil) != tt.wantErr {
+				t.Errorf("DataKeyShouldMatchEnvironmentVariable() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDataKeyShouldMatchFile(t *testing.T) {
+	type args struct {
+		fileContent       string
+		templateArguments interface{}
+	}
+	namespace := "namespace1"
+	kubeClientset := fake.NewSimpleClientset(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "data1", Namespace: namespace},
+		Data:       map[string]string{"config.yaml": "region: us-west-2\n"},
+	})
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "Positive Test",
+			args: args{fileContent: "region: us-west-2\n"},
+		},
+		{
+			name: "Positive Test: templated file",
+			args: args{fileContent: "region: {{.Region}}\n", templateArguments: struct{ Region string }{Region: "us-west-2"}},
+		},
+		{
+			name:    "Negative Test",
+			args:    args{fileContent: "region: us-east-1\n"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filePath := filepath.Join(t.TempDir(), "config.yaml")
+			if err := os.WriteFile(filePath, []byte(tt.args.fileContent), 0644); err != nil {
+				t.Fatalf("failed to write file: %v", err)
+			}
+			if err := DataKeyShouldMatchFile(kubeClientset, "configmap", "data1", namespace, "config.yaml", filePath, tt.args.templateArguments); (err != nil) != tt.wantErr {
+				t.Errorf("DataKeyShouldMatchFile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDataKeyAtPathShouldMatch(t *testing.T) {
+	type args struct {
+		key       string
+		format    string
+		path      string
+		matchType string
+		expected  string
+	}
+	namespace := "namespace1"
+	kubeClientset := fake.NewSimpleClientset(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "data1", Namespace: namespace},
+		Data: map[string]string{
+			"config.yaml": "server:\n  port: 8080\n  hosts:\n  - name: primary\n  - name: secondary\n",
+			"config.json": `{"logging": {"level": "debug", "enabled": true}}`,
+		},
+	})
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "Positive Test: yaml number",
+			args: args{key: "config.yaml", format: "yaml", path: "server.port", matchType: "equal", expected: "8080"},
+		},
+		{
+			name: "Positive Test: yaml list element",
+			args: args{key: "config.yaml", format: "yaml", path: "server.hosts[1].name", matchType: "equal", expected: "secondary"},
+		},
+		{
+			name: "Positive Test: json object",
+			args: args{key: "config.json", format: "json", path: "logging", matchType: "contain", expected: `"level":"debug"`},
+		},
+		{
+			name: "Positive Test: json boolean",
+			args: args{key: "config.json", format: "json", path: "logging.enabled", matchType: "equal", expected: "true"},
+		},
+		{
+			name:    "Negative Test: value mismatch",
+			args:    args{key: "config.yaml", format: "yaml", path: "server.port", matchType: "equal", expected: "9090"},
+			wantErr: true,
+		},
+		{
+			name:    "Negative Test: path not found",
+			args:    args{key: "config.json", format: "json", path: "logging.format", matchType: "equal", expected: "json"},
+			wantErr: true,
+		},
+		{
+			name:    "Negative Test: not json",
+			args:    args{key: "config.yaml", format: "json", path: "server.port", matchType: "equal", expected: "8080"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := DataKeyAtPathShouldMatch(kubeClientset, "configmap", "data1", namespace, tt.args.key, tt.args.format, tt.args.path, tt.args.matchType, tt.args.expected); (err != nil) != tt.wantErr {
+				t.Errorf("DataKeyAtPathShouldMatch() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCreateNamespace(t *testing.T) {
+	type args struct {
+		kubeClientset kubernetes.Interface
+		name          string
+		labels        map[string]string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "Positive Test",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(),
+				name:          "namespace1",
+				labels:        map[string]string{"team": "platform"},
+			},
+		},
+		{
+			name: "Negative Test: already exists",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "namespace1"}}),
+				name:          "namespace1",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := CreateNamespace(tt.args.kubeClientset, tt.args.name, tt.args.labels); (err != nil) != tt.wantErr {
+				t.Errorf("CreateNamespace() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDeleteNamespace(t *testing.T) {
+	type args struct {
+		kubeClientset kubernetes.Interface
+		name          string
+	}
+	terminating := fake.NewSimpleClientset(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "namespace1"}})
+	terminating.PrependReactor("delete", "namespaces", func(action kTesting.Action) (bool, runtime.Object, error) {
+		return true, nil, nil
+	})
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "Positive Test",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "namespace1"}}),
+				name:          "namespace1",
+			},
+		},
+		{
+			name: "Positive Test: not found",
+			args: args{
+				kubeClientset: fake.NewSimpleClientset(),
+				name:          "namespace1",
+			},
+		},
+		{
+			name: "Negative Test: still terminating",
+			args: args{
+				kubeClientset: terminating,
+				name:          "namespace1",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := DeleteNamespace(tt.args.kubeClientset, common.NewWaiterConfig(1, time.Millisecond), tt.args.name); (err != nil) != tt.wantErr {
+				t.Errorf("DeleteNamespace() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func getIngressWithHostname(t *testing.T, name, namespace, hostname string) runtime.Object {
+	ingressInterface := getResourceWithNamespace(t, ingressType, name, namespace)
+	ingress, ok := ingressInterface.(*networkingv1.Ingress)
+	if !ok {
+		t.Errorf("'runtime.Object' could not be cast to '*appsv1.StatefulSet': %v", ingressInterface)
+	}
+	ingress.Status.LoadBalancer.Ingress = []networkingv1.IngressLoadBalancerIngress{
+		{Hostname: hostname},
+	}
+	return ingress
+}
+
+func getStatefulSetWithVolumeClaimTemplate(t *testing.T, name, namespace, volumeClaimTemplatesName string) runtime.Object {
+	statefulSetInterface := getResourceWithNamespace(t, statefulSetType, name, namespace)
+	statefulSet, ok := statefulSetInterface.(*appsv1.StatefulSet)
+	if !ok {
+		t.Errorf("'runtime.Object' could not be cast to '*appsv1.StatefulSet': %v", statefulSetInterface)
+	}
+	statefulSet.Spec.VolumeClaimTemplates = []corev1.PersistentVolumeClaim{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: volumeClaimTemplatesName,
+			},
+		},
+	}
+	return statefulSet
+}
+
+func getNodeWithStatus(t *testing.T, name, label string, statusType corev1.NodeConditionType, status corev1.ConditionStatus) runtime.Object {
+	nodeInterface := getResourceWithLabel(t, nodeType, name, label)
+	node, ok := nodeInterface.(*corev1.Node)
+	if !ok {
+		t.Errorf("'runtime.Object' could not be cast to '*corev1.Node': %v", nodeInterface)
+	}
+	node.Status = corev1.NodeStatus{
+		Conditions: []corev1.NodeCondition{
+			{
+				Type:   statusType,
+				Status: status,
+			},
+		},
+	}
+	return node
+}
+
+func getJobWithCondition(name, namespace string, conditionType batchv1.JobConditionType, reason string) *batchv1.Job {
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Status: batchv1.JobStatus{
+			Conditions: []batchv1.JobCondition{
+				{
+					Type:   conditionType,
+					Status: corev1.ConditionTrue,
+					Reason: reason,
+				},
+			},
+		},
+	}
+}
+
+func getHorizontalPodAutoscalerWithStatus(name, namespace string, minReplicas, currentReplicas, desiredReplicas int32) *v2.HorizontalPodAutoscaler {
+	return &v2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: v2.HorizontalPodAutoscalerSpec{
+			MinReplicas: &minReplicas,
+			MaxReplicas: 10,
+		},
+		Status: v2.HorizontalPodAutoscalerStatus{
+			CurrentReplicas: currentReplicas,
+			DesiredReplicas: desiredReplicas,
+		},
+	}
+}
+
+func getServiceWithPorts(name, namespace string) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: corev1.ServiceSpec{
+			Type: corev1.ServiceTypeNodePort,
+			Ports: []corev1.ServicePort{
+				{Protocol: corev1.ProtocolTCP, Port: 80, TargetPort: intstr.FromString("http"), NodePort: 30080},
+				{Protocol: corev1.ProtocolUDP, Port: 53, TargetPort: intstr.FromInt32(5353), NodePort: 30053},
+			},
+		},
+	}
+}
+
+func getResource(t *testing.T, resourceType, name string) runtime.Object {
+	return getResourceWithAll(t, resourceType, name, "", "")
+}
+
+func getResourceWithNamespace(t *testing.T, resourceType, name, namespace string) runtime.Object {
+	return getResourceWithAll(t, resourceType, name, namespace, "")
+}
+
+func getResourceWithLabel(t *testing.T, resourceType, name, label string) runtime.Object {
+	return getResourceWithAll(t, resourceType, name, "", label)
+}
+
+func getResourceWithAll(t *testing.T, resourceType, name, namespace, label string) runtime.Object {
+	labels := map[string]string{}
+	if label != "" {
+		key, value := getLabelParts(t, label)
+		labels[key] = value
+	}
+
+	switch resourceType {
+	case deploymentType:
 		return &appsv1.Deployment{
 			ObjectMeta: metav1.ObjectMeta{
 				Name:      name,
@@ -923,6 +2523,22 @@ func getResourceWithAll(t *testing.T, resourceType, name, namespace, label strin
 				Labels:    labels,
 			},
 		}
+	case jobType:
+		return &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+				Labels:    labels,
+			},
+			Status: batchv1.JobStatus{
+				Conditions: []batchv1.JobCondition{
+					{
+						Type:   batchv1.JobComplete,
+						Status: corev1.ConditionTrue,
+					},
+				},
+			},
+		}
 	default:
 		t.Errorf("Invalid resource type: %s", resourceType)
 	}
@@ -19,7 +19,6 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"reflect"
 	"strconv"
 	"strings"
 	"time"
@@ -30,8 +29,8 @@ import (
 	log "github.com/sirupsen/logrus"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
-	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/client-go/discovery"
@@ -68,10 +67,7 @@ func ResourceOperationInNamespace(dynamicClient dynamic.Interface, resource unst
 	}
 
 	gvr, unstruct := resource.GVR, resource.Resource
-
-	if namespace == "" {
-		namespace = unstruct.GetNamespace()
-	}
+	namespace = resolveNamespace(resource, namespace)
 
 	switch operation {
 	case common.OperationCreate, common.OperationSubmit:
@@ -153,208 +149,94 @@ func ResourceOperationWithResultInNamespace(dynamicClient dynamic.Interface, res
 }
 
 func ResourceShouldBe(dynamicClient dynamic.Interface, resource unstructuredResource, w common.WaiterConfig, state string) error {
-	var (
-		exists  bool
-		counter int
-	)
+	return ResourceShouldBeInNamespace(dynamicClient, resource, w, "", state)
+}
+
+func ResourceShouldBeInNamespace(dynamicClient dynamic.Interface, resource unstructuredResource, w common.WaiterConfig, namespace, state string) error {
+	return ResourcesShouldBeInNamespace(dynamicClient, []unstructuredResource{resource}, w, common.QuantifierAll, namespace, state)
+}
 
+func ResourcesShouldBeInNamespace(dynamicClient dynamic.Interface, resources []unstructuredResource, w common.WaiterConfig, quantifier, namespace, state string) error {
 	if err := validateDynamicClient(dynamicClient); err != nil {
 		return err
 	}
 
-	gvr, unstruct := resource.GVR, resource.Resource
-	for {
-		exists = true
-		if counter >= w.GetTries() {
-			return errors.New("waiter timed out waiting for resource state")
-		}
-		log.Infof("waiting for resource %v/%v to become %v", unstruct.GetNamespace(), unstruct.GetName(), state)
-
-		_, err := dynamicClient.Resource(gvr.Resource).Namespace(unstruct.GetNamespace()).Get(context.Background(), unstruct.GetName(), metav1.GetOptions{})
-		if err != nil {
-			if !kerrors.IsNotFound(err) {
-				return err
-			}
-			log.Infof("%v/%v is not found: %v", unstruct.GetNamespace(), unstruct.GetName(), err)
-			exists = false
-		}
-
-		switch state {
-		case common.StateDeleted:
-			if !exists {
-				log.Infof("%v/%v is deleted", unstruct.GetNamespace(), unstruct.GetName())
-				return nil
-			}
-		case common.StateCreated:
-			if exists {
-				log.Infof("%v/%v is created", unstruct.GetNamespace(), unstruct.GetName())
-				return nil
-			}
-		}
-		counter++
-		time.Sleep(w.GetInterval())
-	}
+	return waitForResources(w, resources, quantifier, "waiter timed out waiting for resource state", func(resource unstructuredResource) (bool, error) {
+		return isResourceInState(dynamicClient, resource, resolveNamespace(resource, namespace), state)
+	})
 }
 
 func ResourceShouldConvergeToField(dynamicClient dynamic.Interface, resource unstructuredResource, w common.WaiterConfig, selector string) error {
-	var counter int
+	return ResourceShouldConvergeToFieldInNamespace(dynamicClient, resource, w, "", selector)
+}
+
+func ResourceShouldConvergeToFieldInNamespace(dynamicClient dynamic.Interface, resource unstructuredResource, w common.WaiterConfig, namespace, selector string) error {
+	return ResourcesShouldConvergeToFieldInNamespace(dynamicClient, []unstructuredResource{resource}, w, common.QuantifierAll, namespace, selector)
+}
 
+func ResourcesShouldConvergeToFieldInNamespace(dynamicClient dynamic.Interface, resources []unstructuredResource, w common.WaiterConfig, quantifier, namespace, selector string) error {
 	if err := validateDynamicClient(dynamicClient); err != nil {
 		return err
 	}
 
-	split := util.DeleteEmpty(strings.Split(selector, "="))
-	if len(split) != 2 {
-		return errors.Errorf("Selector '%s' should meet format '<key>=<value>'", selector)
-	}
-
-	key := split[0]
-	value := split[1]
-
-	keySlice := util.DeleteEmpty(strings.Split(key, "."))
-	if len(keySlice) < 1 {
-		return errors.Errorf("Found empty 'key' in selector '%s' of form '<key>=<value>'", selector)
-	}
-
-	gvr, unstruct := resource.GVR, resource.Resource
-
-	for {
-		if counter >= w.GetTries() {
-			return errors.New("waiter timed out waiting for resource")
-		}
-		log.Infof("waiting for resource %v/%v to converge to %v=%v", unstruct.GetNamespace(), unstruct.GetName(), key, value)
-		retResource, err := dynamicClient.Resource(gvr.Resource).Namespace(unstruct.GetNamespace()).Get(context.Background(), unstruct.GetName(), metav1.GetOptions{})
-		if err != nil {
-			return err
-		}
-
-		val, err := util.ExtractField(retResource.UnstructuredContent(), keySlice)
-		if err != nil {
-			return err
-		}
-		var convertedValue any
-		switch val.(type) {
-		case int, int64:
-			convertedValue, err = strconv.ParseInt(value, 10, 64)
-			if err != nil {
-				return err
-			}
-		case string:
-			convertedValue = value
-		default:
-			return errors.New("unknown type")
-		}
-		if reflect.DeepEqual(val, convertedValue) {
-			break
-		}
-		counter++
-		time.Sleep(w.GetInterval())
+	key, keySlice, value, err := parseFieldSelector(selector)
+	if err != nil {
+		return err
 	}
 
-	return nil
+	return waitForResources(w, resources, quantifier, "waiter timed out waiting for resource", func(resource unstructuredResource) (bool, error) {
+		return isResourceFieldEqual(dynamicClient, resource, resolveNamespace(resource, namespace), key, keySlice, value)
+	})
 }
 
 func ResourceShouldConvergeToSelector(dynamicClient dynamic.Interface, resource unstructuredResource, w common.WaiterConfig, selector string) error {
-	var counter int
+	return ResourceShouldConvergeToSelectorInNamespace(dynamicClient, resource, w, "", selector)
+}
 
+func ResourceShouldConvergeToSelectorInNamespace(dynamicClient dynamic.Interface, resource unstructuredResource, w common.WaiterConfig, namespace, selector string) error {
+	return ResourcesShouldConvergeToSelectorInNamespace(dynamicClient, []unstructuredResource{resource}, w, common.QuantifierAll, namespace, selector)
+}
+
+func ResourcesShouldConvergeToSelectorInNamespace(dynamicClient dynamic.Interface, resources []unstructuredResource, w common.WaiterConfig, quantifier, namespace, selector string) error {
 	if err := validateDynamicClient(dynamicClient); err != nil {
 		return err
 	}
 
-	split := util.DeleteEmpty(strings.Split(selector, "="))
-	if len(split) != 2 {
-		return errors.Errorf("Selector '%s' should meet format '<key>=<value>'", selector)
-	}
-
-	key := split[0]
-	value := split[1]
-
-	keySlice := util.DeleteEmpty(strings.Split(key, "."))
-	if len(keySlice) < 1 {
-		return errors.Errorf("Found empty 'key' in selector '%s' of form '<key>=<value>'", selector)
+	key, keySlice, value, err := parseFieldSelector(selector)
+	if err != nil {
+		return err
 	}
 
-	gvr, unstruct := resource.GVR, resource.Resource
-
-	for {
-		if counter >= w.GetTries() {
-			return errors.New("waiter timed out waiting for resource")
-		}
-		log.Infof("waiting for resource %v/%v to converge to %v=%v", unstruct.GetNamespace(), unstruct.GetName(), key, value)
-		retResource, err := dynamicClient.Resource(gvr.Resource).Namespace(unstruct.GetNamespace()).Get(context.Background(), unstruct.GetName(), metav1.GetOptions{})
-		if err != nil {
-			return err
-		}
+	return waitForResources(w, resources, quantifier, "waiter timed out waiting for resource", func(resource unstructuredResource) (bool, error) {
+		return isResourceStringFieldEqual(dynamicClient, resource, resolveNamespace(resource, namespace), key, keySlice, value)
+	})
+}
 
-		if val, ok, err := unstructured.NestedString(retResource.UnstructuredContent(), keySlice...); ok {
-			if err != nil {
-				return err
-			}
-			if strings.EqualFold(val, value) {
-				break
-			}
-		}
-		counter++
-		time.Sleep(w.GetInterval())
-	}
+func ResourceConditionShouldBe(dynamicClient dynamic.Interface, resource unstructuredResource, w common.WaiterConfig, conditionType, conditionValue string) error {
+	return ResourceConditionShouldBeInNamespace(dynamicClient, resource, w, "", conditionType, conditionValue)
+}
 
-	return nil
+func ResourceConditionShouldBeInNamespace(dynamicClient dynamic.Interface, resource unstructuredResource, w common.WaiterConfig, namespace, conditionType, conditionValue string) error {
+	return ResourcesConditionShouldBeInNamespace(dynamicClient, []unstructuredResource{resource}, w, common.QuantifierAll, namespace, conditionType, conditionValue)
 }
 
-func ResourceConditionShouldBe(dynamicClient dynamic.Interface, resource unstructuredResource, w common.WaiterConfig, conditionType, conditionValue string) error {
-	var (
-		counter        int
-		expectedStatus = cases.Title(language.English).String(conditionValue)
-	)
+func ResourcesConditionShouldBeInNamespace(dynamicClient dynamic.Interface, resources []unstructuredResource, w common.WaiterConfig, quantifier, namespace, conditionType, conditionValue string) error {
+	expectedStatus := cases.Title(language.English).String(conditionValue)
 
 	if err := validateDynamicClient(dynamicClient); err != nil {
 		return err
 	}
 
-	gvr, unstruct := resource.GVR, resource.Resource
-
-	for {
-		if counter >= w.GetTries() {
-			return errors.New("waiter timed out waiting for resource state")
-		}
-		log.Infof("waiting for resource %v/%v to meet condition %v=%v", unstruct.GetNamespace(), unstruct.GetName(), conditionType, expectedStatus)
-		cr, err := dynamicClient.Resource(gvr.Resource).Namespace(unstruct.GetNamespace()).Get(context.Background(), unstruct.GetName(), metav1.GetOptions{})
-		if err != nil {
-			return err
-		}
-
-		if conditions, ok, err := unstructured.NestedSlice(cr.UnstructuredContent(), "status", "conditions"); ok {
-			if err != nil {
-				return err
-			}
-
-			for _, c := range conditions {
-				condition, ok := c.(map[string]interface{})
-				if !ok {
-					continue
-				}
-				tp, found := condition["type"]
-				if !found {
-					continue
-				}
-				condType, ok := tp.(string)
-				if !ok {
-					continue
-				}
-				if condType == conditionType {
-					status := condition["status"].(string)
-					if corev1.ConditionStatus(status) == corev1.ConditionStatus(expectedStatus) {
-						return nil
-					}
-				}
-			}
-		}
-		counter++
-		time.Sleep(w.GetInterval())
-	}
+	return waitForResources(w, resources, quantifier, "waiter timed out waiting for resource state", func(resource unstructuredResource) (bool, error) {
+		return isResourceConditionStatus(dynamicClient, resource, resolveNamespace(resource, namespace), conditionType, expectedStatus)
+	})
 }
 
 func UpdateResourceWithField(dynamicClient dynamic.Interface, resource unstructuredResource, key string, value string) error {
+	return UpdateResourceWithFieldInNamespace(dynamicClient, resource, "", key, value)
+}
+
+func UpdateResourceWithFieldInNamespace(dynamicClient dynamic.Interface, resource unstructuredResource, namespace, key, value string) error {
 	var (
 		keySlice     = util.DeleteEmpty(strings.Split(key, "."))
 		overrideType bool
@@ -367,6 +249,7 @@ func UpdateResourceWithField(dynamicClient dynamic.Interface, resource unstructu
 	}
 
 	gvr, unstruct := resource.GVR, resource.Resource
+	namespace = resolveNamespace(resource, namespace)
 
 	n, err := strconv.ParseInt(value, 10, 64)
 	if err == nil {
@@ -374,7 +257,7 @@ func UpdateResourceWithField(dynamicClient dynamic.Interface, resource unstructu
 		intValue = n
 	}
 
-	updateTarget, err := dynamicClient.Resource(gvr.Resource).Namespace(unstruct.GetNamespace()).Get(context.Background(), unstruct.GetName(), metav1.GetOptions{})
+	updateTarget, err := dynamicClient.Resource(gvr.Resource).Namespace(namespace).Get(context.Background(), unstruct.GetName(), metav1.GetOptions{})
 	if err != nil {
 		return err
 	}
@@ -390,7 +273,7 @@ func UpdateResourceWithField(dynamicClient dynamic.Interface, resource unstructu
 		}
 	}
 
-	_, err = dynamicClient.Resource(gvr.Resource).Namespace(unstruct.GetNamespace()).Update(context.Background(), updateTarget, metav1.UpdateOptions{})
+	_, err = dynamicClient.Resource(gvr.Resource).Namespace(namespace).Update(context.Background(), updateTarget, metav1.UpdateOptions{})
 	if err != nil {
 		return err
 	}
@@ -478,6 +361,115 @@ func DeleteResourcesAtPath(dynamicClient dynamic.Interface, dc discovery.Discove
 	return nil
 }
 
+// ResourcesAtPathOperation applies the operation to the manifests of every .yaml, .yml and .json file under the path,
+// ordered by kind and waiting for CustomResourceDefinitions to be established before the resources following them,
+// deletion happens in reverse order and waits for all resources to be deleted
+func ResourcesAtPathOperation(dynamicClient dynamic.Interface, dc discovery.DiscoveryInterface, TemplateArguments interface{}, w common.WaiterConfig, resourcesPath, operation, defaultNamespace string) error {
+	if err := validateDynamicClient(dynamicClient); err != nil {
+		return err
+	}
+
+	manifests, err := getManifestsAtPath(TemplateArguments, resourcesPath)
+	if err != nil {
+		return err
+	}
+
+	if operation == common.OperationDelete {
+		return deleteManifests(dynamicClient, dc, w, manifests, defaultNamespace)
+	}
+	return applyManifests(dynamicClient, dc, w, manifests, operation, defaultNamespace)
+}
+
+func applyManifests(dynamicClient dynamic.Interface, dc discovery.DiscoveryInterface, w common.WaiterConfig, manifests []*unstructured.Unstructured, operation, defaultNamespace string) error {
+	var definitions []unstructuredResource
+
+	sortByInstallOrder(manifests)
+	for _, manifest := range manifests {
+		isDefinition := manifest.GetKind() == kindCustomResourceDefinition
+		if !isDefinition && len(definitions) > 0 {
+			if err := waitForCustomResourceDefinitions(dynamicClient, w, definitions); err != nil {
+				return err
+			}
+			definitions = nil
+		}
+
+		resource, err := getResourceForManifest(manifest, dc)
+		if err != nil {
+			return err
+		}
+		SetDefaultNamespace(resource, defaultNamespace)
+		if err := ResourceOperationInNamespace(dynamicClient, resource, operation, ""); err != nil {
+			return err
+		}
+		if isDefinition {
+			definitions = append(definitions, resource)
+		}
+	}
+
+	if len(definitions) > 0 {
+		return waitForCustomResourceDefinitions(dynamicClient, w, definitions)
+	}
+	return nil
+}
+
+func deleteManifests(dynamicClient dynamic.Interface, dc discovery.DiscoveryInterface, w common.WaiterConfig, manifests []*unstructured.Unstructured, defaultNamespace string) error {
+	var deleted []unstructuredResource
+
+	sortByUninstallOrder(manifests)
+	for _, manifest := range manifests {
+		resource, err := getResourceForManifest(manifest, dc)
+		if err != nil {
+			// the kind is no longer served once its CustomResourceDefinition is deleted
+			if meta.IsNoMatchError(err) {
+				log.Infof("%s %s already deleted", manifest.GetKind(), manifest.GetName())
+				continue
+			}
+			return err
+		}
+		SetDefaultNamespace(resource, defaultNamespace)
+		if err := ResourceOperationInNamespace(dynamicClient, resource, common.OperationDelete, ""); err != nil {
+			return err
+		}
+		deleted = append(deleted, resource)
+	}
+
+	if len(deleted) == 0 {
+		return nil
+	}
+	return waitForResources(w, deleted, common.QuantifierAll, "waiter timed out waiting for deletion", func(resource unstructuredResource) (bool, error) {
+		return isResourceInState(dynamicClient, resource, resource.Resource.GetNamespace(), common.StateDeleted)
+	})
+}
+
+func waitForCustomResourceDefinitions(dynamicClient dynamic.Interface, w common.WaiterConfig, definitions []unstructuredResource) error {
+	return waitForResources(w, definitions, common.QuantifierAll, "waiter timed out waiting for CustomResourceDefinitions to be established", func(resource unstructuredResource) (bool, error) {
+		for _, conditionType := range []string{customResourceDefinitionEstablished, customResourceDefinitionNamesAccepted} {
+			ok, err := isResourceConditionStatus(dynamicClient, resource, "", conditionType, "True")
+			if kerrors.IsNotFound(err) {
+				// not created yet, e.g. by an operator installing it
+				return false, nil
+			}
+			if err != nil || !ok {
+				return false, err
+			}
+		}
+		return true, nil
+	})
+}
+
+// CustomResourceDefinitionShouldBeEstablished waits for the CustomResourceDefinition's names to be accepted and for it
+// to be established, so its kind is served
+func CustomResourceDefinitionShouldBeEstablished(dynamicClient dynamic.Interface, w common.WaiterConfig, name string) error {
+	if err := validateDynamicClient(dynamicClient); err != nil {
+		return err
+	}
+
+	definition := &unstructured.Unstructured{}
+	definition.SetGroupVersionKind(customResourceDefinitionMapping.GroupVersionKind)
+	definition.SetName(name)
+	return waitForCustomResourceDefinitions(dynamicClient, w, []unstructuredResource{{GVR: customResourceDefinitionMapping, Resource: definition}})
+}
+
 func VerifyInstanceGroups(dynamicClient dynamic.Interface) error {
 	igs, err := GetInstanceGroupList(dynamicClient)
 	if err != nil {
@@ -498,3 +490,123 @@ func VerifyInstanceGroups(dynamicClient dynamic.Interface) error {
 
 	return nil
 }
+
+func DeleteResourcesWithSelector(dynamicClient dynamic.Interface, mapping *meta.RESTMapping, namespace, selectorType, selector string) error {
+	resources, err := GetResourceListWithSelector(dynamicClient, mapping, namespace, selectorType, selector)
+	if err != nil {
+		return err
+	}
+
+	if len(resources.Items) == 0 {
+		log.Infof("no %s matched %s '%s' in namespace %s", mapping.Resource.Resource, selectorType, selector, namespace)
+		return nil
+	}
+
+	for _, resource := range resources.Items {
+		err := dynamicClient.Resource(mapping.Resource).Namespace(resource.GetNamespace()).Delete(context.Background(), resource.GetName(), metav1.DeleteOptions{})
+		if err != nil {
+			if kerrors.IsNotFound(err) {
+				log.Infof("%s %s already deleted", resource.GetKind(), resource.GetName())
+				continue
+			}
+			return err
+		}
+		log.Infof("%s %s has been deleted from namespace %s", resource.GetKind(), resource.GetName(), resource.GetNamespace())
+	}
+	return nil
+}
+
+func ResourcesWithSelectorCountShouldBe(dynamicClient dynamic.Interface, mapping *meta.RESTMapping, namespace, selectorType, selector string, expectedCount int) error {
+	resources, err := GetResourceListWithSelector(dynamicClient, mapping, namespace, selectorType, selector)
+	if err != nil {
+		return err
+	}
+
+	count := len(resources.Items)
+	if count != expectedCount {
+		return errors.Errorf("expected %d %s with %s '%s' in namespace %s, but found %d", expectedCount, mapping.Resource.Resource, selectorType, selector, namespace, count)
+	}
+	log.Infof("found %d %s with %s '%s' in namespace %s", count, mapping.Resource.Resource, selectorType, selector, namespace)
+	return nil
+}
+
+func ResourcesWithSelectorShouldReachCount(dynamicClient dynamic.Interface, mapping *meta.RESTMapping, w common.WaiterConfig, namespace, selectorType, selector, comparison string, expectedCount int) error {
+	description := fmt.Sprintf("%s with %s '%s'", mapping.Resource.Resource, selectorType, selector)
+	return common.WaitForCount(w, comparison, expectedCount, description, func() (int, error) {
+		resources, err := GetResourceListWithSelector(dynamicClient, mapping, namespace, selectorType, selector)
+		if err != nil {
+			return 0, err
+		}
+		return len(resources.Items), nil
+	})
+}
+
+func ResourcesWithSelectorShouldConvergeToField(dynamicClient dynamic.Interface, mapping *meta.RESTMapping, w common.WaiterConfig, namespace, selectorType, selector, fieldSelector string) error {
+	key, keySlice, value, err := parseFieldSelector(fieldSelector)
+	if err != nil {
+		return err
+	}
+
+	description := fmt.Sprintf("%s with %s '%s' in namespace %s not converged to %v=%v", mapping.Resource.Resource, selectorType, selector, namespace, key, value)
+	return common.WaitForCount(w, common.ComparisonExactly, 0, description, func() (int, error) {
+		resources, err := GetResourceListWithSelector(dynamicClient, mapping, namespace, selectorType, selector)
+		if err != nil {
+			return 0, err
+		}
+		// no resources found yet is not converged
+		if len(resources.Items) == 0 {
+			return 1, nil
+		}
+		var notConverged int
+		for _, resource := range resources.Items {
+			converged, err := isFieldEqual(resource.UnstructuredContent(), keySlice, value)
+			if err != nil {
+				return 0, err
+			}
+			if !converged {
+				notConverged++
+			}
+		}
+		return notConverged, nil
+	})
+}
+
+func ResourceShouldHaveMetadata(dynamicClient dynamic.Interface, mapping *meta.RESTMapping, name, namespace, metadataType, expected string) error {
+	if err := validateDynamicClient(dynamicClient); err != nil {
+		return err
+	}
+
+	if mapping.Scope != nil && mapping.Scope.Name() == meta.RESTScopeNameRoot {
+		namespace = ""
+	}
+
+	resource, err := dynamicClient.Resource(mapping.Resource).Namespace(namespace).Get(context.Background(), name, metav1.GetOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "failed to get %s %s", mapping.Resource.Resource, name)
+	}
+
+	if err := validateMetadata(*resource, metadataType, expected); err != nil {
+		return err
+	}
+	log.Infof("%s of %s %s match '%s'", metadataType, mapping.Resource.Resource, name, expected)
+	return nil
+}
+
+func ResourcesWithSelectorShouldHaveMetadata(dynamicClient dynamic.Interface, mapping *meta.RESTMapping, namespace, selectorType, selector, metadataType, expected string) error {
+	resources, err := GetResourceListWithSelector(dynamicClient, mapping, namespace, selectorType, selector)
+	if err != nil {
+		return err
+	}
+
+	if len(resources.Items) == 0 {
+		return errors.Errorf("no %s matched %s '%s' in namespace %s", mapping.Resource.Resource, selectorType, selector, namespace)
+	}
+
+	for _, resource := range resources.Items {
+		if err := validateMetadata(resource, metadataType, expected); err != nil {
+			return err
+		}
+	}
+	log.Infof("%s of %d %s with %s '%s' match '%s'", metadataType, len(resources.Items), mapping.Resource.Resource, selectorType, selector, expected)
+	return nil
+}
@@ -15,18 +15,31 @@ limitations under the License.
 package unstructured
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"fmt"
-	"html/template"
+	"io"
 	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/keikoproj/kubedog/internal/util"
+	"github.com/keikoproj/kubedog/pkg/kube/common"
 	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	serializer "k8s.io/apimachinery/pkg/runtime/serializer/yaml"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/discovery/cached/memory"
 	"k8s.io/client-go/dynamic"
@@ -34,15 +47,99 @@ import (
 )
 
 const (
-	yamlSeparator = "\n---"
-	trimTokens    = "\n "
+	selectorTypeDefault = "selector"
+	selectorTypeLabel   = "label selector"
+	selectorTypeField   = "field selector"
+
+	metadataLabels      = "labels"
+	metadataAnnotations = "annotations"
+
+	kindCustomResourceDefinition          = "CustomResourceDefinition"
+	customResourceDefinitionEstablished   = "Established"
+	customResourceDefinitionNamesAccepted = "NamesAccepted"
 )
 
+var customResourceDefinitionMapping = &meta.RESTMapping{
+	Resource:         schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"},
+	GroupVersionKind: schema.GroupVersionKind{Group: "apiextensions.k8s.io", Version: "v1", Kind: kindCustomResourceDefinition},
+	Scope:            meta.RESTScopeRoot,
+}
+
+// installOrder is the order in which kinds are applied from a directory, the same as Helm's, kinds not listed (e.g.
+// custom resources) are applied last and deletion happens in reverse
+var installOrder = []string{
+	"PriorityClass",
+	"Namespace",
+	"NetworkPolicy",
+	"ResourceQuota",
+	"LimitRange",
+	"PodSecurityPolicy",
+	"PodDisruptionBudget",
+	"ServiceAccount",
+	"Secret",
+	"SecretList",
+	"ConfigMap",
+	"StorageClass",
+	"PersistentVolume",
+	"PersistentVolumeClaim",
+	kindCustomResourceDefinition,
+	"ClusterRole",
+	"ClusterRoleList",
+	"ClusterRoleBinding",
+	"ClusterRoleBindingList",
+	"Role",
+	"RoleList",
+	"RoleBinding",
+	"RoleBindingList",
+	"Service",
+	"DaemonSet",
+	"Pod",
+	"ReplicationController",
+	"ReplicaSet",
+	"Deployment",
+	"HorizontalPodAutoscaler",
+	"StatefulSet",
+	"Job",
+	"CronJob",
+	"IngressClass",
+	"Ingress",
+	"APIService",
+}
+
+var manifestExtensions = []string{".yaml", ".yml", ".json"}
+
 type unstructuredResource struct {
 	GVR      *meta.RESTMapping
 	Resource *unstructured.Unstructured
 }
 
+// Resource is a decoded manifest along with its REST mapping, as returned by GetResource and GetResources
+type Resource = unstructuredResource
+
+// CachedDiscoveryClient caches discovery and REST mappings across calls, the mappings are reset when a kind is not
+// found since it may be served by a CustomResourceDefinition created after they were cached
+type CachedDiscoveryClient struct {
+	discovery.CachedDiscoveryInterface
+	mapper *restmapper.DeferredDiscoveryRESTMapper
+}
+
+// NewCachedDiscoveryClient caches the discovery client in memory, unless it is already cached, e.g. on disk
+func NewCachedDiscoveryClient(dc discovery.DiscoveryInterface) *CachedDiscoveryClient {
+	cachedDiscoveryClient, ok := dc.(discovery.CachedDiscoveryInterface)
+	if !ok {
+		cachedDiscoveryClient = memory.NewMemCacheClient(dc)
+	}
+	return &CachedDiscoveryClient{
+		CachedDiscoveryInterface: cachedDiscoveryClient,
+		mapper:                   restmapper.NewDeferredDiscoveryRESTMapper(cachedDiscoveryClient),
+	}
+}
+
+// Reset invalidates the cached discovery and REST mappings
+func (c *CachedDiscoveryClient) Reset() {
+	c.mapper.Reset()
+}
+
 func GetResource(dc discovery.DiscoveryInterface, TemplateArguments interface{}, resourceFilePath string) (unstructuredResource, error) {
 	data, err := os.ReadFile(resourceFilePath)
 	if err != nil {
@@ -56,19 +153,41 @@ func GetResources(dc discovery.DiscoveryInterface, TemplateArguments interface{}
 	if err != nil {
 		return nil, err
 	}
-	manifests := bytes.Split(data, []byte(yamlSeparator))
+	rendered, err := common.RenderTemplate("Resource", string(data), TemplateArguments)
+	if err != nil {
+		return nil, err
+	}
+	manifests, err := splitManifests(rendered)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed splitting manifests of '%s'", resourcesFilePath)
+	}
 	resourceList := make([]unstructuredResource, 0)
 	for _, manifest := range manifests {
-		if len(bytes.Trim(manifest, trimTokens)) == 0 {
-			continue
-		}
-		resource, err := getResourceFromString(string(manifest), dc, TemplateArguments)
+		resource, err := decodeResource(manifest, dc)
 		if err != nil {
 			return nil, err
 		}
 		resourceList = append(resourceList, resource)
 	}
-	return resourceList, err
+	return resourceList, nil
+}
+
+// SetDefaultNamespace sets the namespace of namespaced resources whose manifest does not specify one
+func SetDefaultNamespace(resource unstructuredResource, namespace string) {
+	if resource.GVR == nil || resource.GVR.Scope == nil || resource.Resource == nil {
+		return
+	}
+	if resource.GVR.Scope.Name() == meta.RESTScopeNameNamespace && resource.Resource.GetNamespace() == "" {
+		resource.Resource.SetNamespace(namespace)
+	}
+}
+
+// resolveNamespace returns the namespace overriding the manifest's, if any, otherwise the manifest's namespace
+func resolveNamespace(resource unstructuredResource, namespace string) string {
+	if namespace == "" {
+		return resource.Resource.GetNamespace()
+	}
+	return namespace
 }
 
 func GetInstanceGroupList(dynamicClient dynamic.Interface) (*unstructured.UnstructuredList, error) {
@@ -90,6 +209,41 @@ func GetInstanceGroupList(dynamicClient dynamic.Interface) (*unstructured.Unstru
 	return igs, nil
 }
 
+func GetResourceListWithSelector(dynamicClient dynamic.Interface, mapping *meta.RESTMapping, namespace, selectorType, selector string) (*unstructured.UnstructuredList, error) {
+	if err := validateDynamicClient(dynamicClient); err != nil {
+		return nil, err
+	}
+
+	listOptions, err := getListOptions(selectorType, selector)
+	if err != nil {
+		return nil, err
+	}
+
+	if mapping.Scope != nil && mapping.Scope.Name() == meta.RESTScopeNameRoot {
+		namespace = ""
+	}
+
+	resources, err := dynamicClient.Resource(mapping.Resource).Namespace(namespace).List(context.Background(), listOptions)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list %s", mapping.Resource.Resource)
+	}
+	return resources, nil
+}
+
+func GetResourceMapping(dc discovery.DiscoveryInterface, kind string) (*meta.RESTMapping, error) {
+	if dc == nil {
+		return nil, errors.Errorf("'k8s.io/client-go/discovery.DiscoveryInterface' is nil.")
+	}
+
+	return getMappingWithReset(dc, func(mapper meta.RESTMapper) (*meta.RESTMapping, error) {
+		gvk, err := mapper.KindFor(schema.ParseGroupResource(kind).WithVersion(""))
+		if err != nil {
+			return nil, err
+		}
+		return mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	})
+}
+
 func validateDynamicClient(dynamicClient dynamic.Interface) error {
 	if dynamicClient == nil {
 		return errors.Errorf("'k8s.io/client-go/dynamic.Interface' is nil.")
@@ -98,33 +252,202 @@ func validateDynamicClient(dynamicClient dynamic.Interface) error {
 }
 
 func getResourceFromString(resourceString string, dc discovery.DiscoveryInterface, args interface{}) (unstructuredResource, error) {
-	resource := &unstructured.Unstructured{}
-	var renderBuffer bytes.Buffer
+	rendered, err := common.RenderTemplate("Resource", resourceString, args)
+	if err != nil {
+		return unstructuredResource{GVR: nil, Resource: &unstructured.Unstructured{}}, err
+	}
+	return decodeResource(rendered, dc)
+}
 
-	if args != nil {
-		template, err := template.New("Resource").Parse(resourceString)
+// splitManifests splits a YAML stream on its document separators, only '---' lines are separators so block scalars
+// containing them are kept whole, documents with nothing but comments or whitespace are skipped
+func splitManifests(data []byte) ([][]byte, error) {
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(data)))
+	manifests := make([][]byte, 0)
+	for {
+		manifest, err := reader.Read()
+		if err == io.EOF {
+			return manifests, nil
+		}
 		if err != nil {
-			return unstructuredResource{GVR: nil, Resource: resource}, err
+			return nil, err
+		}
+		if isEmptyManifest(manifest) {
+			continue
 		}
+		manifests = append(manifests, manifest)
+	}
+}
 
-		err = template.Execute(&renderBuffer, &args)
-		if err != nil {
-			return unstructuredResource{GVR: nil, Resource: resource}, err
+func isEmptyManifest(manifest []byte) bool {
+	for _, line := range strings.Split(string(manifest), "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "#") && !strings.HasPrefix(line, "---") {
+			return false
 		}
-	} else {
-		renderBuffer.WriteString(resourceString)
 	}
+	return true
+}
 
-	dec := serializer.NewDecodingSerializer(unstructured.UnstructuredJSONScheme)
-	_, gvk, err := dec.Decode(renderBuffer.Bytes(), nil, resource)
+func decodeResource(manifest []byte, dc discovery.DiscoveryInterface) (unstructuredResource, error) {
+	resource, err := decodeManifest(manifest)
 	if err != nil {
 		return unstructuredResource{GVR: nil, Resource: resource}, err
 	}
-	gvr, err := getGVR(gvk, dc)
+	return getResourceForManifest(resource, dc)
+}
+
+func decodeManifest(manifest []byte) (*unstructured.Unstructured, error) {
+	resource := &unstructured.Unstructured{}
+	dec := serializer.NewDecodingSerializer(unstructured.UnstructuredJSONScheme)
+	_, _, err := dec.Decode(manifest, nil, resource)
+	return resource, err
+}
+
+func getResourceForManifest(manifest *unstructured.Unstructured, dc discovery.DiscoveryInterface) (unstructuredResource, error) {
+	gvk := manifest.GroupVersionKind()
+	gvr, err := getGVR(&gvk, dc)
 	if err != nil {
-		return unstructuredResource{GVR: nil, Resource: resource}, err
+		return unstructuredResource{GVR: nil, Resource: manifest}, err
+	}
+	return unstructuredResource{GVR: gvr, Resource: manifest}, nil
+}
+
+// getManifestsAtPath decodes the manifests of every .yaml, .yml and .json file under the path, without mapping them
+// since the kinds of custom resources are not served until their definitions are applied
+func getManifestsAtPath(TemplateArguments interface{}, resourcesPath string) ([]*unstructured.Unstructured, error) {
+	manifests := make([]*unstructured.Unstructured, 0)
+	walkFn := func(path string, info os.FileInfo, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+		if info.IsDir() || !isManifestFile(path) {
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rendered, err := common.RenderTemplate("Resource", string(data), TemplateArguments)
+		if err != nil {
+			return errors.Wrapf(err, "failed rendering '%s'", path)
+		}
+		documents, err := splitManifests(rendered)
+		if err != nil {
+			return errors.Wrapf(err, "failed splitting manifests of '%s'", path)
+		}
+		for _, document := range documents {
+			manifest, err := decodeManifest(document)
+			if err != nil {
+				return errors.Wrapf(err, "failed decoding manifest of '%s'", path)
+			}
+			manifests = append(manifests, manifest)
+		}
+		return nil
+	}
+	if err := filepath.Walk(resourcesPath, walkFn); err != nil {
+		return nil, err
+	}
+	return manifests, nil
+}
+
+func isManifestFile(path string) bool {
+	extension := strings.ToLower(filepath.Ext(path))
+	for _, manifestExtension := range manifestExtensions {
+		if extension == manifestExtension {
+			return true
+		}
+	}
+	return false
+}
+
+func getInstallRank(kind string) int {
+	for rank, installKind := range installOrder {
+		if kind == installKind {
+			return rank
+		}
 	}
-	return unstructuredResource{GVR: gvr, Resource: resource}, err
+	return len(installOrder)
+}
+
+// sortByInstallOrder orders the manifests by kind, keeping the order they were read in for the same kind
+func sortByInstallOrder(manifests []*unstructured.Unstructured) {
+	sort.SliceStable(manifests, func(i, j int) bool {
+		return getInstallRank(manifests[i].GetKind()) < getInstallRank(manifests[j].GetKind())
+	})
+}
+
+// sortByUninstallOrder orders the manifests in the reverse of the install order
+func sortByUninstallOrder(manifests []*unstructured.Unstructured) {
+	sortByInstallOrder(manifests)
+	for i, j := 0, len(manifests)-1; i < j; i, j = i+1, j-1 {
+		manifests[i], manifests[j] = manifests[j], manifests[i]
+	}
+}
+
+func getListOptions(selectorType, selector string) (metav1.ListOptions, error) {
+	switch selectorType {
+	case selectorTypeDefault, selectorTypeLabel:
+		return metav1.ListOptions{LabelSelector: selector}, nil
+	case selectorTypeField:
+		return metav1.ListOptions{FieldSelector: selector}, nil
+	default:
+		return metav1.ListOptions{}, errors.Errorf("unsupported selector type: '%s'", selectorType)
+	}
+}
+
+func parseFieldSelector(selector string) (string, []string, string, error) {
+	split := util.DeleteEmpty(strings.Split(selector, "="))
+	if len(split) != 2 {
+		return "", nil, "", errors.Errorf("Selector '%s' should meet format '<key>=<value>'", selector)
+	}
+
+	key := split[0]
+	value := split[1]
+
+	keySlice := util.DeleteEmpty(strings.Split(key, "."))
+	if len(keySlice) < 1 {
+		return "", nil, "", errors.Errorf("Found empty 'key' in selector '%s' of form '<key>=<value>'", selector)
+	}
+	return key, keySlice, value, nil
+}
+
+// isFieldEqual compares the field with the value converted to the field's type, a field that is not found, e.g. a
+// status that is not reported yet, is not equal
+func isFieldEqual(content map[string]interface{}, keySlice []string, value string) (bool, error) {
+	val, err := util.ExtractField(content, keySlice)
+	if err != nil {
+		if errors.Is(err, util.ErrFieldNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	var convertedValue any
+	switch val.(type) {
+	case nil:
+		return false, nil
+	case int, int64:
+		convertedValue, err = strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return false, err
+		}
+	case float64:
+		convertedValue, err = strconv.ParseFloat(value, 64)
+		if err != nil {
+			return false, err
+		}
+	case bool:
+		convertedValue, err = strconv.ParseBool(value)
+		if err != nil {
+			return false, err
+		}
+	case string:
+		convertedValue = value
+	default:
+		return false, errors.Errorf("unsupported type '%T' of field '%s'", val, strings.Join(keySlice, "."))
+	}
+	return reflect.DeepEqual(val, convertedValue), nil
 }
 
 func getGVR(gvk *schema.GroupVersionKind, dc discovery.DiscoveryInterface) (*meta.RESTMapping, error) {
@@ -132,11 +455,168 @@ func getGVR(gvk *schema.GroupVersionKind, dc discovery.DiscoveryInterface) (*met
 		return nil, errors.Errorf("'k8s.io/client-go/discovery.DiscoveryInterface' is nil.")
 	}
 
-	CachedDiscoveryInterface := memory.NewMemCacheClient(dc)
-	DeferredDiscoveryRESTMapper := restmapper.NewDeferredDiscoveryRESTMapper(CachedDiscoveryInterface)
-	RESTMapping, err := DeferredDiscoveryRESTMapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	return getMappingWithReset(dc, func(mapper meta.RESTMapper) (*meta.RESTMapping, error) {
+		return mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	})
+}
+
+// getMappingWithReset uses the mapper cached by the discovery client, if any, and retries with reset mappings when the
+// kind is not found, otherwise a new mapper is used
+func getMappingWithReset(dc discovery.DiscoveryInterface, getMapping func(mapper meta.RESTMapper) (*meta.RESTMapping, error)) (*meta.RESTMapping, error) {
+	cachedDiscoveryClient, ok := dc.(*CachedDiscoveryClient)
+	if !ok {
+		return getMapping(restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(dc)))
+	}
+
+	mapping, err := getMapping(cachedDiscoveryClient.mapper)
+	if meta.IsNoMatchError(err) {
+		log.Infof("resetting cached REST mappings: %v", err)
+		cachedDiscoveryClient.Reset()
+		return getMapping(cachedDiscoveryClient.mapper)
+	}
+	return mapping, err
+}
+
+// waitForResources waits until the check is satisfied by all, or any, of the resources depending on the quantifier
+func waitForResources(w common.WaiterConfig, resources []unstructuredResource, quantifier, timeoutMessage string, check func(resource unstructuredResource) (bool, error)) error {
+	var counter int
+
+	if quantifier != common.QuantifierAll && quantifier != common.QuantifierAny {
+		return errors.Errorf("unsupported quantifier: '%s'", quantifier)
+	}
+	if len(resources) == 0 {
+		return errors.New("no resources found in manifest")
+	}
+
+	for {
+		if counter >= w.GetTries() {
+			return errors.New(timeoutMessage)
+		}
+		var satisfied int
+		for _, resource := range resources {
+			ok, err := check(resource)
+			if err != nil {
+				return err
+			}
+			if ok {
+				satisfied++
+			}
+		}
+		switch {
+		case quantifier == common.QuantifierAll && satisfied == len(resources):
+			return nil
+		case quantifier == common.QuantifierAny && satisfied > 0:
+			return nil
+		}
+		counter++
+		time.Sleep(w.GetInterval())
+	}
+}
+
+func isResourceInState(dynamicClient dynamic.Interface, resource unstructuredResource, namespace, state string) (bool, error) {
+	gvr, unstruct := resource.GVR, resource.Resource
+	log.Infof("waiting for resource %v/%v to become %v", namespace, unstruct.GetName(), state)
+
+	exists := true
+	_, err := dynamicClient.Resource(gvr.Resource).Namespace(namespace).Get(context.Background(), unstruct.GetName(), metav1.GetOptions{})
 	if err != nil {
-		return nil, err
+		if !kerrors.IsNotFound(err) {
+			return false, err
+		}
+		log.Infof("%v/%v is not found: %v", namespace, unstruct.GetName(), err)
+		exists = false
+	}
+
+	switch state {
+	case common.StateDeleted:
+		if !exists {
+			log.Infof("%v/%v is deleted", namespace, unstruct.GetName())
+			return true, nil
+		}
+	case common.StateCreated:
+		if exists {
+			log.Infof("%v/%v is created", namespace, unstruct.GetName())
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func isResourceFieldEqual(dynamicClient dynamic.Interface, resource unstructuredResource, namespace, key string, keySlice []string, value string) (bool, error) {
+	gvr, unstruct := resource.GVR, resource.Resource
+	log.Infof("waiting for resource %v/%v to converge to %v=%v", namespace, unstruct.GetName(), key, value)
+	retResource, err := dynamicClient.Resource(gvr.Resource).Namespace(namespace).Get(context.Background(), unstruct.GetName(), metav1.GetOptions{})
+	if err != nil {
+		return false, err
+	}
+	return isFieldEqual(retResource.UnstructuredContent(), keySlice, value)
+}
+
+func isResourceStringFieldEqual(dynamicClient dynamic.Interface, resource unstructuredResource, namespace, key string, keySlice []string, value string) (bool, error) {
+	gvr, unstruct := resource.GVR, resource.Resource
+	log.Infof("waiting for resource %v/%v to converge to %v=%v", namespace, unstruct.GetName(), key, value)
+	retResource, err := dynamicClient.Resource(gvr.Resource).Namespace(namespace).Get(context.Background(), unstruct.GetName(), metav1.GetOptions{})
+	if err != nil {
+		return false, err
+	}
+
+	val, ok, err := unstructured.NestedString(retResource.UnstructuredContent(), keySlice...)
+	if !ok {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return strings.EqualFold(val, value), nil
+}
+
+func isResourceConditionStatus(dynamicClient dynamic.Interface, resource unstructuredResource, namespace, conditionType, expectedStatus string) (bool, error) {
+	gvr, unstruct := resource.GVR, resource.Resource
+	log.Infof("waiting for resource %v/%v to meet condition %v=%v", namespace, unstruct.GetName(), conditionType, expectedStatus)
+	cr, err := dynamicClient.Resource(gvr.Resource).Namespace(namespace).Get(context.Background(), unstruct.GetName(), metav1.GetOptions{})
+	if err != nil {
+		return false, err
+	}
+
+	conditions, ok, err := unstructured.NestedSlice(cr.UnstructuredContent(), "status", "conditions")
+	if !ok {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		condType, ok := condition["type"].(string)
+		if !ok || condType != conditionType {
+			continue
+		}
+		status, _ := condition["status"].(string)
+		if corev1.ConditionStatus(status) == corev1.ConditionStatus(expectedStatus) {
+			return true, nil
+		}
 	}
-	return RESTMapping, nil
+	return false, nil
+}
+
+// validateMetadata checks the labels or annotations of the resource satisfy the expected selector
+func validateMetadata(resource unstructured.Unstructured, metadataType, expected string) error {
+	var metadata map[string]string
+	switch metadataType {
+	case metadataLabels:
+		metadata = resource.GetLabels()
+	case metadataAnnotations:
+		metadata = resource.GetAnnotations()
+	default:
+		return errors.Errorf("unsupported metadata type: '%s'", metadataType)
+	}
+
+	if err := common.ValidateSelectorMatch(metadata, expected); err != nil {
+		return errors.Wrapf(err, "%s of %s %s do not match", metadataType, resource.GetKind(), resource.GetName())
+	}
+	return nil
 }
@@ -15,8 +15,8 @@ limitations under the License.
 package unstructured
 
 import (
-	"bytes"
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -27,6 +27,7 @@ import (
 	"github.com/keikoproj/kubedog/internal/util"
 	"github.com/keikoproj/kubedog/pkg/generic"
 	"github.com/keikoproj/kubedog/pkg/kube/common"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -34,6 +35,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	serializer "k8s.io/apimachinery/pkg/runtime/serializer/yaml"
 	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
 	fakeDiscovery "k8s.io/client-go/discovery/fake"
 	"k8s.io/client-go/dynamic"
 	fakeDynamic "k8s.io/client-go/dynamic/fake"
@@ -434,6 +436,153 @@ func TestResourceShouldBe(t *testing.T) {
 	}
 }
 
+func TestResourceShouldBeInNamespace(t *testing.T) {
+	type args struct {
+		dynamicClient dynamic.Interface
+		resource      unstructuredResource
+		w             common.WaiterConfig
+		namespace     string
+		state         string
+	}
+	resource := getResourceFromYaml(t, getFilePath("resource.yaml"))
+	resourceInNamespace := getResourceInNamespace(resource, "someOtherNamespace")
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "Positive Test: StateCreated in namespace",
+			args: args{
+				dynamicClient: newFakeDynamicClientWithResource(resourceInNamespace),
+				resource:      resource,
+				namespace:     resourceInNamespace.Resource.GetNamespace(),
+				state:         common.StateCreated,
+			},
+		},
+		{
+			name: "Positive Test: StateDeleted in namespace",
+			args: args{
+				dynamicClient: newFakeDynamicClientWithResource(resource),
+				resource:      resource,
+				namespace:     resourceInNamespace.Resource.GetNamespace(),
+				state:         common.StateDeleted,
+			},
+		},
+		{
+			name: "Negative Test: only created in manifest namespace",
+			args: args{
+				dynamicClient: newFakeDynamicClientWithResource(resource),
+				resource:      resource,
+				namespace:     resourceInNamespace.Resource.GetNamespace(),
+				state:         common.StateCreated,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.args.w = common.NewWaiterConfig(1, time.Millisecond)
+			if err := ResourceShouldBeInNamespace(tt.args.dynamicClient, tt.args.resource, tt.args.w, tt.args.namespace, tt.args.state); (err != nil) != tt.wantErr {
+				t.Errorf("ResourceShouldBeInNamespace() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestResourcesShouldBeInNamespace(t *testing.T) {
+	type args struct {
+		dynamicClient dynamic.Interface
+		resources     []unstructuredResource
+		w             common.WaiterConfig
+		quantifier    string
+		namespace     string
+		state         string
+	}
+	resources := getResourcesFromYaml(t, getFilePath("multi-resource.yaml"))
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "Positive Test: all created",
+			args: args{
+				dynamicClient: newFakeDynamicClientWithResourcesAndResourcesLists(resources...),
+				resources:     resources,
+				quantifier:    common.QuantifierAll,
+				state:         common.StateCreated,
+			},
+		},
+		{
+			name: "Positive Test: any created",
+			args: args{
+				dynamicClient: newFakeDynamicClientWithResource(resources[0]),
+				resources:     resources,
+				quantifier:    common.QuantifierAny,
+				state:         common.StateCreated,
+			},
+		},
+		{
+			name: "Positive Test: any deleted",
+			args: args{
+				dynamicClient: newFakeDynamicClientWithResource(resources[0]),
+				resources:     resources,
+				quantifier:    common.QuantifierAny,
+				state:         common.StateDeleted,
+			},
+		},
+		{
+			name: "Negative Test: not all created",
+			args: args{
+				dynamicClient: newFakeDynamicClientWithResource(resources[0]),
+				resources:     resources,
+				quantifier:    common.QuantifierAll,
+				state:         common.StateCreated,
+			},
+			wantErr: true,
+		},
+		{
+			name: "Negative Test: none created in namespace",
+			args: args{
+				dynamicClient: newFakeDynamicClientWithResourcesAndResourcesLists(resources...),
+				resources:     resources,
+				quantifier:    common.QuantifierAny,
+				namespace:     "someOtherNamespace",
+				state:         common.StateCreated,
+			},
+			wantErr: true,
+		},
+		{
+			name: "Negative Test: unsupported quantifier",
+			args: args{
+				dynamicClient: newFakeDynamicClientWithResourcesAndResourcesLists(resources...),
+				resources:     resources,
+				quantifier:    "some",
+				state:         common.StateCreated,
+			},
+			wantErr: true,
+		},
+		{
+			name: "Negative Test: no resources",
+			args: args{
+				dynamicClient: newFakeDynamicClient(),
+				quantifier:    common.QuantifierAll,
+				state:         common.StateDeleted,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.args.w = common.NewWaiterConfig(1, time.Millisecond)
+			if err := ResourcesShouldBeInNamespace(tt.args.dynamicClient, tt.args.resources, tt.args.w, tt.args.quantifier, tt.args.namespace, tt.args.state); (err != nil) != tt.wantErr {
+				t.Errorf("ResourcesShouldBeInNamespace() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestResourceShouldConvergeToSelector(t *testing.T) {
 	type args struct {
 		dynamicClient dynamic.Interface
@@ -519,6 +668,52 @@ func TestResourceShouldConvergeToSelector(t *testing.T) {
 	}
 }
 
+func TestResourceShouldConvergeToSelectorInNamespace(t *testing.T) {
+	type args struct {
+		dynamicClient dynamic.Interface
+		resource      unstructuredResource
+		w             common.WaiterConfig
+		namespace     string
+		selector      string
+	}
+	resource := getResourceFromYaml(t, getFilePath("resource.yaml"))
+	resourceInNamespace := getResourceInNamespace(resource, "someOtherNamespace")
+	labelKey, labelValue := getOneLabel(t, *resource.Resource)
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "Positive Test",
+			args: args{
+				dynamicClient: newFakeDynamicClientWithResource(resourceInNamespace),
+				resource:      resource,
+				namespace:     resourceInNamespace.Resource.GetNamespace(),
+				selector:      ".metadata.labels." + labelKey + "=" + labelValue,
+			},
+		},
+		{
+			name: "Negative Test: only found in manifest namespace",
+			args: args{
+				dynamicClient: newFakeDynamicClientWithResource(resource),
+				resource:      resource,
+				namespace:     resourceInNamespace.Resource.GetNamespace(),
+				selector:      ".metadata.labels." + labelKey + "=" + labelValue,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.args.w = common.NewWaiterConfig(1, time.Millisecond)
+			if err := ResourceShouldConvergeToSelectorInNamespace(tt.args.dynamicClient, tt.args.resource, tt.args.w, tt.args.namespace, tt.args.selector); (err != nil) != tt.wantErr {
+				t.Errorf("ResourceShouldConvergeToSelectorInNamespace() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestResourceShouldConvergeToField(t *testing.T) {
 	type args struct {
 		dynamicClient dynamic.Interface
@@ -602,6 +797,52 @@ func TestResourceShouldConvergeToField(t *testing.T) {
 	}
 }
 
+func TestResourceShouldConvergeToFieldInNamespace(t *testing.T) {
+	type args struct {
+		dynamicClient dynamic.Interface
+		resource      unstructuredResource
+		w             common.WaiterConfig
+		namespace     string
+		selector      string
+	}
+	resource := getResourceFromYaml(t, getFilePath("resource.yaml"))
+	resourceInNamespace := getResourceInNamespace(resource, "someOtherNamespace")
+	labelKey, labelValue := getOneLabel(t, *resource.Resource)
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "Positive Test",
+			args: args{
+				dynamicClient: newFakeDynamicClientWithResource(resourceInNamespace),
+				resource:      resource,
+				namespace:     resourceInNamespace.Resource.GetNamespace(),
+				selector:      ".metadata.labels." + labelKey + "=" + labelValue,
+			},
+		},
+		{
+			name: "Negative Test: only found in manifest namespace",
+			args: args{
+				dynamicClient: newFakeDynamicClientWithResource(resource),
+				resource:      resource,
+				namespace:     resourceInNamespace.Resource.GetNamespace(),
+				selector:      ".metadata.labels." + labelKey + "=" + labelValue,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.args.w = common.NewWaiterConfig(1, time.Millisecond)
+			if err := ResourceShouldConvergeToFieldInNamespace(tt.args.dynamicClient, tt.args.resource, tt.args.w, tt.args.namespace, tt.args.selector); (err != nil) != tt.wantErr {
+				t.Errorf("ResourceShouldConvergeToFieldInNamespace() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestResourceConditionShouldBe(t *testing.T) {
 	type args struct {
 		dynamicClient  dynamic.Interface
@@ -664,6 +905,117 @@ func TestResourceConditionShouldBe(t *testing.T) {
 	}
 }
 
+func TestResourceConditionShouldBeInNamespace(t *testing.T) {
+	type args struct {
+		dynamicClient  dynamic.Interface
+		resource       unstructuredResource
+		w              common.WaiterConfig
+		namespace      string
+		conditionType  string
+		conditionValue string
+	}
+	resource := getResourceFromYaml(t, getFilePath("resource.yaml"))
+	resourceInNamespace := getResourceInNamespace(resource, "someOtherNamespace")
+	conditionType, conditionStatus := getOneCondition(t, *resource.Resource)
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "Positive Test",
+			args: args{
+				dynamicClient:  newFakeDynamicClientWithResource(resourceInNamespace),
+				resource:       resource,
+				namespace:      resourceInNamespace.Resource.GetNamespace(),
+				conditionType:  conditionType,
+				conditionValue: conditionStatus,
+			},
+		},
+		{
+			name: "Negative Test: only found in manifest namespace",
+			args: args{
+				dynamicClient:  newFakeDynamicClientWithResource(resource),
+				resource:       resource,
+				namespace:      resourceInNamespace.Resource.GetNamespace(),
+				conditionType:  conditionType,
+				conditionValue: conditionStatus,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.args.w = common.NewWaiterConfig(1, time.Millisecond)
+			if err := ResourceConditionShouldBeInNamespace(tt.args.dynamicClient, tt.args.resource, tt.args.w, tt.args.namespace, tt.args.conditionType, tt.args.conditionValue); (err != nil) != tt.wantErr {
+				t.Errorf("ResourceConditionShouldBeInNamespace() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestResourcesConditionShouldBeInNamespace(t *testing.T) {
+	type args struct {
+		dynamicClient  dynamic.Interface
+		resources      []unstructuredResource
+		w              common.WaiterConfig
+		quantifier     string
+		conditionType  string
+		conditionValue string
+	}
+	resources := getResourcesFromYaml(t, getFilePath("multi-resource.yaml"))
+	conditionType, conditionStatus := getOneCondition(t, *resources[0].Resource)
+	unmetResource := getResourceInNamespace(resources[1], resources[1].Resource.GetNamespace())
+	if err := unstructured.SetNestedSlice(unmetResource.Resource.Object, []interface{}{}, "status", "conditions"); err != nil {
+		t.Error(err)
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "Positive Test: all",
+			args: args{
+				dynamicClient:  newFakeDynamicClientWithResourcesAndResourcesLists(resources...),
+				resources:      resources,
+				quantifier:     common.QuantifierAll,
+				conditionType:  conditionType,
+				conditionValue: conditionStatus,
+			},
+		},
+		{
+			name: "Positive Test: any",
+			args: args{
+				dynamicClient:  newFakeDynamicClientWithResourcesAndResourcesLists(resources[0], unmetResource),
+				resources:      resources,
+				quantifier:     common.QuantifierAny,
+				conditionType:  conditionType,
+				conditionValue: conditionStatus,
+			},
+		},
+		{
+			name: "Negative Test: waiter timed out, not all meet condition",
+			args: args{
+				dynamicClient:  newFakeDynamicClientWithResourcesAndResourcesLists(resources[0], unmetResource),
+				resources:      resources,
+				quantifier:     common.QuantifierAll,
+				conditionType:  conditionType,
+				conditionValue: conditionStatus,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.args.w = common.NewWaiterConfig(1, time.Millisecond)
+			if err := ResourcesConditionShouldBeInNamespace(tt.args.dynamicClient, tt.args.resources, tt.args.w, tt.args.quantifier, "", tt.args.conditionType, tt.args.conditionValue); (err != nil) != tt.wantErr {
+				t.Errorf("ResourcesConditionShouldBeInNamespace() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestUpdateResourceWithField(t *testing.T) {
 	type args struct {
 		dynamicClient dynamic.Interface
@@ -687,185 +1039,953 @@ func TestUpdateResourceWithField(t *testing.T) {
 			},
 		},
 		{
-			name: "Positive Test: integer value",
+			name: "Positive Test: integer value",
+			args: args{
+				dynamicClient: newFakeDynamicClientWithResource(resource),
+				resource:      resource,
+				key:           ".metadata.labels.someNewIntegerLabelKey",
+				value:         "1",
+			},
+		},
+		{
+			name: "Negative Test: invalid client",
+			args: args{
+				dynamicClient: nil,
+			},
+			wantErr: true,
+		},
+		{
+			name: "Negative Test: 'Get' call fails",
+			args: args{
+				dynamicClient: newFakeDynamicClient(),
+				resource:      resource,
+				key:           ".metadata.labels.someNewLabelKey",
+				value:         "someNewLabelValue",
+			},
+			wantErr: true,
+		},
+		{
+			name: "Negative Test: 'Update' call fails",
+			args: args{
+				dynamicClient: newFakeDynamicClientWithReactors(
+					&kTesting.SimpleReactor{
+						Verb:     "get",
+						Resource: resource.Resource.GetName(),
+						Reaction: newReactionFunc(),
+					},
+					&kTesting.SimpleReactor{
+						Verb:     "update",
+						Resource: resource.Resource.GetName(),
+						Reaction: newReactionFuncWithError(errors.New("an error")),
+					},
+				),
+				resource: resource,
+				key:      ".metadata.labels.someNewLabelKey",
+				value:    "someNewLabelValue",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := UpdateResourceWithField(tt.args.dynamicClient, tt.args.resource, tt.args.key, tt.args.value); (err != nil) != tt.wantErr {
+				t.Errorf("UpdateResourceWithField() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUpdateResourceWithFieldInNamespace(t *testing.T) {
+	type args struct {
+		dynamicClient dynamic.Interface
+		resource      unstructuredResource
+		namespace     string
+		key           string
+		value         string
+	}
+	resource := getResourceFromYaml(t, getFilePath("resource.yaml"))
+	resourceInNamespace := getResourceInNamespace(resource, "someOtherNamespace")
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "Positive Test",
+			args: args{
+				dynamicClient: newFakeDynamicClientWithResource(resourceInNamespace),
+				resource:      resource,
+				namespace:     resourceInNamespace.Resource.GetNamespace(),
+				key:           ".metadata.labels.someNewLabelKey",
+				value:         "someNewLabelValue",
+			},
+		},
+		{
+			name: "Negative Test: only found in manifest namespace",
+			args: args{
+				dynamicClient: newFakeDynamicClientWithResource(resource),
+				resource:      resource,
+				namespace:     resourceInNamespace.Resource.GetNamespace(),
+				key:           ".metadata.labels.someNewLabelKey",
+				value:         "someNewLabelValue",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := UpdateResourceWithFieldInNamespace(tt.args.dynamicClient, tt.args.resource, tt.args.namespace, tt.args.key, tt.args.value); (err != nil) != tt.wantErr {
+				t.Errorf("UpdateResourceWithFieldInNamespace() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDeleteResourcesAtPath(t *testing.T) {
+	type args struct {
+		dynamicClient     dynamic.Interface
+		dc                discovery.DiscoveryInterface
+		TemplateArguments interface{}
+		w                 common.WaiterConfig
+		resourcesPath     string
+	}
+
+	resourcePath := getFilePath("resource.yaml")
+	resource := getResourceFromYaml(t, resourcePath)
+	client := newFakeDynamicClientWithResourceList(resource)
+	clientWithResource := newFakeDynamicClientWithResourcesAndResourcesLists(resource)
+
+	resourcesPath := getFilePath("multi-resource.yaml")
+	resources := getResourcesFromYaml(t, resourcesPath)
+	clientWithResources := newFakeDynamicClientWithResourcesAndResourcesLists(resources...)
+	clientWithResourcesInTestDir := newFakeDynamicClientWithResourcesInDir(t, getTestFilesDirPath())
+
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "Positive Test: delete resource in directory",
+			args: args{
+				dynamicClient: clientWithResourcesInTestDir,
+				dc:            newFakeDiscoveryClient(&clientWithResourcesInTestDir.Fake),
+				resourcesPath: getTestFilesDirPath(),
+			},
+		},
+		{
+			name: "Positive Test: delete resource",
+			args: args{
+				dynamicClient: clientWithResource,
+				dc:            newFakeDiscoveryClient(&clientWithResource.Fake),
+				resourcesPath: resourcePath,
+			},
+		},
+		{
+			name: "Positive Test: already deleted",
+			args: args{
+				dynamicClient: client,
+				dc:            newFakeDiscoveryClient(&client.Fake),
+				resourcesPath: resourcePath,
+			},
+		},
+		{
+			name: "Positive Test: delete multiple resources",
+			args: args{
+				dynamicClient: clientWithResources,
+				dc:            newFakeDiscoveryClient(&clientWithResources.Fake),
+				resourcesPath: resourcesPath,
+			},
+		},
+		{
+			name: "Negative Test: invalid client",
+			args: args{
+				dynamicClient: nil,
+			},
+			wantErr: true,
+		},
+		{
+			name: "Negative Test: 'GetResources' fails",
+			args: args{
+				dynamicClient: client,
+				dc:            nil,
+				resourcesPath: resourcePath,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.args.w = common.NewWaiterConfig(1, time.Second)
+			if err := DeleteResourcesAtPath(tt.args.dynamicClient, tt.args.dc, tt.args.TemplateArguments, tt.args.w, tt.args.resourcesPath); (err != nil) != tt.wantErr {
+				t.Errorf("DeleteResourcesAtPath() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestResourcesAtPathOperation(t *testing.T) {
+	type args struct {
+		dynamicClient     *fakeDynamic.FakeDynamicClient
+		dc                discovery.DiscoveryInterface
+		TemplateArguments interface{}
+		w                 common.WaiterConfig
+		resourcesPath     string
+		operation         string
+		defaultNamespace  string
+	}
+
+	resources := getDirectoryResources(t)
+	servedResources := []unstructuredResource{}
+	for _, resource := range resources {
+		if resource.Resource.GetKind() != "SomeCustomKind" {
+			servedResources = append(servedResources, resource)
+		}
+	}
+	client := newFakeDynamicClientWithResourcesLists(resources...)
+	clientWithResources := newFakeDynamicClientWithResourcesAndResourcesLists(resources...)
+	clientWithServedResources := newFakeDynamicClientWithResourcesAndResourcesLists(servedResources...)
+	clientWithNotEstablishedDefinition := newFakeDynamicClientWithResourcesLists(resources...)
+	clientWithNotEstablishedDefinition.PrependReactor("get", "somecustomkinds.some.group", func(action kTesting.Action) (bool, runtime.Object, error) {
+		definition := &unstructured.Unstructured{}
+		definition.SetName("somecustomkinds.some.group")
+		return true, definition, nil
+	})
+
+	tests := []struct {
+		name      string
+		args      args
+		wantVerb  string
+		wantNames []string
+		wantErr   bool
+	}{
+		{
+			name: "Positive Test: create in install order",
+			args: args{
+				dynamicClient: client,
+				dc:            newFakeDiscoveryClient(&client.Fake),
+				resourcesPath: getTestDirectoryPath(),
+				operation:     common.OperationCreate,
+			},
+			wantVerb:  "create",
+			wantNames: []string{"someTestNamespace", "somecustomkinds.some.group", "someRole", "someDeployment", "someCustomResource"},
+		},
+		{
+			name: "Positive Test: delete in reverse install order",
+			args: args{
+				dynamicClient: clientWithResources,
+				dc:            newFakeDiscoveryClient(&clientWithResources.Fake),
+				resourcesPath: getTestDirectoryPath(),
+				operation:     common.OperationDelete,
+			},
+			wantVerb:  "delete",
+			wantNames: []string{"someCustomResource", "someDeployment", "someRole", "somecustomkinds.some.group", "someTestNamespace"},
+		},
+		{
+			name: "Positive Test: delete skips kinds no longer served",
+			args: args{
+				dynamicClient: clientWithServedResources,
+				dc:            newFakeDiscoveryClient(&clientWithServedResources.Fake),
+				resourcesPath: getTestDirectoryPath(),
+				operation:     common.OperationDelete,
+			},
+			wantVerb:  "delete",
+			wantNames: []string{"someDeployment", "someRole", "somecustomkinds.some.group", "someTestNamespace"},
+		},
+		{
+			name: "Negative Test: CustomResourceDefinition not established",
+			args: args{
+				dynamicClient: clientWithNotEstablishedDefinition,
+				dc:            newFakeDiscoveryClient(&clientWithNotEstablishedDefinition.Fake),
+				resourcesPath: getTestDirectoryPath(),
+				operation:     common.OperationCreate,
+			},
+			wantErr: true,
+		},
+		{
+			name: "Negative Test: invalid client",
+			args: args{
+				dynamicClient: nil,
+				operation:     common.OperationCreate,
+			},
+			wantErr: true,
+		},
+		{
+			name: "Negative Test: path does not exist",
+			args: args{
+				dynamicClient: client,
+				dc:            newFakeDiscoveryClient(&client.Fake),
+				resourcesPath: filepath.Join(getTestDirectoryPath(), "missing"),
+				operation:     common.OperationCreate,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.args.w = common.NewWaiterConfig(1, time.Millisecond)
+			var dynamicClient dynamic.Interface
+			if tt.args.dynamicClient != nil {
+				dynamicClient = tt.args.dynamicClient
+			}
+			if err := ResourcesAtPathOperation(dynamicClient, tt.args.dc, tt.args.TemplateArguments, tt.args.w, tt.args.resourcesPath, tt.args.operation, tt.args.defaultNamespace); (err != nil) != tt.wantErr {
+				t.Errorf("ResourcesAtPathOperation() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if gotNames := getActionNames(tt.args.dynamicClient.Actions(), tt.wantVerb); !reflect.DeepEqual(gotNames, tt.wantNames) {
+				t.Errorf("ResourcesAtPathOperation() %s order = %v, want %v", tt.wantVerb, gotNames, tt.wantNames)
+			}
+		})
+	}
+}
+
+func TestSortByInstallOrder(t *testing.T) {
+	newManifest := func(kind, name string) *unstructured.Unstructured {
+		manifest := &unstructured.Unstructured{}
+		manifest.SetKind(kind)
+		manifest.SetName(name)
+		return manifest
+	}
+	manifests := []*unstructured.Unstructured{
+		newManifest("SomeCustomKind", "custom"),
+		newManifest("Deployment", "first"),
+		newManifest("CustomResourceDefinition", "definition"),
+		newManifest("Deployment", "second"),
+		newManifest("Namespace", "namespace"),
+	}
+
+	sortByInstallOrder(manifests)
+	wantNames := []string{"namespace", "definition", "first", "second", "custom"}
+	if gotNames := getManifestNames(manifests); !reflect.DeepEqual(gotNames, wantNames) {
+		t.Errorf("sortByInstallOrder() = %v, want %v", gotNames, wantNames)
+	}
+
+	sortByUninstallOrder(manifests)
+	wantNames = []string{"custom", "second", "first", "definition", "namespace"}
+	if gotNames := getManifestNames(manifests); !reflect.DeepEqual(gotNames, wantNames) {
+		t.Errorf("sortByUninstallOrder() = %v, want %v", gotNames, wantNames)
+	}
+}
+
+func TestCustomResourceDefinitionShouldBeEstablished(t *testing.T) {
+	type args struct {
+		dynamicClient dynamic.Interface
+		w             common.WaiterConfig
+		name          string
+	}
+
+	definition := getResourceFromYaml(t, filepath.Join(getTestDirectoryPath(), "crd.json"))
+	clientWithEstablishedDefinition := newFakeDynamicClient()
+	_ = clientWithEstablishedDefinition.Tracker().Create(customResourceDefinitionMapping.Resource, definition.Resource, "")
+
+	definitionNamesNotAccepted := definition.Resource.DeepCopy()
+	_ = unstructured.SetNestedSlice(definitionNamesNotAccepted.Object, []interface{}{
+		map[string]interface{}{"type": "Established", "status": "True"},
+		map[string]interface{}{"type": "NamesAccepted", "status": "False"},
+	}, "status", "conditions")
+	clientWithDefinitionNamesNotAccepted := newFakeDynamicClient()
+	_ = clientWithDefinitionNamesNotAccepted.Tracker().Create(customResourceDefinitionMapping.Resource, definitionNamesNotAccepted, "")
+
+	clientWithDefinitionCreatedLater := newFakeDynamicClient()
+	_ = clientWithDefinitionCreatedLater.Tracker().Create(customResourceDefinitionMapping.Resource, definition.Resource, "")
+	var gets int
+	clientWithDefinitionCreatedLater.PrependReactor("get", customResourceDefinitionMapping.Resource.Resource, func(action kTesting.Action) (bool, runtime.Object, error) {
+		gets++
+		if gets == 1 {
+			return true, nil, kerrors.NewNotFound(customResourceDefinitionMapping.Resource.GroupResource(), definition.Resource.GetName())
+		}
+		return false, nil, nil
+	})
+
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "Positive Test: established",
+			args: args{
+				dynamicClient: clientWithEstablishedDefinition,
+				name:          definition.Resource.GetName(),
+			},
+		},
+		{
+			name: "Positive Test: established once created",
+			args: args{
+				dynamicClient: clientWithDefinitionCreatedLater,
+				name:          definition.Resource.GetName(),
+			},
+		},
+		{
+			name: "Negative Test: names not accepted",
+			args: args{
+				dynamicClient: clientWithDefinitionNamesNotAccepted,
+				name:          definition.Resource.GetName(),
+			},
+			wantErr: true,
+		},
+		{
+			name: "Negative Test: not found",
+			args: args{
+				dynamicClient: newFakeDynamicClient(),
+				name:          definition.Resource.GetName(),
+			},
+			wantErr: true,
+		},
+		{
+			name: "Negative Test: invalid client",
+			args: args{
+				dynamicClient: nil,
+				name:          definition.Resource.GetName(),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.args.w = common.NewWaiterConfig(3, time.Millisecond)
+			if err := CustomResourceDefinitionShouldBeEstablished(tt.args.dynamicClient, tt.args.w, tt.args.name); (err != nil) != tt.wantErr {
+				t.Errorf("CustomResourceDefinitionShouldBeEstablished() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestVerifyInstanceGroups(t *testing.T) {
+	type args struct {
+		dynamicClient dynamic.Interface
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "Positive Test: .status.currentState=Ready",
+			args: args{
+				dynamicClient: newFakeDynamicClientWithCustomListKinds(
+					getInstanceGroupFromYaml(t,
+						getFilePath("instance-group.yaml"),
+					),
+				),
+			},
+		},
+		{
+			name: "Negative Test: .status.currentState=NotReady",
+			args: args{
+				dynamicClient: newFakeDynamicClientWithCustomListKinds(
+					getInstanceGroupFromYaml(t,
+						getFilePath("instance-group-not-ready.yaml"),
+					),
+				),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := VerifyInstanceGroups(tt.args.dynamicClient); (err != nil) != tt.wantErr {
+				t.Errorf("VerifyInstanceGroups() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDeleteResourcesWithSelector(t *testing.T) {
+	type args struct {
+		dynamicClient dynamic.Interface
+		mapping       *meta.RESTMapping
+		namespace     string
+		selectorType  string
+		selector      string
+	}
+	resource := getResourceFromYaml(t, getFilePath("resource.yaml"))
+	labelKey, labelValue := getOneLabel(t, *resource.Resource)
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "Positive Test: resources matched",
+			args: args{
+				dynamicClient: newFakeDynamicClientWithResourceAndCustomListKinds(resource),
+				mapping:       resource.GVR,
+				namespace:     resource.Resource.GetNamespace(),
+				selectorType:  selectorTypeDefault,
+				selector:      labelKey + "=" + labelValue,
+			},
+		},
+		{
+			name: "Positive Test: no resources matched",
+			args: args{
+				dynamicClient: newFakeDynamicClientWithResourceAndCustomListKinds(resource),
+				mapping:       resource.GVR,
+				namespace:     resource.Resource.GetNamespace(),
+				selectorType:  selectorTypeLabel,
+				selector:      labelKey + "=not-" + labelValue,
+			},
+		},
+		{
+			name: "Negative Test: invalid selector type",
+			args: args{
+				dynamicClient: newFakeDynamicClientWithResourceAndCustomListKinds(resource),
+				mapping:       resource.GVR,
+				namespace:     resource.Resource.GetNamespace(),
+				selectorType:  "invalid-selector",
+				selector:      labelKey + "=" + labelValue,
+			},
+			wantErr: true,
+		},
+		{
+			name: "Negative Test: invalid client",
+			args: args{
+				dynamicClient: nil,
+				mapping:       resource.GVR,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := DeleteResourcesWithSelector(tt.args.dynamicClient, tt.args.mapping, tt.args.namespace, tt.args.selectorType, tt.args.selector); (err != nil) != tt.wantErr {
+				t.Errorf("DeleteResourcesWithSelector() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestResourcesWithSelectorCountShouldBe(t *testing.T) {
+	type args struct {
+		dynamicClient dynamic.Interface
+		mapping       *meta.RESTMapping
+		namespace     string
+		selectorType  string
+		selector      string
+		expectedCount int
+	}
+	resource := getResourceFromYaml(t, getFilePath("resource.yaml"))
+	labelKey, labelValue := getOneLabel(t, *resource.Resource)
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "Positive Test: count matches",
+			args: args{
+				dynamicClient: newFakeDynamicClientWithResourceAndCustomListKinds(resource),
+				mapping:       resource.GVR,
+				namespace:     resource.Resource.GetNamespace(),
+				selectorType:  selectorTypeLabel,
+				selector:      labelKey + "=" + labelValue,
+				expectedCount: 1,
+			},
+		},
+		{
+			name: "Positive Test: no resources expected",
+			args: args{
+				dynamicClient: newFakeDynamicClientWithResourceAndCustomListKinds(resource),
+				mapping:       resource.GVR,
+				namespace:     resource.Resource.GetNamespace(),
+				selectorType:  selectorTypeLabel,
+				selector:      labelKey + "=not-" + labelValue,
+				expectedCount: 0,
+			},
+		},
+		{
+			name: "Negative Test: count does not match",
+			args: args{
+				dynamicClient: newFakeDynamicClientWithResourceAndCustomListKinds(resource),
+				mapping:       resource.GVR,
+				namespace:     resource.Resource.GetNamespace(),
+				selectorType:  selectorTypeLabel,
+				selector:      labelKey + "=" + labelValue,
+				expectedCount: 2,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ResourcesWithSelectorCountShouldBe(tt.args.dynamicClient, tt.args.mapping, tt.args.namespace, tt.args.selectorType, tt.args.selector, tt.args.expectedCount); (err != nil) != tt.wantErr {
+				t.Errorf("ResourcesWithSelectorCountShouldBe() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestResourceShouldHaveMetadata(t *testing.T) {
+	type args struct {
+		name         string
+		metadataType string
+		expected     string
+	}
+	resource := getResourceFromYaml(t, getFilePath("resource.yaml"))
+	resource.Resource.SetAnnotations(map[string]string{"team": "platform"})
+	labelKey, labelValue := getOneLabel(t, *resource.Resource)
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "Positive Test: labels with set-based requirements",
+			args: args{
+				name:         resource.Resource.GetName(),
+				metadataType: metadataLabels,
+				expected:     fmt.Sprintf("%s in (%s,other),!missing", labelKey, labelValue),
+			},
+		},
+		{
+			name: "Positive Test: annotations",
+			args: args{
+				name:         resource.Resource.GetName(),
+				metadataType: metadataAnnotations,
+				expected:     "team=platform",
+			},
+		},
+		{
+			name: "Negative Test: label expected to be absent",
+			args: args{
+				name:         resource.Resource.GetName(),
+				metadataType: metadataLabels,
+				expected:     "!" + labelKey,
+			},
+			wantErr: true,
+		},
+		{
+			name: "Negative Test: malformed expectation",
+			args: args{
+				name:         resource.Resource.GetName(),
+				metadataType: metadataAnnotations,
+				expected:     "team in platform",
+			},
+			wantErr: true,
+		},
+		{
+			name: "Negative Test: resource not found",
+			args: args{
+				name:         "not-" + resource.Resource.GetName(),
+				metadataType: metadataLabels,
+				expected:     labelKey,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dynamicClient := newFakeDynamicClientWithResourceAndCustomListKinds(resource)
+			if err := ResourceShouldHaveMetadata(dynamicClient, resource.GVR, tt.args.name, resource.Resource.GetNamespace(), tt.args.metadataType, tt.args.expected); (err != nil) != tt.wantErr {
+				t.Errorf("ResourceShouldHaveMetadata() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestResourcesWithSelectorShouldHaveMetadata(t *testing.T) {
+	type args struct {
+		selector     string
+		metadataType string
+		expected     string
+	}
+	resource := getResourceFromYaml(t, getFilePath("resource.yaml"))
+	resource.Resource.SetAnnotations(map[string]string{"team": "platform"})
+	labelKey, labelValue := getOneLabel(t, *resource.Resource)
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "Positive Test: annotations not in set",
+			args: args{
+				selector:     labelKey + "=" + labelValue,
+				metadataType: metadataAnnotations,
+				expected:     "team notin (data,ml)",
+			},
+		},
+		{
+			name: "Negative Test: annotation value differs",
+			args: args{
+				selector:     labelKey + "=" + labelValue,
+				metadataType: metadataAnnotations,
+				expected:     "team=data",
+			},
+			wantErr: true,
+		},
+		{
+			name: "Negative Test: no resources matched selector",
+			args: args{
+				selector:     labelKey + "=not-" + labelValue,
+				metadataType: metadataLabels,
+				expected:     labelKey,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dynamicClient := newFakeDynamicClientWithResourceAndCustomListKinds(resource)
+			if err := ResourcesWithSelectorShouldHaveMetadata(dynamicClient, resource.GVR, resource.Resource.GetNamespace(), selectorTypeLabel, tt.args.selector, tt.args.metadataType, tt.args.expected); (err != nil) != tt.wantErr {
+				t.Errorf("ResourcesWithSelectorShouldHaveMetadata() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestResourcesWithSelectorShouldReachCount(t *testing.T) {
+	type args struct {
+		dynamicClient dynamic.Interface
+		mapping       *meta.RESTMapping
+		w             common.WaiterConfig
+		namespace     string
+		selectorType  string
+		selector      string
+		comparison    string
+		expectedCount int
+	}
+	resource := getResourceFromYaml(t, getFilePath("resource.yaml"))
+	labelKey, labelValue := getOneLabel(t, *resource.Resource)
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "Positive Test: at least 1 resource",
+			args: args{
+				dynamicClient: newFakeDynamicClientWithResourceAndCustomListKinds(resource),
+				mapping:       resource.GVR,
+				namespace:     resource.Resource.GetNamespace(),
+				selectorType:  selectorTypeLabel,
+				selector:      labelKey + "=" + labelValue,
+				comparison:    common.ComparisonAtLeast,
+				expectedCount: 1,
+			},
+		},
+		{
+			name: "Negative Test: exactly 2 resources",
+			args: args{
+				dynamicClient: newFakeDynamicClientWithResourceAndCustomListKinds(resource),
+				mapping:       resource.GVR,
+				namespace:     resource.Resource.GetNamespace(),
+				selectorType:  selectorTypeLabel,
+				selector:      labelKey + "=" + labelValue,
+				comparison:    common.ComparisonExactly,
+				expectedCount: 2,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.args.w = common.NewWaiterConfig(1, time.Second)
+			if err := ResourcesWithSelectorShouldReachCount(tt.args.dynamicClient, tt.args.mapping, tt.args.w, tt.args.namespace, tt.args.selectorType, tt.args.selector, tt.args.comparison, tt.args.expectedCount); (err != nil) != tt.wantErr {
+				t.Errorf("ResourcesWithSelectorShouldReachCount() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestResourcesWithSelectorShouldConvergeToField(t *testing.T) {
+	type args struct {
+		dynamicClient dynamic.Interface
+		mapping       *meta.RESTMapping
+		w             common.WaiterConfig
+		namespace     string
+		selectorType  string
+		selector      string
+		fieldSelector string
+	}
+	resource := getResourceFromYaml(t, getFilePath("resource.yaml"))
+	labelKey, labelValue := getOneLabel(t, *resource.Resource)
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "Positive Test: all resources converged",
 			args: args{
-				dynamicClient: newFakeDynamicClientWithResource(resource),
-				resource:      resource,
-				key:           ".metadata.labels.someNewIntegerLabelKey",
-				value:         "1",
+				dynamicClient: newFakeDynamicClientWithResourceAndCustomListKinds(resource),
+				mapping:       resource.GVR,
+				namespace:     resource.Resource.GetNamespace(),
+				selectorType:  selectorTypeLabel,
+				selector:      labelKey + "=" + labelValue,
+				fieldSelector: "status.replicaCount=2",
 			},
 		},
 		{
-			name: "Negative Test: invalid client",
+			name: "Negative Test: resources did not converge",
 			args: args{
-				dynamicClient: nil,
+				dynamicClient: newFakeDynamicClientWithResourceAndCustomListKinds(resource),
+				mapping:       resource.GVR,
+				namespace:     resource.Resource.GetNamespace(),
+				selectorType:  selectorTypeLabel,
+				selector:      labelKey + "=" + labelValue,
+				fieldSelector: "status.replicaCount=3",
 			},
 			wantErr: true,
 		},
 		{
-			name: "Negative Test: 'Get' call fails",
+			name: "Negative Test: no resources matched",
 			args: args{
-				dynamicClient: newFakeDynamicClient(),
-				resource:      resource,
-				key:           ".metadata.labels.someNewLabelKey",
-				value:         "someNewLabelValue",
+				dynamicClient: newFakeDynamicClientWithResourceAndCustomListKinds(resource),
+				mapping:       resource.GVR,
+				namespace:     resource.Resource.GetNamespace(),
+				selectorType:  selectorTypeLabel,
+				selector:      labelKey + "=not-" + labelValue,
+				fieldSelector: "status.replicaCount=2",
 			},
 			wantErr: true,
 		},
 		{
-			name: "Negative Test: 'Update' call fails",
+			name: "Negative Test: invalid field selector",
 			args: args{
-				dynamicClient: newFakeDynamicClientWithReactors(
-					&kTesting.SimpleReactor{
-						Verb:     "get",
-						Resource: resource.Resource.GetName(),
-						Reaction: newReactionFunc(),
-					},
-					&kTesting.SimpleReactor{
-						Verb:     "update",
-						Resource: resource.Resource.GetName(),
-						Reaction: newReactionFuncWithError(errors.New("an error")),
-					},
-				),
-				resource: resource,
-				key:      ".metadata.labels.someNewLabelKey",
-				value:    "someNewLabelValue",
+				dynamicClient: newFakeDynamicClientWithResourceAndCustomListKinds(resource),
+				mapping:       resource.GVR,
+				namespace:     resource.Resource.GetNamespace(),
+				selectorType:  selectorTypeLabel,
+				selector:      labelKey + "=" + labelValue,
+				fieldSelector: "status.replicaCount",
 			},
 			wantErr: true,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if err := UpdateResourceWithField(tt.args.dynamicClient, tt.args.resource, tt.args.key, tt.args.value); (err != nil) != tt.wantErr {
-				t.Errorf("UpdateResourceWithField() error = %v, wantErr %v", err, tt.wantErr)
+			tt.args.w = common.NewWaiterConfig(1, time.Second)
+			if err := ResourcesWithSelectorShouldConvergeToField(tt.args.dynamicClient, tt.args.mapping, tt.args.w, tt.args.namespace, tt.args.selectorType, tt.args.selector, tt.args.fieldSelector); (err != nil) != tt.wantErr {
+				t.Errorf("ResourcesWithSelectorShouldConvergeToField() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
 	}
 }
 
-func TestDeleteResourcesAtPath(t *testing.T) {
+func TestGetResourceMapping(t *testing.T) {
 	type args struct {
-		dynamicClient     dynamic.Interface
-		dc                discovery.DiscoveryInterface
-		TemplateArguments interface{}
-		w                 common.WaiterConfig
-		resourcesPath     string
+		dc   discovery.DiscoveryInterface
+		kind string
 	}
-
-	resourcePath := getFilePath("resource.yaml")
-	resource := getResourceFromYaml(t, resourcePath)
-	client := newFakeDynamicClientWithResourceList(resource)
-	clientWithResource := newFakeDynamicClientWithResourcesAndResourcesLists(resource)
-
-	resourcesPath := getFilePath("multi-resource.yaml")
-	resources := getResourcesFromYaml(t, resourcesPath)
-	clientWithResources := newFakeDynamicClientWithResourcesAndResourcesLists(resources...)
-	clientWithResourcesInTestDir := newFakeDynamicClientWithResourcesInDir(t, getTestFilesDirPath())
-
+	resource := getResourceFromYaml(t, getFilePath("resource.yaml"))
 	tests := []struct {
 		name    string
 		args    args
+		want    schema.GroupVersionKind
 		wantErr bool
 	}{
 		{
-			name: "Positive Test: delete resource in directory",
-			args: args{
-				dynamicClient: clientWithResourcesInTestDir,
-				dc:            newFakeDiscoveryClient(&clientWithResourcesInTestDir.Fake),
-				resourcesPath: getTestFilesDirPath(),
-			},
-		},
-		{
-			name: "Positive Test: delete resource",
-			args: args{
-				dynamicClient: clientWithResource,
-				dc:            newFakeDiscoveryClient(&clientWithResource.Fake),
-				resourcesPath: resourcePath,
-			},
-		},
-		{
-			name: "Positive Test: already deleted",
-			args: args{
-				dynamicClient: client,
-				dc:            newFakeDiscoveryClient(&client.Fake),
-				resourcesPath: resourcePath,
-			},
-		},
-		{
-			name: "Positive Test: delete multiple resources",
+			name: "Positive Test: kind",
 			args: args{
-				dynamicClient: clientWithResources,
-				dc:            newFakeDiscoveryClient(&clientWithResources.Fake),
-				resourcesPath: resourcesPath,
+				dc:   newFakeDiscoveryClient(&newFakeDynamicClientWithResourceList(resource).Fake),
+				kind: resource.Resource.GetKind(),
 			},
+			want: resource.GVR.GroupVersionKind,
 		},
 		{
-			name: "Negative Test: invalid client",
+			name: "Negative Test: unknown kind",
 			args: args{
-				dynamicClient: nil,
+				dc:   newFakeDiscoveryClient(&newFakeDynamicClientWithResourceList(resource).Fake),
+				kind: "unknownKind",
 			},
 			wantErr: true,
 		},
 		{
-			name: "Negative Test: 'GetResources' fails",
+			name: "Negative Test: invalid discovery client",
 			args: args{
-				dynamicClient: client,
-				dc:            nil,
-				resourcesPath: resourcePath,
+				dc:   nil,
+				kind: resource.Resource.GetKind(),
 			},
 			wantErr: true,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			tt.args.w = common.NewWaiterConfig(1, time.Second)
-			if err := DeleteResourcesAtPath(tt.args.dynamicClient, tt.args.dc, tt.args.TemplateArguments, tt.args.w, tt.args.resourcesPath); (err != nil) != tt.wantErr {
-				t.Errorf("DeleteResourcesAtPath() error = %v, wantErr %v", err, tt.wantErr)
+			got, err := GetResourceMapping(tt.args.dc, tt.args.kind)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetResourceMapping() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && !reflect.DeepEqual(got.GroupVersionKind, tt.want) {
+				t.Errorf("GetResourceMapping() = %v, want %v", got.GroupVersionKind, tt.want)
 			}
 		})
 	}
 }
 
-func TestVerifyInstanceGroups(t *testing.T) {
+func TestNewCachedDiscoveryClient(t *testing.T) {
 	type args struct {
-		dynamicClient dynamic.Interface
+		dc discovery.DiscoveryInterface
 	}
+	dc := newFakeDiscoveryClient(&newFakeDynamicClient().Fake)
+	cachedDiscoveryClient := memory.NewMemCacheClient(dc)
 	tests := []struct {
-		name    string
-		args    args
-		wantErr bool
+		name     string
+		args     args
+		wantKept bool
 	}{
 		{
-			name: "Positive Test: .status.currentState=Ready",
+			name: "Positive Test: discovery client is cached",
 			args: args{
-				dynamicClient: newFakeDynamicClientWithCustomListKinds(
-					getInstanceGroupFromYaml(t,
-						getFilePath("instance-group.yaml"),
-					),
-				),
+				dc: dc,
 			},
 		},
 		{
-			name: "Negative Test: .status.currentState=NotReady",
+			name: "Positive Test: cached discovery client is kept",
 			args: args{
-				dynamicClient: newFakeDynamicClientWithCustomListKinds(
-					getInstanceGroupFromYaml(t,
-						getFilePath("instance-group-not-ready.yaml"),
-					),
-				),
+				dc: cachedDiscoveryClient,
 			},
-			wantErr: true,
+			wantKept: true,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if err := VerifyInstanceGroups(tt.args.dynamicClient); (err != nil) != tt.wantErr {
-				t.Errorf("VerifyInstanceGroups() error = %v, wantErr %v", err, tt.wantErr)
+			got := NewCachedDiscoveryClient(tt.args.dc)
+			if kept := got.CachedDiscoveryInterface == tt.args.dc; kept != tt.wantKept {
+				t.Errorf("NewCachedDiscoveryClient() kept = %v, wantKept %v", kept, tt.wantKept)
 			}
 		})
 	}
 }
 
+func TestCachedDiscoveryClient(t *testing.T) {
+	resource := getResourceFromYaml(t, getFilePath("resource.yaml"))
+	client := newFakeDynamicClient()
+	client.Resources = append(client.Resources, newAPIResourceList(schema.GroupVersion{Version: "v1"}, "namespaces", "Namespace", false))
+	dc := NewCachedDiscoveryClient(newFakeDiscoveryClient(&client.Fake))
+
+	if _, err := GetResourceMapping(dc, resource.Resource.GetKind()); err == nil {
+		t.Errorf("GetResourceMapping() error = nil, want an error for a kind not served")
+	}
+
+	// served after the mappings were cached, as when its CustomResourceDefinition is created
+	client.Resources = append(client.Resources, newAPIResourceList(
+		resource.GVR.GroupVersionKind.GroupVersion(),
+		resource.Resource.GetName(),
+		resource.Resource.GetKind(),
+		true,
+	))
+	got, err := getGVR(&resource.GVR.GroupVersionKind, dc)
+	if err != nil {
+		t.Errorf("getGVR() error = %v, want the mappings to be reset", err)
+		return
+	}
+	if !reflect.DeepEqual(got.GroupVersionKind, resource.GVR.GroupVersionKind) {
+		t.Errorf("getGVR() = %v, want %v", got.GroupVersionKind, resource.GVR.GroupVersionKind)
+	}
+
+	discoveryActions := len(client.Actions())
+	if _, err := GetResourceMapping(dc, resource.Resource.GetKind()); err != nil {
+		t.Errorf("GetResourceMapping() error = %v", err)
+	}
+	if len(client.Actions()) != discoveryActions {
+		t.Errorf("GetResourceMapping() made %d discovery calls, want the cached mappings to be used", len(client.Actions())-discoveryActions)
+	}
+}
+
 func TestGetResource(t *testing.T) {
 	type args struct {
 		dc                discovery.DiscoveryInterface
@@ -984,6 +2104,52 @@ func TestGetResources(t *testing.T) {
 	}
 }
 
+func TestSplitManifests(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "Positive Test: separators and empty documents",
+			data: "---\n# comment only\n---\nkind: A\n---\n\n---\nkind: B\n",
+			want: []string{"kind: A\n", "kind: B\n"},
+		},
+		{
+			name: "Positive Test: separator inside block scalar",
+			data: "kind: A\ndata:\n  key: |\n    ---\n    value\n---\nkind: B\n",
+			want: []string{"kind: A\ndata:\n  key: |\n    ---\n    value\n", "kind: B\n"},
+		},
+		{
+			name: "Positive Test: separator with trailing comment",
+			data: "kind: A\n--- # next\nkind: B",
+			want: []string{"kind: A\n", "kind: B\n"},
+		},
+		{
+			name:    "Negative Test: content after separator",
+			data:    "kind: A\n--- kind: B\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := splitManifests([]byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("splitManifests() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			var gotStrings []string
+			for _, manifest := range got {
+				gotStrings = append(gotStrings, string(manifest))
+			}
+			if !reflect.DeepEqual(gotStrings, tt.want) {
+				t.Errorf("splitManifests() = %q, want %q", gotStrings, tt.want)
+			}
+		})
+	}
+}
+
 func TestGetInstanceGroupList(t *testing.T) {
 	type args struct {
 		dynamicClient dynamic.Interface
@@ -1018,6 +2184,145 @@ func TestGetInstanceGroupList(t *testing.T) {
 	}
 }
 
+func TestIsFieldEqual(t *testing.T) {
+	type args struct {
+		keySlice []string
+		value    string
+	}
+	content := map[string]interface{}{
+		"status": map[string]interface{}{
+			"phase":    "Running",
+			"replicas": int64(3),
+			"ratio":    0.5,
+			"ready":    true,
+			"message":  nil,
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Ready"},
+			},
+		},
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    bool
+		wantErr bool
+	}{
+		{
+			name: "Positive Test: string",
+			args: args{keySlice: []string{"status", "phase"}, value: "Running"},
+			want: true,
+		},
+		{
+			name: "Positive Test: int",
+			args: args{keySlice: []string{"status", "replicas"}, value: "3"},
+			want: true,
+		},
+		{
+			name: "Positive Test: float",
+			args: args{keySlice: []string{"status", "ratio"}, value: "0.5"},
+			want: true,
+		},
+		{
+			name: "Positive Test: bool",
+			args: args{keySlice: []string{"status", "ready"}, value: "true"},
+			want: true,
+		},
+		{
+			name: "Positive Test: not equal",
+			args: args{keySlice: []string{"status", "phase"}, value: "Pending"},
+			want: false,
+		},
+		{
+			name: "Positive Test: field not found yet",
+			args: args{keySlice: []string{"status", "startTime"}, value: "someTime"},
+			want: false,
+		},
+		{
+			name: "Positive Test: index not found yet",
+			args: args{keySlice: []string{"status", "conditions[1]", "type"}, value: "Ready"},
+			want: false,
+		},
+		{
+			name: "Positive Test: null field",
+			args: args{keySlice: []string{"status", "message"}, value: "someMessage"},
+			want: false,
+		},
+		{
+			name:    "Negative Test: invalid bool",
+			args:    args{keySlice: []string{"status", "ready"}, value: "someValue"},
+			wantErr: true,
+		},
+		{
+			name:    "Negative Test: unsupported type",
+			args:    args{keySlice: []string{"status", "conditions"}, value: "someValue"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := isFieldEqual(content, tt.args.keySlice, tt.args.value)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("isFieldEqual() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("isFieldEqual() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetDefaultNamespace(t *testing.T) {
+	type args struct {
+		resource  unstructuredResource
+		namespace string
+	}
+	newResource := func(namespace string, scope meta.RESTScope) unstructuredResource {
+		resource := &unstructured.Unstructured{}
+		resource.SetName("resource")
+		resource.SetNamespace(namespace)
+		return unstructuredResource{GVR: &meta.RESTMapping{Scope: scope}, Resource: resource}
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{
+			name: "Positive Test: namespaceless manifest",
+			args: args{
+				resource:  newResource("", meta.RESTScopeNamespace),
+				namespace: "default-namespace",
+			},
+			want: "default-namespace",
+		},
+		{
+			name: "Positive Test: manifest namespace is kept",
+			args: args{
+				resource:  newResource("manifest-namespace", meta.RESTScopeNamespace),
+				namespace: "default-namespace",
+			},
+			want: "manifest-namespace",
+		},
+		{
+			name: "Positive Test: cluster scoped resource",
+			args: args{
+				resource:  newResource("", meta.RESTScopeRoot),
+				namespace: "default-namespace",
+			},
+			want: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetDefaultNamespace(tt.args.resource, tt.args.namespace)
+			if got := tt.args.resource.Resource.GetNamespace(); got != tt.want {
+				t.Errorf("SetDefaultNamespace() namespace = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func templateArgsToMap(t *testing.T, args ...generic.TemplateArgument) map[string]string {
 	argsMap, err := generic.TemplateArgumentsToMap(args...)
 	if err != nil {
@@ -1026,8 +2331,17 @@ func templateArgsToMap(t *testing.T, args ...generic.TemplateArgument) map[strin
 	return argsMap
 }
 
+// generateFileFromTemplate generates the file from a copy of the template, so it is written to a temporary directory
 func generateFileFromTemplate(t *testing.T, templatedFilePath string, templateArgs interface{}) string {
-	generatedPath, err := generic.GenerateFileFromTemplate(templatedFilePath, templateArgs)
+	data, err := os.ReadFile(templatedFilePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	copiedFilePath := filepath.Join(t.TempDir(), filepath.Base(templatedFilePath))
+	if err := os.WriteFile(copiedFilePath, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	generatedPath, err := generic.GenerateFileFromTemplate(copiedFilePath, templateArgs)
 	if err != nil {
 		t.Error(err)
 	}
@@ -1060,6 +2374,43 @@ func getTestFilesDirPath() string {
 	return filepath.Join(getTestDirPath(), "files")
 }
 
+func getTestDirectoryPath() string {
+	return filepath.Join(getTestDirPath(), "directory")
+}
+
+func getDirectoryResources(t *testing.T) []unstructuredResource {
+	resources := getResourcesFromYaml(t, filepath.Join(getTestDirectoryPath(), "namespace.yaml"))
+	resources = append(resources, getResourceFromYaml(t, filepath.Join(getTestDirectoryPath(), "crd.json")))
+	resources = append(resources, getResourceFromYaml(t, filepath.Join(getTestDirectoryPath(), "rbac", "role.yml")))
+	resources = append(resources, getResourceFromYaml(t, filepath.Join(getTestDirectoryPath(), "workloads", "deployment.yaml")))
+	return resources
+}
+
+func getActionNames(actions []kTesting.Action, verb string) []string {
+	var names []string
+	for _, action := range actions {
+		switch a := action.(type) {
+		case kTesting.CreateAction:
+			if verb == "create" {
+				names = append(names, a.GetObject().(*unstructured.Unstructured).GetName())
+			}
+		case kTesting.DeleteAction:
+			if verb == "delete" {
+				names = append(names, a.GetName())
+			}
+		}
+	}
+	return names
+}
+
+func getManifestNames(manifests []*unstructured.Unstructured) []string {
+	var names []string
+	for _, manifest := range manifests {
+		names = append(names, manifest.GetName())
+	}
+	return names
+}
+
 func getFilePath(testFileName string) string {
 	return filepath.Join(getTestFilesDirPath(), testFileName)
 }
@@ -1122,17 +2473,23 @@ func getResourceFromYaml(t *testing.T, resourceFilePath string) unstructuredReso
 	return getResourceFromBytes(t, rawResource)
 }
 
+func getResourceInNamespace(resource unstructuredResource, namespace string) unstructuredResource {
+	resourceInNamespace := unstructuredResource{GVR: resource.GVR, Resource: resource.Resource.DeepCopy()}
+	resourceInNamespace.Resource.SetNamespace(namespace)
+	return resourceInNamespace
+}
+
 func getResourcesFromYaml(t *testing.T, resourcesFilePath string) []unstructuredResource {
 	rawResources, err := os.ReadFile(resourcesFilePath)
 	if err != nil {
 		t.Error(err)
 	}
-	rawResourcesSplit := bytes.Split(rawResources, []byte(yamlSeparator))
+	rawResourcesSplit, err := splitManifests(rawResources)
+	if err != nil {
+		t.Error(err)
+	}
 	resources := make([]unstructuredResource, 0)
 	for _, rawResource := range rawResourcesSplit {
-		if len(bytes.Trim(rawResource, trimTokens)) == 0 {
-			continue
-		}
 		resource := getResourceFromBytes(t, rawResource)
 		resources = append(resources, resource)
 	}
@@ -1158,6 +2515,17 @@ func newFakeDynamicClientWithCustomListKinds(resource unstructuredResource) *fak
 	)
 }
 
+func newFakeDynamicClientWithResourceAndCustomListKinds(resource unstructuredResource) *fakeDynamic.FakeDynamicClient {
+	client := fakeDynamic.NewSimpleDynamicClientWithCustomListKinds(
+		runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{
+			resource.GVR.Resource: resource.Resource.GetKind() + "List",
+		},
+	)
+	_ = client.Tracker().Create(resource.GVR.Resource, resource.Resource, resource.Resource.GetNamespace())
+	return client
+}
+
 func newFakeDynamicClientWithResourceList(resource unstructuredResource) *fakeDynamic.FakeDynamicClient {
 	client := fakeDynamic.NewSimpleDynamicClient(runtime.NewScheme())
 